@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+)
+
+func TestJetStopsAtMountain(t *testing.T) {
+	tm := tilemap.NewTileMap(20, 20)
+	tm.FillRect(10, 0, 12, 19, tilemap.TerrainMountain)
+	m := mech.New(rl.NewVector3(9.5, 0, 5.5), mech.DefaultConfig())
+
+	// A step east that would end on the mountain is undone
+	dt := float32(0.1)
+	m.Velocity = rl.NewVector3(10, 0, 0)
+	m.Position.X += m.Velocity.X * dt
+	if !resolveFlightCollision(m, tm, dt) {
+		t.Fatal("jet flew onto a mountain")
+	}
+	if !tm.IsFlyableAt(m.Position.X, m.Position.Z) {
+		t.Errorf("jet left at %v, over the mountain", m.Position)
+	}
+	if m.Velocity.X != 0 {
+		t.Errorf("jet still heading into the mountain at %v", m.Velocity)
+	}
+
+	// Heading diagonally into it, the jet keeps sliding along its face
+	m.Velocity = rl.NewVector3(10, 0, 10)
+	m.Position.X += m.Velocity.X * dt
+	m.Position.Z += m.Velocity.Z * dt
+	resolveFlightCollision(m, tm, dt)
+	if m.Position.Z != 6.5 || m.Velocity.Z != 10 {
+		t.Errorf("jet stuck at %v rather than sliding along the mountain", m.Position)
+	}
+
+	// In the open there's nothing to resolve
+	m.Position = rl.NewVector3(4.5, 0, 4.5)
+	if resolveFlightCollision(m, tm, dt) {
+		t.Error("pushed back over open ground")
+	}
+
+	// Robot mode walks by its own rules
+	m.Mode = mech.ModeRobot
+	m.Position = rl.NewVector3(10.5, 0, 5.5)
+	if resolveFlightCollision(m, tm, dt) {
+		t.Error("robot-mode mech handled as a jet")
+	}
+}
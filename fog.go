@@ -0,0 +1,87 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// baseSightRange is how far the player's bases see around themselves
+const baseSightRange = 8.0
+
+// sighting is somewhere the player sees from, and how far
+type sighting struct {
+	pos    rl.Vector3
+	radius float32
+}
+
+// updateFog redraws the player's vision for the frame: their mech, units
+// and bases reveal the ground around them, as do allies sharing vision. Sight
+// shrinks at night, the mech's scan doesn't. Then picks out which units in
+// sight the player has spotted.
+func (g *Game) updateFog(dt float32) {
+	g.fog.BeginFrame(dt)
+	scale := g.dayCycle.SightScale()
+
+	var sightings []sighting
+	if !g.playerMech.IsDead() {
+		sightings = append(sightings, sighting{g.playerMech.Position, g.playerMech.Config.SightRange * scale})
+	}
+	if g.playerMech.ScanTimer > 0 {
+		sightings = append(sightings, sighting{g.playerMech.ScanCenter, g.playerMech.Config.ScanRadius})
+	}
+
+	for _, u := range g.unitManager.GetUnits() {
+		if u.IsDead() || u.IsSpawning() || !g.alliances.SharesVision(unit.TeamPlayer, u.Team) {
+			continue
+		}
+		sightings = append(sightings, sighting{u.Position, u.SightRange() * scale})
+	}
+
+	for _, b := range g.baseManager.Bases {
+		if b.Owner == base.OwnerPlayer1 && !b.IsDestroyed() {
+			sightings = append(sightings, sighting{b.Position, baseSightRange * scale})
+		}
+	}
+
+	for _, s := range sightings {
+		g.fog.Reveal(s.pos.X, s.pos.Z, s.radius)
+	}
+	g.spotted = spotUnits(g.unitManager.GetUnits(), sightings, g.fog, g.alliances, g.unitManager.Detectability)
+}
+
+// spotUnits returns the IDs of the units the player can make out. Their own
+// side's and allies sharing vision always are. Others must be in sight, and
+// if concealed, like in forest, also close to where the player sees from:
+// within each sighting's range scaled down by how hard they are to detect.
+// A unit that has just fired gives itself away.
+func spotUnits(units []*unit.Unit, sightings []sighting, fog *tilemap.FogOfWar, alliances *unit.Alliances, detectability func(*unit.Unit) float32) map[uint32]bool {
+	spotted := make(map[uint32]bool)
+	for _, u := range units {
+		if u.IsDead() {
+			continue
+		}
+		if alliances.SharesVision(unit.TeamPlayer, u.Team) {
+			spotted[u.ID] = true
+			continue
+		}
+		if fog != nil && !fog.IsVisibleAt(u.Position) {
+			continue
+		}
+
+		d := detectability(u)
+		if d >= 1 {
+			spotted[u.ID] = true
+			continue
+		}
+		for _, s := range sightings {
+			if u.DistanceToPoint(s.pos) <= s.radius*d {
+				spotted[u.ID] = true
+				break
+			}
+		}
+	}
+	return spotted
+}
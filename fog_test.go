@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestForestHidesUnitsInSight(t *testing.T) {
+	tm := tilemap.NewTileMap(40, 40)
+	tm.FillRect(20, 0, 39, 39, tilemap.TerrainForest)
+	fog := tilemap.NewFogOfWar(tm, tilemap.FogMemoryFull)
+	um := unit.NewManager(10)
+	um.DetectionAt = tm.DetectionModAt
+	alliances := unit.NewAlliances()
+
+	open := unit.New(1, unit.TypeInfantry, unit.TeamEnemy, rl.NewVector3(10.5, 0, 20.5))
+	hidden := unit.New(2, unit.TypeInfantry, unit.TeamEnemy, rl.NewVector3(29.5, 0, 20.5))
+	units := []*unit.Unit{open, hidden}
+
+	// A viewer that sees both tiles, far from each unit
+	viewer := sighting{rl.NewVector3(20, 0, 20.5), 12}
+	fog.BeginFrame(0)
+	fog.Reveal(viewer.pos.X, viewer.pos.Z, viewer.radius)
+	if !fog.IsVisibleAt(hidden.Position) {
+		t.Fatal("forest unit's tile isn't in sight")
+	}
+
+	spotted := spotUnits(units, []sighting{viewer}, fog, alliances, um.Detectability)
+	if !spotted[open.ID] {
+		t.Error("unit in the open isn't spotted")
+	}
+	if spotted[hidden.ID] {
+		t.Error("unit in forest is spotted from afar")
+	}
+
+	// Close in, or let it fire, and it shows
+	near := sighting{rl.NewVector3(27.5, 0, 20.5), 12}
+	if !spotUnits(units, []sighting{near}, fog, alliances, um.Detectability)[hidden.ID] {
+		t.Error("unit in forest isn't spotted up close")
+	}
+	hidden.RevealTimer = unit.FireRevealTime
+	if !spotUnits(units, []sighting{viewer}, fog, alliances, um.Detectability)[hidden.ID] {
+		t.Error("unit in forest that just fired isn't spotted")
+	}
+}
@@ -1,11 +1,23 @@
 package main
 
 import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 
+	"github.com/chazu/herzog-drei/pkg/ai"
+	"github.com/chazu/herzog-drei/pkg/assets"
+	"github.com/chazu/herzog-drei/pkg/audio"
 	"github.com/chazu/herzog-drei/pkg/base"
 	"github.com/chazu/herzog-drei/pkg/combat"
+	"github.com/chazu/herzog-drei/pkg/graphics"
 	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/profile"
+	"github.com/chazu/herzog-drei/pkg/scenario"
+	"github.com/chazu/herzog-drei/pkg/stats"
 	"github.com/chazu/herzog-drei/pkg/tilemap"
 	"github.com/chazu/herzog-drei/pkg/unit"
 )
@@ -18,6 +30,20 @@ const (
 
 	mapWidth  = 64
 	mapHeight = 48
+
+	rallySnapRadius   = 5 // Tiles searched for passable ground when placing a rally point
+	selectRadius      = 8 // World units around the mech that G selects
+	hoverRadius       = 1 // World units from the cursor's ground point that count as hovering a unit
+	baseHoverRadius   = 2 // Same for hovering a base
+	bulkPurchaseCount = 5 // Units queued by Shift+number
+	spawnClearRadius  = 1 // A base waits while a unit is still emerging this close to its spawn point
+
+	statsFilePath = "herzog-drei-stats.json" // Match history, in the working directory
+	assetsPath    = "assets"
+
+	// Sound effects, loaded from assets/sounds (missing files play nothing)
+	soundExplosion = "explosion.wav"
+	soundPurchase  = "purchase.wav"
 )
 
 // Game holds the game state
@@ -26,6 +52,8 @@ type Game struct {
 	tileMap *tilemap.TileMap
 	camera  *tilemap.GameCamera
 	minimap *tilemap.Minimap
+	fog     *tilemap.FogOfWar // What the player sees and remembers
+	spotted map[uint32]bool   // Units the player can make out this frame
 
 	// Player mech
 	playerMech   *mech.Mech
@@ -36,6 +64,7 @@ type Game struct {
 	unitManager    *unit.Manager
 	unitRenderer   *unit.Renderer
 	unitPathfinder *unit.Pathfinder
+	alliances      *unit.Alliances
 
 	// Bases
 	baseManager  *base.Manager
@@ -44,69 +73,275 @@ type Game struct {
 	// Combat
 	combatSystem   *combat.System
 	combatRenderer *combat.Renderer
+
+	// Opponent
+	enemyCommander *ai.Commander
+
+	// UI
+	orderMenu OrderMenu
+
+	// Assets and sound
+	assets *assets.Manager
+	audio  *audio.Player
+
+	// Flow control
+	state GameState
+	loser base.Owner // Set when the match ends
+
+	// How each match starts, and scripted events during it
+	matchConfig MatchConfig
+	scenario    *scenario.Runner
+
+	// Statistics
+	stats        *stats.Tracker
+	statsSummary stats.Summary // Win/loss record across saved matches
+	statsPath    string        // Where match results are saved
+
+	// Settings
+	graphicsQuality graphics.Quality
+	dayCycle        *graphics.DayCycle
+
+	// Frame timing overlay
+	profiler *profile.Profiler
 }
 
 // NewGame creates and initializes a new game instance
 func NewGame() *Game {
 	g := &Game{}
 	g.init()
+	g.Reset()
 	return g
 }
 
-// init sets up initial game state
+// init creates the long-lived systems that survive between matches
 func (g *Game) init() {
-	// Create tile map with test terrain
-	g.tileMap = tilemap.GenerateTestMap(mapWidth, mapHeight)
+	g.state = StateMainMenu
 
 	// Set up game camera
 	g.camera = tilemap.NewGameCamera()
-	g.camera.SetBounds(g.tileMap.GetWorldBounds())
-
-	// Create player mech at center of map
-	centerX, centerZ := g.tileMap.TileToWorld(mapWidth/2, mapHeight/2)
-	startPos := rl.NewVector3(centerX, 3, centerZ)
-	g.playerMech = mech.New(startPos, mech.DefaultConfig())
-	g.mechInput = mech.NewInputHandler()
-	g.mechRenderer = mech.NewRenderer()
-
-	// Set camera to follow mech
-	g.camera.SetTarget(g.playerMech.Position)
 
 	// Set up minimap in top-right corner
 	g.minimap = tilemap.NewMinimap()
 	g.minimap.SetPosition(screenWidth-210, 10)
 	g.minimap.SetSize(200, 150)
 
+	// Sounds are heard from the camera
+	g.assets = assets.NewManager(assetsPath)
+	g.audio = audio.NewPlayer(g.assets)
+
+	// Mech input and rendering
+	g.mechInput = mech.NewInputHandler()
+	g.mechRenderer = mech.NewRenderer()
+
 	// Initialize unit system
 	g.unitManager = unit.NewManager(100) // Max 100 units
 	g.unitRenderer = unit.NewRenderer()
-	g.unitPathfinder = unit.NewPathfinder(mapWidth, mapHeight, 1.0)
+	g.unitPathfinder = unit.NewPathfinder(mapWidth, mapHeight, tilemap.DefaultTileSize)
 	g.unitManager.Pathfinder = g.unitPathfinder
 
+	// Phase timing, off until toggled
+	g.profiler = profile.New()
+	g.unitManager.Profiler = g.profiler
+	g.unitPathfinder.Profiler = g.profiler
+
+	// Alliances are shared by units and bases; empty until team games exist
+	g.alliances = unit.NewAlliances()
+	g.unitManager.Alliances = g.alliances
+
 	// Initialize base system
 	g.baseManager = base.NewManager(base.DefaultConfig())
 	g.baseRenderer = base.NewRenderer()
-	g.baseManager.CreateDefaultMap()
+	g.baseManager.Alliances = g.alliances
 
 	// Initialize combat system
 	g.combatSystem = combat.NewSystem(combat.DefaultConfig())
 	g.combatRenderer = combat.NewRenderer()
+	g.combatSystem.SetBaseManager(g.baseManager)
+
+	g.setGraphicsQuality(graphics.QualityHigh)
+
+	// Player 2's spending and orders
+	g.enemyCommander = ai.NewCommander(base.OwnerPlayer2, ai.DefaultCommanderConfig())
+
+	// Match statistics and the saved record
+	g.stats = stats.NewTracker()
+	g.matchConfig = DefaultMatchConfig()
+	g.dayCycle = graphics.NewDayCycle()
+	g.scenario = scenario.NewRunner()
+	g.scenario.Add(scenario.Trigger{
+		Name:      "hq-under-attack",
+		Condition: scenario.HQHealthBelow(base.OwnerPlayer1, 0.5),
+		Action:    scenario.ShowMessage("Your HQ is under attack!"),
+		Repeat:    true, // Warn again if it's repaired and hit again
+	})
+	g.statsPath = statsFilePath
+	history, err := stats.LoadHistory(g.statsPath)
+	if err != nil {
+		log.Printf("stats file unreadable, starting a fresh record: %v", err)
+	}
+	g.statsSummary = stats.Summarize(history)
+
+	// Losses are counted and heard as units die
+	g.unitManager.Subscribe(func(e unit.Event) {
+		if e.Type == unit.EventDied {
+			g.stats.UnitLost(base.OwnerOfTeam(e.Team))
+			g.audio.PlayAt(soundExplosion, e.Position)
+		}
+	})
+}
+
+// Reset starts a fresh match, reusing the window and loaded resources.
+// All per-match state (map, mech, units, bases, effects, credits, IDs) is
+// rebuilt so nothing leaks from the previous match.
+func (g *Game) Reset() {
+	g.loser = base.OwnerNeutral
+	g.orderMenu.Close()
+	g.stats.Reset()
+	g.enemyCommander.Reset()
+	g.scenario.Reset()
+	g.dayCycle.Reset()
+
+	// Create the match's tile map, cleared where the bases go
+	var layout tilemap.Layout
+	g.tileMap, layout = loadMap(g.matchConfig.Map)
+	g.unitManager.DetectionAt = g.tileMap.DetectionModAt
+	g.fog = tilemap.NewFogOfWar(g.tileMap, g.matchConfig.FogMemory)
+	g.minimap.Fog = g.fog
+	g.unitRenderer.Visibility = g.fog
+	g.unitRenderer.Spotted = func(u *unit.Unit) bool { return g.spotted[u.ID] }
+	g.combatRenderer.Visibility = g.fog
+	g.unitPathfinder.SyncFromTileMap(g.tileMap)
+	g.camera.SetBounds(g.tileMap.GetWorldBounds())
+
+	// Create player mech at center of map
+	centerX, centerZ := g.tileMap.TileToWorld(g.tileMap.Width/2, g.tileMap.Height/2)
+	startPos := rl.NewVector3(centerX, 3, centerZ)
+	g.playerMech = mech.New(startPos, mech.DefaultConfig())
+	g.playerMech.Projectiles = g.combatSystem.Projectiles
+
+	// Set camera to follow mech
+	g.camera.SetTarget(g.playerMech.Position)
+
+	// Clear out units, bases, and effects from any previous match
+	g.unitManager.Reset()
+	g.unitRenderer.Hovered = nil
+	g.baseManager.Reset()
+	g.applyHandicaps(g.matchConfig)
+	if g.matchConfig.Map == "" {
+		g.baseManager.CreateDefaultMap(g.tileMap) // Adds the center garrison
+	} else {
+		g.baseManager.CreateFromLayout(g.tileMap, layout)
+	}
+	g.baseManager.ResolveSpawnPoints(g.tileMap)
+	g.combatSystem.Reset()
 	g.combatSystem.SetRespawnPosition(startPos) // Respawn at start position
 
-	// Spawn test units for demonstration
-	g.spawnTestUnits()
+	// Starting credits and forces, then the neutral garrisons
+	g.applyMatchConfig(g.matchConfig)
+	g.deployGarrisons()
 }
 
 // Update handles game logic each frame
 func (g *Game) Update() {
-	dt := rl.GetFrameTime()
+	g.profiler.BeginFrame()
+
+	// Settings can be changed from any screen
+	if rl.IsKeyPressed(rl.KeyF2) {
+		g.setGraphicsQuality(g.graphicsQuality.Next())
+	}
+	if rl.IsKeyPressed(rl.KeyF3) {
+		g.mechInput.MoveMode = g.mechInput.MoveMode.Next()
+	}
+	if rl.IsKeyPressed(rl.KeyF4) {
+		g.minimap.RotateWithCamera = !g.minimap.RotateWithCamera
+	}
+	if rl.IsKeyPressed(rl.KeyF5) {
+		g.combatSystem.Config.ShowDamageText = !g.combatSystem.Config.ShowDamageText
+	}
+	if rl.IsKeyPressed(rl.KeyF6) {
+		g.dayCycle.Enabled = !g.dayCycle.Enabled
+	}
+	if rl.IsKeyPressed(rl.KeyF7) {
+		g.profiler.Enabled = !g.profiler.Enabled
+	}
+
+	switch g.state {
+	case StateMainMenu:
+		if rl.IsKeyPressed(rl.KeyEnter) {
+			g.state = StatePlaying
+		}
+
+	case StatePlaying:
+		if rl.IsKeyPressed(rl.KeyP) {
+			g.state = StatePaused
+			return
+		}
+		g.updatePlaying(rl.GetFrameTime())
+		g.checkGameOver()
+
+	case StatePaused:
+		if rl.IsKeyPressed(rl.KeyP) {
+			g.state = StatePlaying
+		}
+
+	case StateGameOver:
+		// Simulation is frozen, only a restart gets us out
+		if rl.IsKeyPressed(rl.KeyEnter) {
+			g.restart()
+		}
+	}
+}
+
+// checkGameOver ends the match as soon as an HQ falls
+func (g *Game) checkGameOver() {
+	if loser := g.baseManager.IsGameOver(); loser != base.OwnerNeutral {
+		g.endMatch(loser)
+	}
+}
+
+// endMatch freezes the game on the game-over screen and saves the result
+func (g *Game) endMatch(loser base.Owner) {
+	g.loser = loser
+	g.state = StateGameOver
+
+	winner := base.OwnerPlayer1
+	if loser == base.OwnerPlayer1 {
+		winner = base.OwnerPlayer2
+	}
+
+	record := stats.NewRecord(g.stats, winner, time.Now())
+	history, err := stats.AppendRecord(g.statsPath, record)
+	if err != nil {
+		log.Printf("failed to save match stats: %v", err)
+	}
+	g.statsSummary = stats.Summarize(history)
+}
+
+// setGraphicsQuality applies a graphics quality level to the effect renderers
+func (g *Game) setGraphicsQuality(q graphics.Quality) {
+	g.graphicsQuality = q
+	settings := graphics.SettingsFor(q)
+	g.mechRenderer.Graphics = settings
+	g.unitRenderer.Graphics = settings
+	g.combatRenderer.Graphics = settings
+	g.combatSystem.Config.MaxDecals = settings.MaxDecals
+}
+
+// updatePlaying advances the simulation by one frame
+func (g *Game) updatePlaying(dt float32) {
+	g.stats.Tick(dt)
+	g.dayCycle.Update(dt)
 
 	// Handle camera input (zoom)
 	g.camera.HandleInput()
 
 	// Process player input
+	g.mechInput.CameraYaw = g.camera.Yaw()
 	g.mechInput.Update(g.playerMech)
 
+	// Radial order menu for selected units (swallows clicks while open)
+	g.handleOrderMenuInput()
+
 	// Update mech
 	g.playerMech.Update(dt)
 
@@ -121,27 +356,131 @@ func (g *Game) Update() {
 		g.playerMech.Position.Y = g.tileMap.GetHeightAt(g.playerMech.Position.X, g.playerMech.Position.Z)
 	}
 
+	// Peaks are too high to fly over, the jet slides along them instead
+	resolveFlightCollision(g.playerMech, g.tileMap, dt)
+
 	// Handle transport (pickup/drop units)
 	g.handleTransport()
 
-	// Update units
+	// Update units, guards escorting the mech follow it
+	g.unitManager.SetMech(g.playerMech.Position, !g.playerMech.IsDead())
 	g.unitManager.Update(dt)
 
 	// Update bases (income, capture progress, spawns)
+	stop := g.profiler.Start(profile.SectionBases)
+	g.baseManager.UpdateStationing(g.unitManager)
+	g.baseManager.UpdateCaptures(g.unitManager.GetUnits())
 	g.baseManager.Update(dt)
+	g.baseManager.UpdateDefenses(dt, g.unitManager.GetUnits())
+	stop()
+	for _, b := range g.baseManager.RecentCaptures() {
+		g.stats.BaseCaptured(b.Owner)
+	}
 
 	// Update combat (hit detection, damage, respawn)
+	stop = g.profiler.Start(profile.SectionCombat)
 	g.combatSystem.Update(dt, g.playerMech, g.unitManager)
+	stop()
+	for _, b := range g.baseManager.Bases {
+		g.combatSystem.ReportDamage(b, b.Position, b.ConsumeDamage())
+	}
+
+	// What the player can see after everything has moved
+	g.updateFog(dt)
+
+	// Let the opponent spend its income and direct its units
+	stop = g.profiler.Start(profile.SectionAI)
+	g.enemyCommander.Update(dt, g.baseManager, g.unitManager)
+	stop()
+
+	// Scripted scenario events
+	g.scenario.Update(dt, g.unitManager, g.baseManager)
 
 	// Process base spawn queues - spawn units from bases
 	g.processBaseSpawns()
 
-	// Handle unit purchasing (press 1-6 to buy units at nearest owned base)
+	// Handle unit purchasing (purchase hotkeys buy at nearest owned base)
 	g.handleUnitPurchaseInput()
 
+	// Handle rally point placement (shift-click on the minimap)
+	g.handleRallyInput()
+
+	// Handle base defense stance (B toggles the nearest owned base)
+	g.handleBaseStanceInput()
+
+	// Handle bringing garrisoned infantry back out (U)
+	g.handleUngarrisonInput()
+
+	// Handle unit selection and path display toggle
+	g.handleSelectionInput()
+
+	// Health bars show for whatever the mouse is over, enemies only if
+	// the player can see them
+	ground := g.camera.ScreenToWorld(rl.GetMousePosition(), 0)
+	g.unitRenderer.Hovered = g.unitManager.GetNearestSeenUnit(ground, hoverRadius, g.unitRenderer.IsSeen)
+	g.unitRenderer.Zoom = g.camera.ZoomLevel
+
 	// Update camera to follow mech
 	g.camera.SetTarget(g.playerMech.Position)
 	g.camera.Update()
+	g.audio.SetListener(g.camera.Camera.Target, g.camera.Yaw())
+}
+
+// drawInspectTooltip shows details of the unit or base under the mouse
+// beside the cursor
+func (g *Game) drawInspectTooltip() {
+	var info unit.InspectInfo
+	if u := g.unitRenderer.Hovered; u != nil && !u.IsDead() {
+		info = u.InspectAs(g.playerMech.Team, g.unitRenderer.Visibility)
+	} else {
+		ground := g.camera.ScreenToWorld(rl.GetMousePosition(), 0)
+		b := g.baseManager.GetBaseAt(ground, baseHoverRadius)
+		if b == nil {
+			return
+		}
+		info = b.InspectAs(base.OwnerPlayer1, g.unitRenderer.Visibility)
+	}
+
+	const fontSize, lineHeight, pad = 14, 16, 6
+	lines := info.Lines()
+	width := int32(0)
+	for _, line := range lines {
+		if w := rl.MeasureText(line, fontSize); w > width {
+			width = w
+		}
+	}
+
+	mouse := rl.GetMousePosition()
+	x, y := int32(mouse.X)+16, int32(mouse.Y)+16
+	rl.DrawRectangle(x, y, width+2*pad, int32(len(lines))*lineHeight+2*pad, rl.Color{R: 20, G: 20, B: 30, A: 210})
+	for i, line := range lines {
+		rl.DrawText(line, x+pad, y+pad+int32(i)*lineHeight, fontSize, rl.RayWhite)
+	}
+}
+
+// resolveFlightCollision keeps a jet out of tiles it can't fly over,
+// pushing it back out the way it came like robot-mode collision. It undoes
+// only the blocked part of the move where it can, so the jet slides along a
+// mountain's edge rather than sticking to it. Returns true if it pushed.
+func resolveFlightCollision(m *mech.Mech, tm *tilemap.TileMap, dt float32) bool {
+	if m.Mode != mech.ModeJet || tm.IsFlyableAt(m.Position.X, m.Position.Z) {
+		return false
+	}
+
+	prevX := m.Position.X - m.Velocity.X*dt
+	prevZ := m.Position.Z - m.Velocity.Z*dt
+	switch {
+	case tm.IsFlyableAt(prevX, m.Position.Z):
+		m.Position.X = prevX
+		m.Velocity.X = 0
+	case tm.IsFlyableAt(m.Position.X, prevZ):
+		m.Position.Z = prevZ
+		m.Velocity.Z = 0
+	default:
+		m.Position.X, m.Position.Z = prevX, prevZ
+		m.Velocity.X, m.Velocity.Z = 0, 0
+	}
+	return true
 }
 
 // handleTransport handles picking up and dropping units
@@ -159,22 +498,26 @@ func (g *Game) handleTransport() {
 		}
 	}
 
-	// Handle drop
+	// Handle drop, units stay aboard if there's nowhere below to put them
 	if g.playerMech.InputDrop && g.playerMech.CanDrop() {
-		g.playerMech.DropUnit()
+		if dropPos, _, ok := g.playerMech.DropTarget(g.tileMap, g.unitManager.GetUnits()); ok {
+			g.playerMech.DropUnitAt(dropPos)
+		}
 	}
 }
 
 // Render draws the game each frame
 func (g *Game) Render() {
+	stop := g.profiler.Start(profile.SectionRender)
+
 	rl.BeginDrawing()
-	rl.ClearBackground(rl.SkyBlue)
+	rl.ClearBackground(g.dayCycle.ClearColor())
 
 	// 3D rendering
 	g.camera.Begin3D()
 
 	// Render tile map
-	g.tileMap.Render()
+	g.tileMap.RenderWithFog(g.fog)
 
 	// Draw bases
 	g.baseRenderer.Draw(g.baseManager)
@@ -184,16 +527,24 @@ func (g *Game) Render() {
 
 	// Draw player mech
 	g.mechRenderer.Draw(g.playerMech)
+	g.mechRenderer.DrawDropPreview(g.playerMech, g.tileMap, g.unitManager.GetUnits())
 
 	// Draw combat effects (explosions)
 	g.combatRenderer.Draw(g.combatSystem)
 
 	g.camera.End3D()
 
-	// Draw minimap with player marker
-	markers := []tilemap.MinimapMarker{
-		tilemap.NewMarker(g.playerMech.Position.X, g.playerMech.Position.Z, tilemap.MarkerPlayer, rl.Red),
+	// Darken the world at night, the minimap and HUD stay readable on top
+	if g.dayCycle.Enabled {
+		rl.DrawRectangle(0, 0, screenWidth, screenHeight, g.dayCycle.Tint())
 	}
+
+	// Floating damage numbers and rank chevrons sit over the world, under the HUD
+	g.combatRenderer.DrawDamageTexts(g.combatSystem, g.camera.Camera)
+	g.unitRenderer.DrawRanks(g.unitManager, g.camera.Camera)
+
+	// Draw minimap with bases, units, and the player
+	markers := buildMinimapMarkers(g.playerMech, g.unitManager.GetUnits(), g.baseManager.Bases, g.fog, g.unitRenderer.IsSeen)
 	g.minimap.RenderWithMarkers(g.tileMap, g.camera, markers)
 
 	// Draw UI overlay
@@ -212,6 +563,17 @@ func (g *Game) Render() {
 	// Draw combat UI (respawn timer, invulnerability)
 	g.combatRenderer.DrawUI(g.combatSystem, screenWidth, screenHeight)
 
+	// Scenario messages
+	scenario.DrawMessages(g.scenario, screenWidth)
+
+	// Details of whatever the mouse is over
+	if !g.orderMenu.Open {
+		g.drawInspectTooltip()
+	}
+
+	// Draw the order menu over the HUD
+	g.orderMenu.Draw()
+
 	// Show current terrain info
 	terrain := g.tileMap.GetTerrainAt(g.playerMech.Position.X, g.playerMech.Position.Z)
 	info := tilemap.GetTerrainInfo(terrain)
@@ -219,132 +581,171 @@ func (g *Game) Render() {
 
 	// Show transport info
 	if g.playerMech.IsCarrying() {
-		carriedInfo := "Carrying: " + g.playerMech.CarriedUnit.Config.Type.String()
+		carried := g.playerMech.CarriedUnits
+		names := make([]string, len(carried))
+		for i, u := range carried {
+			names[i] = u.Config.Type.String()
+		}
+		carriedInfo := fmt.Sprintf("Carrying %d/%d: %s", len(carried), g.playerMech.Config.MaxCarryCount, strings.Join(names, ", "))
 		rl.DrawText(carriedInfo, 10, screenHeight-80, 15, rl.Green)
 	}
 	orderInfo := "Order: " + g.playerMech.GetSelectedOrderName() + " (R/F to cycle)"
 	rl.DrawText(orderInfo, 10, screenHeight-60, 15, rl.DarkGray)
 
-	rl.DrawText("T: Transform | E: Pickup | Q: Drop | R/F: Cycle Order | Scroll: Zoom", 10, screenHeight-40, 12, rl.DarkGray)
-	rl.DrawText("1-6: Spawn units | 1:Infantry 2:Tank 3:Bike 4:SAM 5:Boat 6:Supply", 10, screenHeight-20, 12, rl.DarkGray)
+	rl.DrawText("T: Transform | E: Pickup | Q: Drop | R/F: Cycle Order | G: Select nearby | H: Show paths | RMB: Order selection | C: Camera ("+g.camera.PresetName()+") | P: Pause", 10, screenHeight-40, 12, rl.DarkGray)
+	rl.DrawText("Number keys: Buy units at nearest base (Shift: x5, Backspace: clear queue) | Shift+Click minimap: Rally | B: Base stance | U: Ungarrison | RMB minimap: Move (Shift: Attack-move)", 10, screenHeight-20, 12, rl.DarkGray)
 
-	rl.EndDrawing()
-}
+	// Phase timings from the last frame
+	profile.Draw(g.profiler, screenWidth-280, 40)
+
+	// Menu, pause, and game-over screens draw on top of the last frame
+	g.drawStateOverlay()
 
-// spawnTestUnits creates initial units for testing
-func (g *Game) spawnTestUnits() {
-	centerX, centerZ := g.tileMap.TileToWorld(mapWidth/2, mapHeight/2)
-
-	// Spawn player units on left side
-	g.unitManager.SpawnWithObjective(
-		unit.TypeInfantry, unit.TeamPlayer,
-		rl.NewVector3(centerX-10, 0, centerZ+5),
-		rl.NewVector3(centerX+10, 0, centerZ+5),
-	)
-	g.unitManager.SpawnWithObjective(
-		unit.TypeTank, unit.TeamPlayer,
-		rl.NewVector3(centerX-10, 0, centerZ),
-		rl.NewVector3(centerX+10, 0, centerZ),
-	)
-	g.unitManager.SpawnWithObjective(
-		unit.TypeMotorcycle, unit.TeamPlayer,
-		rl.NewVector3(centerX-10, 0, centerZ-5),
-		rl.NewVector3(centerX+10, 0, centerZ-5),
-	)
-
-	// Spawn enemy units on right side
-	g.unitManager.SpawnWithObjective(
-		unit.TypeInfantry, unit.TeamEnemy,
-		rl.NewVector3(centerX+10, 0, centerZ+5),
-		rl.NewVector3(centerX-10, 0, centerZ+5),
-	)
-	g.unitManager.SpawnWithObjective(
-		unit.TypeTank, unit.TeamEnemy,
-		rl.NewVector3(centerX+10, 0, centerZ),
-		rl.NewVector3(centerX-10, 0, centerZ),
-	)
-	g.unitManager.SpawnWithObjective(
-		unit.TypeSAM, unit.TeamEnemy,
-		rl.NewVector3(centerX+10, 0, centerZ-5),
-		rl.NewVector3(centerX-10, 0, centerZ-5),
-	)
+	// Presenting waits on vsync, keep it out of the render timing
+	stop()
+	rl.EndDrawing()
 }
 
 // processBaseSpawns handles spawning units from base queues
 func (g *Game) processBaseSpawns() {
 	for _, b := range g.baseManager.Bases {
-		unitType, spawned := b.TrySpawn(g.baseManager.Config)
+		// The last unit is still emerging on the spawn point
+		if g.unitManager.SpawningNear(b.NextSpawnPoint(), spawnClearRadius) {
+			continue
+		}
+
+		unitType, spawnPoint, spawned := b.TrySpawn(g.baseManager.Config)
 		if !spawned {
 			continue
 		}
 
 		// Map base owner to unit team
-		var team unit.Team
-		switch b.Owner {
-		case base.OwnerPlayer1:
-			team = unit.TeamPlayer
-		case base.OwnerPlayer2:
-			team = unit.TeamEnemy
-		default:
+		team, ok := b.Owner.Team()
+		if !ok {
 			continue // Neutral bases shouldn't spawn
 		}
 
-		// Spawn the unit at the base's spawn point
-		g.unitManager.Spawn(unitType, team, b.SpawnPoint)
+		// Spawn the unit at the base's next spawn point, staggered over
+		// frames when many bases are ready at once, then send it to the
+		// base's rally point
+		owner := b.Owner
+		g.unitManager.QueueSpawn(unit.SpawnRequest{
+			Type:     unitType,
+			Team:     team,
+			Position: spawnPoint,
+			OnSpawn: func(u *unit.Unit) {
+				g.stats.UnitBuilt(owner)
+				if b.Rally(u) {
+					g.unitManager.RequestPath(u, u.Objective)
+				}
+			},
+		})
 	}
 }
 
-// handleUnitPurchaseInput purchases units based on number key presses
+// handleUnitPurchaseInput purchases units based on number key presses.
+// Shift queues several at once, Backspace clears the queue for a refund.
 func (g *Game) handleUnitPurchaseInput() {
-	// Find nearest owned base to purchase from
+	if rl.IsKeyPressed(rl.KeyBackspace) {
+		if nearestBase := g.findNearestOwnedBase(base.OwnerPlayer1); nearestBase != nil {
+			g.baseManager.ClearQueue(nearestBase.ID, base.OwnerPlayer1)
+		}
+	}
+
+	count := 1
+	if rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift) {
+		count = bulkPurchaseCount
+	}
+
+	for _, opt := range base.PurchaseOptions {
+		if !rl.IsKeyPressed(opt.Key) {
+			continue
+		}
+
+		// Find nearest owned base to purchase from (pathfinds, so only on a key press)
+		nearestBase := g.findNearestOwnedBase(base.OwnerPlayer1)
+		if nearestBase == nil {
+			return // No owned bases to purchase from
+		}
+
+		// Try to purchase - this checks credits and queues at the base
+		if g.baseManager.TryPurchaseUnits(nearestBase.ID, opt.UnitType, base.OwnerPlayer1, count) > 0 {
+			g.audio.PlayUI(soundPurchase)
+		}
+	}
+}
+
+// handleRallyInput sets the nearest owned base's rally point when the
+// player shift-clicks on the minimap
+func (g *Game) handleRallyInput() {
+	shiftDown := rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+	if !shiftDown || !rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		return
+	}
+
+	worldX, worldZ, ok := g.minimap.ScreenToWorld(g.tileMap, rl.GetMousePosition())
+	if !ok {
+		return // Click wasn't on the minimap
+	}
+
 	nearestBase := g.findNearestOwnedBase(base.OwnerPlayer1)
 	if nearestBase == nil {
-		return // No owned bases to purchase from
+		return
 	}
 
-	// Map keys 1-6 to unit types
-	type keyMapping struct {
-		key      int32
-		unitType unit.UnitType
-	}
-	mappings := []keyMapping{
-		{rl.KeyOne, unit.TypeInfantry},
-		{rl.KeyTwo, unit.TypeTank},
-		{rl.KeyThree, unit.TypeMotorcycle},
-		{rl.KeyFour, unit.TypeSAM},
-		{rl.KeyFive, unit.TypeBoat},
-		{rl.KeySix, unit.TypeSupply},
+	// Units can't gather on water or mountains, snap to the closest good tile
+	rallyX, rallyZ, ok := g.tileMap.NearestPassable(worldX, worldZ, rallySnapRadius)
+	if !ok {
+		return
 	}
+	nearestBase.SetRallyPoint(rl.NewVector3(rallyX, 0, rallyZ))
+}
 
-	for _, m := range mappings {
-		if rl.IsKeyPressed(m.key) {
-			// Try to purchase - this checks credits and queues at the base
-			g.baseManager.TryPurchaseUnit(nearestBase.ID, m.unitType, base.OwnerPlayer1)
-		}
+// handleBaseStanceInput switches the nearest owned base's turret between
+// firing at will and holding fire
+func (g *Game) handleBaseStanceInput() {
+	if !rl.IsKeyPressed(rl.KeyB) {
+		return
+	}
+	if nearestBase := g.findNearestOwnedBase(base.OwnerPlayer1); nearestBase != nil {
+		nearestBase.CycleStance()
 	}
 }
 
-// findNearestOwnedBase finds the player's nearest owned base
-func (g *Game) findNearestOwnedBase(owner base.Owner) *base.Base {
-	ownedBases := g.baseManager.GetBasesOwnedBy(owner)
-	if len(ownedBases) == 0 {
-		return nil
+// handleUngarrisonInput brings one infantry out of the nearest owned base
+func (g *Game) handleUngarrisonInput() {
+	if !rl.IsKeyPressed(rl.KeyU) {
+		return
 	}
+	if nearestBase := g.findNearestOwnedBase(base.OwnerPlayer1); nearestBase != nil {
+		g.baseManager.ExtractStationed(nearestBase.ID, base.OwnerPlayer1, g.unitManager)
+	}
+}
 
-	var nearest *base.Base
-	nearestDist := float32(1e9)
+// handleSelectionInput selects friendly units around the mech and toggles
+// the selection's path display
+func (g *Game) handleSelectionInput() {
+	if rl.IsKeyPressed(rl.KeyG) {
+		g.unitManager.SelectInRadius(g.playerMech.Position, selectRadius, g.playerMech.Team)
+	}
+	if rl.IsKeyPressed(rl.KeyH) {
+		g.unitRenderer.ShowSelectedPaths = !g.unitRenderer.ShowSelectedPaths
+	}
+}
 
-	for _, b := range ownedBases {
-		dx := b.Position.X - g.playerMech.Position.X
-		dz := b.Position.Z - g.playerMech.Position.Z
-		dist := dx*dx + dz*dz // squared distance is fine for comparison
-		if dist < nearestDist {
-			nearestDist = dist
-			nearest = b
-		}
+// Shutdown releases everything the game loaded: models, textures, sounds,
+// and render targets. Safe to call on a partly initialized game, and more
+// than once.
+func (g *Game) Shutdown() {
+	if g.assets != nil {
+		g.assets.Unload()
 	}
+}
 
-	return nearest
+// findNearestOwnedBase finds the player's nearest owned base that can
+// spawn units, preferring bases the mech's position can actually reach
+func (g *Game) findNearestOwnedBase(owner base.Owner) *base.Base {
+	return g.baseManager.NearestReachableOwnedBase(g.playerMech.Position, owner, g.unitPathfinder)
 }
 
 func main() {
@@ -352,10 +753,15 @@ func main() {
 	rl.InitWindow(screenWidth, screenHeight, gameTitle)
 	defer rl.CloseWindow()
 
+	rl.InitAudioDevice()
+	defer rl.CloseAudioDevice()
+
 	rl.SetTargetFPS(targetFPS)
 
-	// Create game instance
+	// Create game instance, releasing its resources before the window and
+	// audio device close
 	game := NewGame()
+	defer game.Shutdown()
 
 	// Main game loop
 	for !rl.WindowShouldClose() {
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/projectile"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// matchState is what a match starts from, as comparable strings
+type matchState struct {
+	Units   []string
+	Bases   []string
+	Credits [2]float32
+	Mech    string
+}
+
+func snapshot(g *Game) matchState {
+	var s matchState
+	for _, u := range g.unitManager.GetUnits() {
+		s.Units = append(s.Units, fmt.Sprintf("%d %s team %d at %v, %v/%v hp, %s",
+			u.ID, unit.TypeName(u.Config.Type), u.Team, u.Position, u.Health, u.MaxHealth, unit.OrderName(u.Order)))
+	}
+	for _, b := range g.baseManager.Bases {
+		s.Bases = append(s.Bases, fmt.Sprintf("%d type %d owner %d at %v, %v/%v hp",
+			b.ID, b.Type, b.Owner, b.Position, b.Health, b.MaxHealth))
+	}
+	s.Credits = [2]float32{g.baseManager.GetCredits(base.OwnerPlayer1), g.baseManager.GetCredits(base.OwnerPlayer2)}
+	s.Mech = fmt.Sprintf("at %v, %v hp", g.playerMech.Position, g.playerMech.Health)
+	return s
+}
+
+func TestResetStartsIdenticalMatches(t *testing.T) {
+	g := newTestGame(t)
+	first := snapshot(g)
+	if len(first.Units) == 0 || len(first.Bases) == 0 {
+		t.Fatalf("match starts with %d units and %d bases", len(first.Units), len(first.Bases))
+	}
+
+	// Play some of a match: spend, fight, and fill the effect pools
+	g.baseManager.GetPlayer(base.OwnerPlayer1).Credits = 1
+	g.baseManager.GetHQ(base.OwnerPlayer2).TakeDamage(100)
+	target := g.unitManager.Spawn(unit.TypeTank, unit.TeamEnemy, rl.NewVector3(10.5, 0, 10.5))
+	target.SpawnTimer = 0
+	target.State = unit.StateIdle
+	for i := 0; i < 5; i++ {
+		g.combatSystem.Projectiles.Fire(projectile.Projectile{
+			Position: rl.NewVector3(5.5, 0.5, 10.5+float32(i)),
+			Velocity: rl.NewVector3(20, 0, 0),
+			Damage:   10,
+			MaxLife:  2,
+			Team:     unit.TeamPlayer,
+		})
+	}
+	for i := 0; i < 20; i++ {
+		g.combatSystem.Update(1.0/60, g.playerMech, g.unitManager)
+	}
+	if len(g.combatSystem.GetExplosions()) == 0 || g.combatSystem.Projectiles.Count() == 0 {
+		t.Fatal("fighting left no effects to clear")
+	}
+
+	g.Reset()
+	if second := snapshot(g); !reflect.DeepEqual(first, second) {
+		t.Errorf("second match starts differently:\nfirst  %+v\nsecond %+v", first, second)
+	}
+	if n := g.combatSystem.Projectiles.Count(); n != 0 {
+		t.Errorf("%d projectiles left over from the last match", n)
+	}
+	if n := len(g.combatSystem.GetExplosions()); n != 0 {
+		t.Errorf("%d explosions left over from the last match", n)
+	}
+
+	// IDs start over too, so the next unit gets the same ID both times
+	g.Reset()
+	a := g.unitManager.Spawn(unit.TypeInfantry, unit.TeamPlayer, rl.NewVector3(1, 0, 1))
+	g.Reset()
+	b := g.unitManager.Spawn(unit.TypeInfantry, unit.TeamPlayer, rl.NewVector3(1, 0, 1))
+	if a.ID != b.ID {
+		t.Errorf("first unit after reset got ID %d, then %d", a.ID, b.ID)
+	}
+}
+
+func TestSpawnedUnitsTakeRallyOrder(t *testing.T) {
+	g := newTestGame(t)
+	hq := g.baseManager.GetHQ(base.OwnerPlayer1)
+	rally := rl.NewVector3(hq.Position.X+6, 0, hq.Position.Z+4)
+	hq.SetRally(rally, unit.OrderCaptureOutpost)
+
+	existing := map[uint32]bool{}
+	for _, u := range g.unitManager.GetUnits() {
+		existing[u.ID] = true
+	}
+	spawnOne := func(ut unit.UnitType) *unit.Unit {
+		t.Helper()
+		if !g.baseManager.TryPurchaseUnit(hq.ID, ut, base.OwnerPlayer1) {
+			t.Fatalf("couldn't buy a %s", unit.TypeName(ut))
+		}
+		hq.SpawnCooldown = 0
+		g.processBaseSpawns()
+		g.unitManager.Update(1.0 / 60)
+		for _, u := range g.unitManager.GetUnits() {
+			if !existing[u.ID] {
+				existing[u.ID] = true
+				return u
+			}
+		}
+		t.Fatalf("no %s spawned", unit.TypeName(ut))
+		return nil
+	}
+
+	infantry := spawnOne(unit.TypeInfantry)
+	if infantry.Order != unit.OrderCaptureOutpost {
+		t.Errorf("infantry spawned with order %s, want the rally order", unit.OrderName(infantry.Order))
+	}
+	if !infantry.HasObjective || infantry.Objective != rally {
+		t.Errorf("infantry heading for %v, want the rally point %v", infantry.Objective, rally)
+	}
+
+	// A unit that can't follow the rally order still goes to the rally point
+	g.baseManager.AddCredits(base.OwnerPlayer1, 1000)
+	g.baseManager.Config.Requirements = nil
+	motorcycle := spawnOne(unit.TypeMotorcycle)
+	if motorcycle.Order != unit.OrderNone {
+		t.Errorf("motorcycle spawned with order %s it can't follow", unit.OrderName(motorcycle.Order))
+	}
+	if !motorcycle.HasObjective || motorcycle.Objective != rally {
+		t.Errorf("motorcycle heading for %v, want the rally point %v", motorcycle.Objective, rally)
+	}
+}
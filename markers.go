@@ -0,0 +1,60 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// buildMinimapMarkers returns the minimap blips for a frame. Bases come
+// first so units draw over them, and the mech last so it's always on top.
+// Other sides' units only show while seen, and through fog their bases
+// once explored (nil fog shows every base).
+func buildMinimapMarkers(playerMech *mech.Mech, units []*unit.Unit, bases []*base.Base, fog *tilemap.FogOfWar, seen func(*unit.Unit) bool) []tilemap.MinimapMarker {
+	markers := make([]tilemap.MinimapMarker, 0, len(bases)+len(units)+1)
+
+	for _, b := range bases {
+		if b.IsDestroyed() {
+			continue
+		}
+		if fog != nil && b.Owner != base.OwnerPlayer1 && !fog.IsExploredAt(b.Position) {
+			continue
+		}
+		markers = append(markers, tilemap.NewMarker(b.Position.X, b.Position.Z, baseMarkerType(b), b.DisplayColor()))
+	}
+
+	for _, u := range units {
+		if u.IsDead() || u.IsCarried() {
+			continue
+		}
+		if !seen(u) {
+			continue
+		}
+		color := base.OwnerColor(base.OwnerOfTeam(u.Team))
+		markers = append(markers, tilemap.NewMarker(u.Position.X, u.Position.Z, unitMarkerType(u), color))
+	}
+
+	// White so the player's own blip never reads as an enemy
+	markers = append(markers, tilemap.NewMarker(playerMech.Position.X, playerMech.Position.Z, tilemap.MarkerPlayer, rl.White))
+	return markers
+}
+
+// baseMarkerType picks the blip for a base, HQs stand out
+func baseMarkerType(b *base.Base) tilemap.MarkerType {
+	if b.Type == base.TypeHQ {
+		return tilemap.MarkerHQ
+	}
+	return tilemap.MarkerBase
+}
+
+// unitMarkerType picks the blip for a unit, units that can't fight are
+// marked as support
+func unitMarkerType(u *unit.Unit) tilemap.MarkerType {
+	if !u.Config.CanAttackGround && !u.Config.CanAttackAir {
+		return tilemap.MarkerSupport
+	}
+	return tilemap.MarkerUnit
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"log"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+const (
+	deploySearchRadius = 6   // Tiles a starting unit may be moved to find free, passable ground
+	deployClearance    = 0.8 // Starting units are placed at least this far apart
+	garrisonRadius     = 2.5 // Distance from its base a garrison defender is posted
+)
+
+// StartingUnit is a unit placed when a match begins
+type StartingUnit struct {
+	Type   unit.UnitType
+	Offset rl.Vector3 // Position relative to the owner's HQ
+	Order  unit.Order // OrderNone leaves the unit idle
+}
+
+// PlayerSetup is one player's state at the start of a match
+type PlayerSetup struct {
+	Credits float64
+	Units   []StartingUnit
+
+	// Handicap scaling the health of the player's units and bases (0 = 1x)
+	HealthMultiplier float32
+}
+
+// MatchConfig defines how a match starts for each player
+type MatchConfig struct {
+	Player1 PlayerSetup
+	Player2 PlayerSetup
+
+	// Seed for simulation randomness, the same seed and inputs replay the
+	// same match
+	Seed int64
+
+	// What each player remembers of explored ground out of sight
+	FogMemory tilemap.FogMemory
+
+	// Builtin map to play on (see tilemap.BuiltinNames), empty for the
+	// test map
+	Map string
+}
+
+// DefaultMatchConfig returns the standard opening: a small mixed force
+// in front of each HQ, the fast units already pushing forward
+func DefaultMatchConfig() MatchConfig {
+	return MatchConfig{
+		Seed:      unit.DefaultSeed,
+		FogMemory: tilemap.FogMemoryFull,
+		Player1: PlayerSetup{
+			Credits: base.StartingCredits,
+			Units: []StartingUnit{
+				{Type: unit.TypeInfantry, Offset: rl.NewVector3(-4, 0, 4), Order: unit.OrderDefendPosition},
+				{Type: unit.TypeTank, Offset: rl.NewVector3(0, 0, 4), Order: unit.OrderAttackHQ},
+				{Type: unit.TypeMotorcycle, Offset: rl.NewVector3(4, 0, 4), Order: unit.OrderAttackHQ},
+			},
+		},
+		Player2: PlayerSetup{
+			Credits: base.StartingCredits,
+			Units: []StartingUnit{
+				{Type: unit.TypeInfantry, Offset: rl.NewVector3(-4, 0, -4), Order: unit.OrderDefendPosition},
+				{Type: unit.TypeTank, Offset: rl.NewVector3(0, 0, -4), Order: unit.OrderAttackHQ},
+				{Type: unit.TypeSAM, Offset: rl.NewVector3(4, 0, -4), Order: unit.OrderDefendPosition},
+			},
+		},
+	}
+}
+
+// Setup returns the starting state for a player
+func (c MatchConfig) Setup(owner base.Owner) PlayerSetup {
+	if owner == base.OwnerPlayer2 {
+		return c.Player2
+	}
+	return c.Player1
+}
+
+// loadMap returns the terrain and base layout for a match. An empty or
+// unknown name gives the test map with the standard layout.
+func loadMap(name string) (*tilemap.TileMap, tilemap.Layout) {
+	if name != "" {
+		b, err := tilemap.LoadBuiltin(name)
+		if err == nil {
+			return b.Map, b.Layout
+		}
+		log.Printf("playing the test map instead: %v", err)
+	}
+
+	tm := tilemap.GenerateTestMap(mapWidth, mapHeight)
+	layout := tilemap.StandardLayout(mapWidth, mapHeight)
+	tm.ClearSites(layout)
+	return tm, layout
+}
+
+// applyHandicaps sets each player's health handicap for their units and
+// bases. Call before the bases and starting units are created.
+func (g *Game) applyHandicaps(cfg MatchConfig) {
+	for _, owner := range []base.Owner{base.OwnerPlayer1, base.OwnerPlayer2} {
+		player := g.baseManager.GetPlayer(owner)
+		player.HealthMultiplier = cfg.Setup(owner).HealthMultiplier
+
+		team, _ := owner.Team()
+		g.unitManager.SetHealthMultiplier(team, player.HealthScale())
+	}
+}
+
+// applyMatchConfig sets starting credits and deploys each player's starting
+// units around their HQ. Call after the map and bases are created.
+func (g *Game) applyMatchConfig(cfg MatchConfig) {
+	g.unitManager.Seed(cfg.Seed)
+
+	for _, owner := range []base.Owner{base.OwnerPlayer1, base.OwnerPlayer2} {
+		setup := cfg.Setup(owner)
+		g.baseManager.GetPlayer(owner).Credits = setup.Credits
+
+		team, _ := owner.Team()
+		var origin rl.Vector3
+		if hq := g.baseManager.GetHQ(owner); hq != nil {
+			origin = hq.Position
+		}
+
+		// Attack orders head for the opposing HQ
+		enemyHQ := g.baseManager.GetHQ(opposingOwner(owner))
+		deployUnits(g.unitManager, g.tileMap, setup.Units, team, origin, enemyHQ)
+	}
+}
+
+// deployGarrisons posts each base's neutral defenders in a ring around it,
+// holding position so they stay to guard it. Call after the map and bases
+// are created.
+func (g *Game) deployGarrisons() {
+	for _, b := range g.baseManager.Bases {
+		b.GarrisonIDs = b.GarrisonIDs[:0]
+		if len(b.Garrison) == 0 {
+			continue
+		}
+
+		defenders := make([]StartingUnit, len(b.Garrison))
+		for i, unitType := range b.Garrison {
+			angle := 2 * math.Pi * float64(i) / float64(len(b.Garrison))
+			defenders[i] = StartingUnit{
+				Type:   unitType,
+				Offset: rl.NewVector3(float32(math.Cos(angle))*garrisonRadius, 0, float32(math.Sin(angle))*garrisonRadius),
+				Order:  unit.OrderDefendPosition,
+			}
+		}
+
+		for _, u := range deployUnits(g.unitManager, g.tileMap, defenders, unit.TeamNeutral, b.Position, nil) {
+			b.GarrisonIDs = append(b.GarrisonIDs, u.ID)
+		}
+		b.Garrisoned = len(b.GarrisonIDs) > 0
+	}
+}
+
+// deployUnits spawns starting units around an origin, each moved onto the
+// nearest passable tile clear of other units, and gives them their orders.
+// Their path searches are queued so a large deployment spreads them over
+// several frames. Returns the units that were spawned.
+func deployUnits(um *unit.Manager, tm *tilemap.TileMap, units []StartingUnit, team unit.Team, origin rl.Vector3, enemyHQ *base.Base) []*unit.Unit {
+	spawned := make([]*unit.Unit, 0, len(units))
+	for _, su := range units {
+		pos := rl.Vector3{X: origin.X + su.Offset.X, Y: 0, Z: origin.Z + su.Offset.Z}
+		if tm != nil {
+			pos.X, pos.Z, _ = tm.NearestFree(pos.X, pos.Z, deploySearchRadius, func(x, z float32, terrain tilemap.TerrainType) bool {
+				return terrain.IsPassable() && len(um.GetUnitsInRadius(rl.Vector3{X: x, Z: z}, deployClearance)) == 0
+			})
+		}
+
+		u := um.Spawn(su.Type, team, pos)
+		if u == nil {
+			break // Unit cap reached
+		}
+		spawned = append(spawned, u)
+
+		if su.Order == unit.OrderNone {
+			continue
+		}
+		target := pos
+		if su.Order == unit.OrderAttackHQ && enemyHQ != nil {
+			target = enemyHQ.Position
+		}
+		u.SetOrder(su.Order, target)
+		um.RequestPath(u, target)
+	}
+	return spawned
+}
+
+// opposingOwner returns the other player
+func opposingOwner(owner base.Owner) base.Owner {
+	if owner == base.OwnerPlayer1 {
+		return base.OwnerPlayer2
+	}
+	return base.OwnerPlayer1
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestMatchConfigAppliesHandicaps(t *testing.T) {
+	g := &Game{
+		baseManager: base.NewManager(base.DefaultConfig()),
+		unitManager: unit.NewManager(10),
+	}
+	cfg := DefaultMatchConfig()
+	cfg.Player2.HealthMultiplier = 1.5
+	g.applyHandicaps(cfg)
+
+	hq := g.baseManager.AddBase(base.TypeHQ, rl.NewVector3(0, 0, 0), base.OwnerPlayer2)
+	if want := base.DefaultConfig().HQMaxHealth * 1.5; hq.MaxHealth != want {
+		t.Errorf("player 2's HQ has %v max health, want %v", hq.MaxHealth, want)
+	}
+	u := g.unitManager.Spawn(unit.TypeTank, unit.TeamEnemy, rl.NewVector3(0, 0, 0))
+	if want := unit.GetConfig(unit.TypeTank).MaxHealth * 1.5; u.MaxHealth != want || u.Health != want {
+		t.Errorf("player 2's tank at %v/%v, want full at %v", u.Health, u.MaxHealth, want)
+	}
+	if p1 := g.unitManager.Spawn(unit.TypeTank, unit.TeamPlayer, rl.NewVector3(0, 0, 0)); p1.MaxHealth != unit.GetConfig(unit.TypeTank).MaxHealth {
+		t.Errorf("player 1's tank has %v max health, want no handicap", p1.MaxHealth)
+	}
+}
+
+func TestMatchConfigChoosesMap(t *testing.T) {
+	want, err := tilemap.LoadBuiltin("badlands")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm, layout := loadMap("badlands")
+	for y := range tm.Tiles {
+		for x := range tm.Tiles[y] {
+			if tm.Tiles[y][x].Terrain != want.Map.Tiles[y][x].Terrain {
+				t.Fatalf("tile (%d, %d) isn't the badlands map", x, y)
+			}
+		}
+	}
+	if len(layout.Sites) != len(want.Layout.Sites) {
+		t.Errorf("%d sites, want the map's %d", len(layout.Sites), len(want.Layout.Sites))
+	}
+
+	// An unknown map falls back to the test map
+	tm, layout = loadMap("nowhere")
+	if tm.Width != mapWidth || tm.Height != mapHeight {
+		t.Errorf("fallback map is %dx%d, want %dx%d", tm.Width, tm.Height, mapWidth, mapHeight)
+	}
+	if err := tilemap.ValidateLayout(tm, layout); err != nil {
+		t.Errorf("fallback map: %v", err)
+	}
+}
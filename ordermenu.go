@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+const (
+	orderMenuRadius       = 90 // Pixels from the menu center to each option
+	orderMenuOptionRadius = 32 // Pixel radius of an option's button
+)
+
+// OrderMenu is a radial menu of orders for the current unit selection
+type OrderMenu struct {
+	Open    bool
+	Center  rl.Vector2
+	Options []unit.Order
+}
+
+// Show opens the menu at a screen position with the given options
+func (om *OrderMenu) Show(center rl.Vector2, options []unit.Order) {
+	if len(options) == 0 {
+		return
+	}
+	om.Open = true
+	om.Center = center
+	om.Options = options
+}
+
+// Close hides the menu
+func (om *OrderMenu) Close() {
+	om.Open = false
+	om.Options = nil
+}
+
+// optionPosition returns the screen position of the i-th option, starting
+// at the top and going clockwise
+func (om *OrderMenu) optionPosition(i int) rl.Vector2 {
+	angle := -math.Pi/2 + 2*math.Pi*float64(i)/float64(len(om.Options))
+	return rl.Vector2{
+		X: om.Center.X + orderMenuRadius*float32(math.Cos(angle)),
+		Y: om.Center.Y + orderMenuRadius*float32(math.Sin(angle)),
+	}
+}
+
+// OptionAt returns the order under a screen position, false if none
+func (om *OrderMenu) OptionAt(pos rl.Vector2) (unit.Order, bool) {
+	for i, order := range om.Options {
+		if rl.Vector2Distance(pos, om.optionPosition(i)) <= orderMenuOptionRadius {
+			return order, true
+		}
+	}
+	return unit.OrderNone, false
+}
+
+// Draw renders the open menu
+func (om *OrderMenu) Draw() {
+	if !om.Open {
+		return
+	}
+
+	mouse := rl.GetMousePosition()
+	rl.DrawCircleV(om.Center, 6, rl.White)
+
+	for i, order := range om.Options {
+		pos := om.optionPosition(i)
+		bg := rl.Color{R: 30, G: 30, B: 40, A: 220}
+		if rl.Vector2Distance(mouse, pos) <= orderMenuOptionRadius {
+			bg = rl.Color{R: 60, G: 90, B: 140, A: 240}
+		}
+
+		rl.DrawLine(int32(om.Center.X), int32(om.Center.Y), int32(pos.X), int32(pos.Y), rl.Gray)
+		rl.DrawCircleV(pos, orderMenuOptionRadius, bg)
+		rl.DrawCircleLines(int32(pos.X), int32(pos.Y), orderMenuOptionRadius, rl.White)
+
+		label := unit.OrderName(order)
+		textWidth := rl.MeasureText(label, 10)
+		rl.DrawText(label, int32(pos.X)-textWidth/2, int32(pos.Y)-5, 10, rl.White)
+	}
+}
+
+// handleOrderMenuInput opens the order menu for the selection on right
+// click and applies the clicked order to all selected units. Right clicks
+// on the minimap send the selection there instead.
+func (g *Game) handleOrderMenuInput() {
+	if !g.orderMenu.Open {
+		if rl.IsMouseButtonPressed(rl.MouseRightButton) {
+			if g.minimap.Contains(rl.GetMousePosition()) {
+				g.handleMinimapOrder()
+				return
+			}
+			selected := g.unitManager.GetSelected()
+			g.orderMenu.Show(rl.GetMousePosition(), unit.ValidOrders(selected))
+		}
+		return
+	}
+
+	// The menu owns the mouse while open, don't shoot through it
+	g.playerMech.InputShoot = false
+
+	if rl.IsMouseButtonPressed(rl.MouseRightButton) {
+		g.orderMenu.Close()
+		return
+	}
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if order, ok := g.orderMenu.OptionAt(rl.GetMousePosition()); ok {
+			switch order {
+			case unit.OrderGuard:
+				// Orders from the menu escort the player
+				g.unitManager.GuardSelected(unit.MechID)
+			case unit.OrderDefendPosition:
+				g.unitManager.OrderSelected(order)
+				g.faceSelectedTowardEnemy()
+			case unit.OrderGarrison:
+				// Infantry shelter in the nearest base the player holds
+				if nearestBase := g.findNearestOwnedBase(base.OwnerPlayer1); nearestBase != nil {
+					g.unitManager.GarrisonSelected(nearestBase.Position)
+				}
+			default:
+				g.unitManager.OrderSelected(order)
+			}
+		}
+		g.orderMenu.Close()
+	}
+}
+
+// handleMinimapOrder moves the selection to the point right clicked on the
+// minimap, shift makes it an attack-move
+func (g *Game) handleMinimapOrder() {
+	goal, ok := g.minimapOrderTarget(rl.GetMousePosition())
+	if !ok {
+		return
+	}
+	attack := rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+	g.unitManager.MoveSelected(goal, attack)
+}
+
+// minimapOrderTarget returns the world point a minimap click orders units
+// to, moved onto passable ground. Returns false for clicks off the minimap
+// or with no passable ground nearby.
+func (g *Game) minimapOrderTarget(screenPos rl.Vector2) (rl.Vector3, bool) {
+	worldX, worldZ, ok := g.minimap.ScreenToWorld(g.tileMap, screenPos)
+	if !ok {
+		return rl.Vector3{}, false
+	}
+	x, z, ok := g.tileMap.NearestPassable(worldX, worldZ, rallySnapRadius)
+	if !ok {
+		return rl.Vector3{}, false
+	}
+	return rl.NewVector3(x, 0, z), true
+}
+
+// faceSelectedTowardEnemy points selected defenders at the enemy HQ, the
+// likeliest direction for an attack to come from
+func (g *Game) faceSelectedTowardEnemy() {
+	hq := g.baseManager.GetHQ(base.OwnerPlayer2)
+	if hq == nil {
+		return
+	}
+	for _, u := range g.unitManager.GetSelected() {
+		if u.Order == unit.OrderDefendPosition {
+			u.GuardToward(hq.Position)
+		}
+	}
+}
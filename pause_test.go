@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/projectile"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// stepSystems advances the simulation systems by one frame, without input
+func stepSystems(g *Game, dt float32) {
+	g.playerMech.Update(dt)
+	g.unitManager.SetMech(g.playerMech.Position, !g.playerMech.IsDead())
+	g.unitManager.Update(dt)
+	g.baseManager.UpdateStationing(g.unitManager)
+	g.baseManager.UpdateCaptures(g.unitManager.GetUnits())
+	g.baseManager.Update(dt)
+	g.baseManager.UpdateDefenses(dt, g.unitManager.GetUnits())
+	g.combatSystem.Update(dt, g.playerMech, g.unitManager)
+}
+
+// simState prints every field of everything the systems update, timers
+// and all
+func simState(g *Game) string {
+	s := fmt.Sprintf("mech %+v\n", *g.playerMech)
+	for _, u := range g.unitManager.GetUnits() {
+		s += fmt.Sprintf("unit %+v\n", *u)
+	}
+	for _, b := range g.baseManager.Bases {
+		s += fmt.Sprintf("base %+v\n", *b)
+	}
+	s += fmt.Sprintf("players %+v %+v\n", *g.baseManager.GetPlayer(base.OwnerPlayer1), *g.baseManager.GetPlayer(base.OwnerPlayer2))
+	s += fmt.Sprintf("projectiles %+v\n", g.combatSystem.Projectiles.Active())
+	s += fmt.Sprintf("explosions %+v\n", g.combatSystem.GetExplosions())
+	s += fmt.Sprintf("respawn %v invulnerable %v", g.combatSystem.GetRespawnTimer(), g.combatSystem.GetInvulnTimer())
+	return s
+}
+
+func TestZeroDtChangesNothing(t *testing.T) {
+	g := newTestGame(t)
+
+	// Get timers running everywhere: units marching, a purchase building,
+	// shots and blasts in flight, and the mech waiting to respawn
+	hq := g.baseManager.GetHQ(base.OwnerPlayer1)
+	g.baseManager.TryPurchaseUnit(hq.ID, unit.TypeInfantry, base.OwnerPlayer1)
+	for i := 0; i < 30; i++ {
+		stepSystems(g, 1.0/60)
+	}
+	target := g.unitManager.Spawn(unit.TypeTank, unit.TeamEnemy, rl.NewVector3(10.5, 0, 10.5))
+	target.SpawnTimer = 0
+	target.State = unit.StateIdle
+	g.combatSystem.Projectiles.Fire(projectile.Projectile{
+		Position: rl.NewVector3(5.5, 0.5, 10.5),
+		Velocity: rl.NewVector3(20, 0, 0),
+		Damage:   10,
+		MaxLife:  2,
+		Team:     unit.TeamPlayer,
+	})
+	from := g.playerMech.Position
+	from.X -= 2
+	g.combatSystem.Projectiles.Fire(projectile.Projectile{
+		Position: from,
+		Velocity: rl.NewVector3(20, 0, 0),
+		Damage:   g.playerMech.MaxHealth * 10,
+		MaxLife:  1,
+		Team:     unit.TeamEnemy,
+	})
+	for i := 0; i < 5; i++ {
+		stepSystems(g, 1.0/60)
+	}
+	if !g.combatSystem.IsMechDead() || len(g.combatSystem.GetExplosions()) == 0 {
+		t.Fatal("setup left no respawn or explosion running")
+	}
+
+	before := simState(g)
+	for i := 0; i < 10; i++ {
+		stepSystems(g, 0)
+	}
+	if after := simState(g); after != before {
+		t.Errorf("paused frames changed the simulation:\nbefore %s\nafter  %s", before, after)
+	}
+}
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// CommanderConfig tunes how the AI runs its side
+type CommanderConfig struct {
+	ThinkInterval float32 // Seconds between decisions
+	Aggression    float32 // Share of new combat units sent at the enemy HQ, the rest defend (0-1)
+	SpendRate     float32 // How fast spending decisions come, 1 = the economy's own pace
+	RetakeSquad   int     // Defenders sent back to each base lost
+	Economy       EconomyConfig
+}
+
+// DefaultCommanderConfig returns the default AI commander tuning
+func DefaultCommanderConfig() CommanderConfig {
+	return CommanderConfig{
+		ThinkInterval: 0.5,
+		Aggression:    0.6,
+		SpendRate:     1.0,
+		RetakeSquad:   3,
+		Economy:       DefaultEconomyConfig(),
+	}
+}
+
+// Commander plays one side: it buys units through its economy, sends new
+// combat units to attack the enemy HQ or defend its own, and counterattacks
+// bases it loses
+type Commander struct {
+	Owner   base.Owner
+	Config  CommanderConfig
+	Economy *EconomyController
+
+	timer     float32
+	elapsed   float32      // Time since the last think
+	owned     map[int]bool // Base IDs held at the last think
+	attackers int          // Combat units ordered to attack so far
+	defenders int          // Combat units ordered to defend so far
+}
+
+// NewCommander creates an AI commander for an owner
+func NewCommander(owner base.Owner, cfg CommanderConfig) *Commander {
+	return &Commander{
+		Owner:   owner,
+		Config:  cfg,
+		Economy: NewEconomyController(owner, cfg.Economy),
+		owned:   make(map[int]bool),
+	}
+}
+
+// Reset clears decision state for a new match
+func (c *Commander) Reset() {
+	c.Economy.Reset()
+	c.timer = 0
+	c.elapsed = 0
+	c.owned = make(map[int]bool)
+	c.attackers = 0
+	c.defenders = 0
+}
+
+// Update thinks every ThinkInterval rather than every frame
+func (c *Commander) Update(dt float32, bm *base.Manager, um *unit.Manager) {
+	if dt <= 0 {
+		return // Paused, no decisions until time moves again
+	}
+	c.elapsed += dt
+	c.timer -= dt
+	if c.timer > 0 {
+		return
+	}
+	c.timer = c.Config.ThinkInterval
+	c.think(c.elapsed, bm, um)
+	c.elapsed = 0
+}
+
+// think makes one round of decisions covering the last elapsed seconds
+func (c *Commander) think(elapsed float32, bm *base.Manager, um *unit.Manager) {
+	team, ok := c.Owner.Team()
+	if !ok {
+		return
+	}
+
+	// The economy keeps its own clock, run faster or slower by SpendRate,
+	// and sends idle infantry to capture outposts
+	c.Economy.Update(elapsed*c.Config.SpendRate, bm, um)
+
+	c.retakeLostBases(bm, um, team)
+	c.assignIdle(bm, um, team)
+}
+
+// retakeLostBases sends defenders back to any base taken since the last
+// think
+func (c *Commander) retakeLostBases(bm *base.Manager, um *unit.Manager, team unit.Team) {
+	for _, b := range bm.Bases {
+		held := b.Owner == c.Owner && !b.IsDestroyed()
+		if c.owned[b.ID] && !held && !b.IsDestroyed() {
+			c.sendSquad(um, team, b.Position)
+		}
+		c.owned[b.ID] = held
+	}
+}
+
+// sendSquad orders up to RetakeSquad of the defenders nearest a position to
+// attack there
+func (c *Commander) sendSquad(um *unit.Manager, team unit.Team, pos rl.Vector3) {
+	for sent := 0; sent < c.Config.RetakeSquad; sent++ {
+		var nearest *unit.Unit
+		var nearestDist float32
+		for _, u := range um.GetUnitsByTeam(team) {
+			if u.Order != unit.OrderDefendPosition || u.IsCarried() {
+				continue
+			}
+			if d := u.DistanceToPoint(pos); nearest == nil || d < nearestDist {
+				nearest, nearestDist = u, d
+			}
+		}
+		if nearest == nil {
+			return
+		}
+		nearest.SetOrder(unit.OrderAttackNearest, pos)
+	}
+}
+
+// assignIdle gives each combat unit without an order one, attacking or
+// defending in the proportion set by Aggression. Capturers are left to the
+// economy while there are bases to take.
+func (c *Commander) assignIdle(bm *base.Manager, um *unit.Manager, team unit.Team) {
+	home := c.Economy.spawnBase(bm)
+	enemyHQ := bm.GetHQ(opponent(c.Owner))
+	capturing := len(c.Economy.LastPlan.CaptureTargets) > 0
+
+	for _, u := range um.GetUnitsByTeam(team) {
+		if u.Order != unit.OrderNone || u.IsCarried() {
+			continue
+		}
+		if _, fighting := u.InterruptedOrder(); fighting {
+			continue
+		}
+		if !u.CanFollowOrder(unit.OrderAttackHQ) || (capturing && u.Config.CanCapture) {
+			continue
+		}
+
+		attack := float32(c.attackers) < c.Config.Aggression*float32(c.attackers+c.defenders+1)
+		switch {
+		case attack && enemyHQ != nil:
+			u.SetOrder(unit.OrderAttackHQ, enemyHQ.Position)
+			c.attackers++
+		case home != nil:
+			u.SetOrder(unit.OrderDefendPosition, home.Position)
+			c.defenders++
+		}
+	}
+}
+
+// opponent returns the player an owner fights
+func opponent(owner base.Owner) base.Owner {
+	switch owner {
+	case base.OwnerPlayer1:
+		return base.OwnerPlayer2
+	case base.OwnerPlayer2:
+		return base.OwnerPlayer1
+	default:
+		return base.OwnerNeutral
+	}
+}
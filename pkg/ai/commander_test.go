@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// newMatch sets up the default map with both players on starting credits
+func newMatch() (*base.Manager, *unit.Manager) {
+	bm := base.NewManager(base.DefaultConfig())
+	bm.CreateDefaultMap(tilemap.GenerateTestMap(64, 48))
+	return bm, unit.NewManager(100)
+}
+
+// queued counts the units waiting at an owner's bases
+func queued(bm *base.Manager, owner base.Owner) int {
+	n := 0
+	for _, b := range bm.GetBasesOwnedBy(owner) {
+		n += len(b.SpawnQueue)
+	}
+	return n
+}
+
+// spawnQueued brings every unit queued at an owner's bases onto the field,
+// ready to take orders
+func spawnQueued(bm *base.Manager, um *unit.Manager, owner base.Owner) []*unit.Unit {
+	team, _ := owner.Team()
+	var spawned []*unit.Unit
+	for _, b := range bm.GetBasesOwnedBy(owner) {
+		for len(b.SpawnQueue) > 0 {
+			b.SpawnCooldown = 0
+			ut, pos, _ := b.TrySpawn(bm.Config)
+			u := um.Spawn(ut, team, pos)
+			u.SpawnTimer = 0
+			u.State = unit.StateIdle
+			spawned = append(spawned, u)
+		}
+	}
+	return spawned
+}
+
+func TestCommanderBuysWithStartingCredits(t *testing.T) {
+	bm, um := newMatch()
+	c := NewCommander(base.OwnerPlayer2, DefaultCommanderConfig())
+
+	c.Update(0.1, bm, um)
+	if queued(bm, base.OwnerPlayer2) == 0 {
+		t.Fatal("no units queued with starting credits")
+	}
+	if bm.Player2.Credits >= base.StartingCredits {
+		t.Errorf("credits still at %v after buying", bm.Player2.Credits)
+	}
+	if queued(bm, base.OwnerPlayer1) != 0 {
+		t.Error("bought units for the other side")
+	}
+}
+
+func TestCommanderOrdersNewUnits(t *testing.T) {
+	bm, um := newMatch()
+	c := NewCommander(base.OwnerPlayer2, DefaultCommanderConfig())
+
+	c.Update(0.1, bm, um)
+	spawned := spawnQueued(bm, um, base.OwnerPlayer2)
+	if len(spawned) == 0 {
+		t.Fatal("nothing spawned")
+	}
+
+	// Run long enough for the economy to make another decision too
+	for i := 0; i < 10; i++ {
+		c.Update(c.Config.ThinkInterval, bm, um)
+	}
+
+	enemyHQ := bm.GetHQ(base.OwnerPlayer1)
+	for _, u := range spawned {
+		if u.Order == unit.OrderNone {
+			t.Errorf("%v %d has no order", u.Config.Type, u.ID)
+			continue
+		}
+		if !u.CanFollowOrder(u.Order) {
+			t.Errorf("%v %d given %s, which it can't follow", u.Config.Type, u.ID, unit.OrderName(u.Order))
+		}
+
+		switch u.Order {
+		case unit.OrderAttackHQ:
+			if u.OrderTarget != enemyHQ.Position {
+				t.Errorf("%v %d attacking %v, not the enemy HQ", u.Config.Type, u.ID, u.OrderTarget)
+			}
+		case unit.OrderCaptureOutpost, unit.OrderDefendPosition:
+			b := bm.GetBaseAt(u.OrderTarget, 0.1)
+			if b == nil {
+				t.Errorf("%v %d sent to %v, where there's no base", u.Config.Type, u.ID, u.OrderTarget)
+			} else if capture := u.Order == unit.OrderCaptureOutpost; capture == (b.Owner == base.OwnerPlayer2) {
+				t.Errorf("%v %d given %s at a base owned by %v", u.Config.Type, u.ID, unit.OrderName(u.Order), b.Owner)
+			}
+		default:
+			t.Errorf("%v %d given unexpected %s", u.Config.Type, u.ID, unit.OrderName(u.Order))
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package ai
+
+import (
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// Phase is the AI's current economic strategy
+type Phase int
+
+const (
+	PhaseExpand   Phase = iota // Poor: grab outposts for income
+	PhaseMilitary              // Rich: spend on an army
+)
+
+// String returns the display name for a phase
+func (p Phase) String() string {
+	switch p {
+	case PhaseExpand:
+		return "Expand"
+	case PhaseMilitary:
+		return "Military"
+	default:
+		return "Unknown"
+	}
+}
+
+// militaryTypes are bought in rotation once the AI can afford an army
+var militaryTypes = []unit.UnitType{unit.TypeTank, unit.TypeMotorcycle, unit.TypeTank, unit.TypeSAM}
+
+// EconomyConfig tunes how the AI spends its credits
+type EconomyConfig struct {
+	RichIncome       float32 // Credits per second at which the AI switches to military spending
+	SaveFraction     float32 // Share of credits held back while expanding
+	IdleCreditCap    float32 // Credits above this are spent whatever the phase
+	CapturersPerBase int     // Infantry bought per outpost being taken
+	DecisionInterval float32 // Seconds between spending decisions
+}
+
+// DefaultEconomyConfig returns the default AI economy tuning
+func DefaultEconomyConfig() EconomyConfig {
+	return EconomyConfig{
+		RichIncome:       35.0, // HQ plus two outposts
+		SaveFraction:     0.3,
+		IdleCreditCap:    1500.0,
+		CapturersPerBase: 2,
+		DecisionInterval: 3.0,
+	}
+}
+
+// Plan is one spending decision
+type Plan struct {
+	Phase          Phase
+	Purchases      []unit.UnitType // Affordable within this decision's budget
+	CaptureTargets []*base.Base    // Capturable bases, most valuable first
+}
+
+// PlanEconomy decides what an owner should buy given its income and credits.
+// While income is below RichIncome and there are bases to take, it saves some
+// credits and buys infantry to capture the most valuable outposts first;
+// after that it spends on military units. Purchases never exceed credits.
+func PlanEconomy(bm *base.Manager, owner base.Owner, cfg EconomyConfig) Plan {
+	plan := Plan{CaptureTargets: captureTargets(bm, owner)}
+
+	income := bm.IncomeRate(owner)
+	credits := bm.GetCredits(owner)
+
+	plan.Phase = PhaseMilitary
+	if income < cfg.RichIncome && len(plan.CaptureTargets) > 0 {
+		plan.Phase = PhaseExpand
+	}
+
+	budget := credits
+	if plan.Phase == PhaseExpand {
+		budget = credits * (1 - cfg.SaveFraction)
+	}
+	if credits > cfg.IdleCreditCap {
+		budget = credits // Don't let credits sit idle
+	}
+
+	if plan.Phase == PhaseExpand {
+		cost := base.UnitCost(unit.TypeInfantry)
+		wanted := len(plan.CaptureTargets) * cfg.CapturersPerBase
+		for len(plan.Purchases) < wanted && budget >= cost {
+			plan.Purchases = append(plan.Purchases, unit.TypeInfantry)
+			budget -= cost
+		}
+		return plan
+	}
+
+	// Rotate through military types, skipping any we can't afford or
+	// haven't unlocked
+	for i := 0; ; i++ {
+		bought := false
+		for j := range militaryTypes {
+			ut := militaryTypes[(i+j)%len(militaryTypes)]
+			if bm.CheckRequirements(owner, ut) != nil {
+				continue
+			}
+			if cost := base.UnitCost(ut); cost <= budget {
+				plan.Purchases = append(plan.Purchases, ut)
+				budget -= cost
+				bought = true
+				break
+			}
+		}
+		if !bought {
+			break
+		}
+	}
+	return plan
+}
+
+// captureTargets returns bases the owner could capture, ordered by income per
+// unit of distance from the owner's HQ so rich, close outposts come first
+func captureTargets(bm *base.Manager, owner base.Owner) []*base.Base {
+	var origin rl.Vector3
+	if hq := bm.GetHQ(owner); hq != nil {
+		origin = hq.Position
+	}
+
+	targets := make([]*base.Base, 0)
+	for _, b := range bm.Bases {
+		if b.Type == base.TypeHQ || b.Owner != base.OwnerNeutral {
+			continue
+		}
+		targets = append(targets, b)
+	}
+
+	score := func(b *base.Base) float32 {
+		dx := b.Position.X - origin.X
+		dz := b.Position.Z - origin.Z
+		return (dx*dx + dz*dz + 1) / (b.IncomeRate * b.IncomeRate)
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return score(targets[i]) < score(targets[j])
+	})
+	return targets
+}
+
+// EconomyController runs an owner's economy over time
+type EconomyController struct {
+	Owner  base.Owner
+	Config EconomyConfig
+
+	LastPlan Plan
+	timer    float32
+}
+
+// NewEconomyController creates an economy AI for an owner
+func NewEconomyController(owner base.Owner, cfg EconomyConfig) *EconomyController {
+	return &EconomyController{
+		Owner:  owner,
+		Config: cfg,
+	}
+}
+
+// Reset clears decision state for a new match
+func (c *EconomyController) Reset() {
+	c.LastPlan = Plan{}
+	c.timer = 0
+}
+
+// Update periodically plans and carries out purchases, and sends idle
+// infantry to capture the top targets
+func (c *EconomyController) Update(dt float32, bm *base.Manager, um *unit.Manager) {
+	if dt <= 0 {
+		return // Paused, no decisions until time moves again
+	}
+	c.timer -= dt
+	if c.timer > 0 {
+		return
+	}
+	c.timer = c.Config.DecisionInterval
+
+	c.LastPlan = PlanEconomy(bm, c.Owner, c.Config)
+
+	if spawnBase := c.spawnBase(bm); spawnBase != nil {
+		for _, ut := range c.LastPlan.Purchases {
+			if !bm.TryPurchaseUnit(spawnBase.ID, ut, c.Owner) {
+				break
+			}
+		}
+	}
+
+	c.assignCapturers(um)
+}
+
+// spawnBase returns where the AI buys units, preferring its HQ
+func (c *EconomyController) spawnBase(bm *base.Manager) *base.Base {
+	if hq := bm.GetHQ(c.Owner); hq != nil {
+		return hq
+	}
+	for _, b := range bm.GetBasesOwnedBy(c.Owner) {
+		if b.CanSpawn() && !b.IsDestroyed() {
+			return b
+		}
+	}
+	return nil
+}
+
+// assignCapturers spreads idle infantry across the capture targets
+func (c *EconomyController) assignCapturers(um *unit.Manager) {
+	targets := c.LastPlan.CaptureTargets
+	team, ok := c.Owner.Team()
+	if len(targets) == 0 || !ok {
+		return
+	}
+
+	next := 0
+	for _, u := range um.GetUnitsByTeam(team) {
+		if !u.Config.CanCapture || u.IsCarried() || u.Order != unit.OrderNone {
+			continue
+		}
+		u.SetOrder(unit.OrderCaptureOutpost, targets[next%len(targets)].Position)
+		next++
+	}
+}
@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// spend totals the cost of a list of purchases
+func spend(purchases []unit.UnitType) float32 {
+	total := float32(0)
+	for _, ut := range purchases {
+		total += base.UnitCost(ut)
+	}
+	return total
+}
+
+func TestPoorAITakesIncomeBasesFirst(t *testing.T) {
+	bm := base.NewManager(base.DefaultConfig())
+	bm.AddBase(base.TypeHQ, rl.NewVector3(0, 0, 0), base.OwnerPlayer2)
+	bm.AddBase(base.TypeHQ, rl.NewVector3(0, 0, 60), base.OwnerPlayer1)
+	far := bm.AddBase(base.TypeOutpost, rl.NewVector3(30, 0, 0), base.OwnerNeutral)
+	near := bm.AddBase(base.TypeOutpost, rl.NewVector3(8, 0, 0), base.OwnerNeutral)
+	rich := bm.AddBase(base.TypeResource, rl.NewVector3(-12, 0, 0), base.OwnerNeutral)
+	bm.AddBase(base.TypeOutpost, rl.NewVector3(8, 0, 60), base.OwnerPlayer1)
+
+	plan := PlanEconomy(bm, base.OwnerPlayer2, DefaultEconomyConfig())
+	if plan.Phase != PhaseExpand {
+		t.Fatalf("poor AI is in phase %v, want expand", plan.Phase)
+	}
+	want := []*base.Base{rich, near, far}
+	if len(plan.CaptureTargets) != len(want) {
+		t.Fatalf("%d capture targets, want the %d neutral bases", len(plan.CaptureTargets), len(want))
+	}
+	for i, b := range want {
+		if plan.CaptureTargets[i] != b {
+			t.Errorf("target %d is base %d, want %d", i, plan.CaptureTargets[i].ID, b.ID)
+		}
+	}
+	if len(plan.Purchases) == 0 {
+		t.Fatal("poor AI buys nothing to capture with")
+	}
+	for _, ut := range plan.Purchases {
+		if ut != unit.TypeInfantry {
+			t.Errorf("poor AI buys %s, want only infantry to capture with", unit.TypeName(ut))
+		}
+	}
+}
+
+func TestRichAIBuysArmy(t *testing.T) {
+	bm, _ := newMatch()
+	for _, b := range bm.Bases {
+		if b.Type != base.TypeHQ && b.Owner == base.OwnerNeutral && bm.IncomeRate(base.OwnerPlayer2) < DefaultEconomyConfig().RichIncome {
+			b.SetOwner(base.OwnerPlayer2)
+		}
+	}
+	if bm.IncomeRate(base.OwnerPlayer2) < DefaultEconomyConfig().RichIncome {
+		t.Fatal("couldn't give player 2 enough income")
+	}
+	bm.GetPlayer(base.OwnerPlayer2).Credits = 1000
+
+	plan := PlanEconomy(bm, base.OwnerPlayer2, DefaultEconomyConfig())
+	if plan.Phase != PhaseMilitary {
+		t.Fatalf("rich AI is in phase %v, want military", plan.Phase)
+	}
+	if len(plan.Purchases) == 0 {
+		t.Fatal("rich AI buys nothing")
+	}
+	for _, ut := range plan.Purchases {
+		if ut == unit.TypeInfantry {
+			t.Error("rich AI buys infantry, want military units")
+		}
+	}
+}
+
+func TestAIStaysWithinCredits(t *testing.T) {
+	for _, credits := range []float64{0, 30, 250, 999, 5000} {
+		bm, um := newMatch()
+		bm.GetPlayer(base.OwnerPlayer2).Credits = credits
+
+		plan := PlanEconomy(bm, base.OwnerPlayer2, DefaultEconomyConfig())
+		if cost := spend(plan.Purchases); float64(cost) > credits {
+			t.Errorf("with %v credits the plan costs %v", credits, cost)
+		}
+
+		c := NewEconomyController(base.OwnerPlayer2, DefaultEconomyConfig())
+		c.Update(1, bm, um)
+		if left := bm.GetCredits(base.OwnerPlayer2); left < 0 {
+			t.Errorf("with %v credits the AI is left with %v", credits, left)
+		}
+		if credits > float64(DefaultEconomyConfig().IdleCreditCap) && queued(bm, base.OwnerPlayer2) == 0 {
+			t.Errorf("with %v credits the AI queued nothing", credits)
+		}
+	}
+}
@@ -13,6 +13,7 @@ type Manager struct {
 	models   map[string]rl.Model
 	textures map[string]rl.Texture2D
 	sounds   map[string]rl.Sound
+	targets  map[string]rl.RenderTexture2D
 }
 
 // NewManager creates a new asset manager with the given base path
@@ -22,6 +23,7 @@ func NewManager(basePath string) *Manager {
 		models:   make(map[string]rl.Model),
 		textures: make(map[string]rl.Texture2D),
 		sounds:   make(map[string]rl.Sound),
+		targets:  make(map[string]rl.RenderTexture2D),
 	}
 }
 
@@ -76,7 +78,29 @@ func (m *Manager) LoadSound(name string) (rl.Sound, error) {
 	return snd, nil
 }
 
-// Unload releases all loaded assets
+// LoadRenderTexture creates an offscreen render target the manager owns and
+// unloads with everything else. Asking again for the same name and size
+// returns the existing target; a new size replaces it.
+func (m *Manager) LoadRenderTexture(name string, width, height int32) (rl.RenderTexture2D, error) {
+	if target, ok := m.targets[name]; ok {
+		if target.Texture.Width == width && target.Texture.Height == height {
+			return target, nil
+		}
+		rl.UnloadRenderTexture(target)
+		delete(m.targets, name)
+	}
+
+	target := rl.LoadRenderTexture(width, height)
+
+	if target.ID == 0 {
+		return target, fmt.Errorf("failed to create render texture: %s (%dx%d)", name, width, height)
+	}
+
+	m.targets[name] = target
+	return target, nil
+}
+
+// Unload releases all loaded assets. Safe to call more than once.
 func (m *Manager) Unload() {
 	for _, model := range m.models {
 		rl.UnloadModel(model)
@@ -87,10 +111,14 @@ func (m *Manager) Unload() {
 	for _, snd := range m.sounds {
 		rl.UnloadSound(snd)
 	}
+	for _, target := range m.targets {
+		rl.UnloadRenderTexture(target)
+	}
 
 	m.models = make(map[string]rl.Model)
 	m.textures = make(map[string]rl.Texture2D)
 	m.sounds = make(map[string]rl.Sound)
+	m.targets = make(map[string]rl.RenderTexture2D)
 }
 
 // GetModel returns a cached model by name
@@ -105,6 +133,12 @@ func (m *Manager) GetTexture(name string) (rl.Texture2D, bool) {
 	return tex, ok
 }
 
+// GetRenderTexture returns a render target by name
+func (m *Manager) GetRenderTexture(name string) (rl.RenderTexture2D, bool) {
+	target, ok := m.targets[name]
+	return target, ok
+}
+
 // GetSound returns a cached sound by name
 func (m *Manager) GetSound(name string) (rl.Sound, bool) {
 	snd, ok := m.sounds[name]
@@ -0,0 +1,115 @@
+package audio
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/assets"
+)
+
+// Raylib pans from 1.0 (left) through 0.5 (center) to 0.0 (right)
+const panCenter = 0.5
+
+// Config holds spatial audio tuning
+type Config struct {
+	FullVolumeDistance float32 // Sounds this close to the listener play at full volume
+	MaxDistance        float32 // Sounds beyond this are silent
+	PanStrength        float32 // 0 = always centered, 1 = hard left/right at the sides
+	Volume             float32 // Master volume applied to every sound
+}
+
+// DefaultConfig returns the default audio configuration
+func DefaultConfig() Config {
+	return Config{
+		FullVolumeDistance: 8.0,
+		MaxDistance:        45.0,
+		PanStrength:        0.8,
+		Volume:             1.0,
+	}
+}
+
+// Spatialize returns the volume (0-1) and raylib pan for a sound at source
+// heard by a listener at listener facing yaw (0 looks toward +Z).
+// Only the ground plane is considered.
+func Spatialize(listener rl.Vector3, yaw float32, source rl.Vector3, cfg Config) (volume, pan float32) {
+	dx := source.X - listener.X
+	dz := source.Z - listener.Z
+	dist := float32(math.Sqrt(float64(dx*dx + dz*dz)))
+
+	// Linear falloff between the full volume and silent distances
+	volume = cfg.Volume
+	if dist >= cfg.MaxDistance {
+		volume = 0
+	} else if dist > cfg.FullVolumeDistance {
+		volume *= 1 - (dist-cfg.FullVolumeDistance)/(cfg.MaxDistance-cfg.FullVolumeDistance)
+	}
+
+	if dist < 0.001 {
+		return volume, panCenter
+	}
+
+	// How far the source sits to the listener's right, -1 to 1
+	rightX := -float32(math.Cos(float64(yaw)))
+	rightZ := float32(math.Sin(float64(yaw)))
+	side := (dx*rightX + dz*rightZ) / dist
+
+	return volume, panCenter - side*cfg.PanStrength*0.5
+}
+
+// Player plays sounds from the asset manager relative to a listener
+type Player struct {
+	Config Config
+
+	// Listener, usually the camera target and heading
+	Listener    rl.Vector3
+	ListenerYaw float32
+
+	assets  *assets.Manager
+	missing map[string]bool // Sounds that failed to load, not retried
+}
+
+// NewPlayer creates a sound player backed by an asset manager
+func NewPlayer(a *assets.Manager) *Player {
+	return &Player{
+		Config:  DefaultConfig(),
+		assets:  a,
+		missing: make(map[string]bool),
+	}
+}
+
+// SetListener moves the listener
+func (p *Player) SetListener(pos rl.Vector3, yaw float32) {
+	p.Listener = pos
+	p.ListenerYaw = yaw
+}
+
+// PlayAt plays a sound emitted at a world position
+func (p *Player) PlayAt(name string, pos rl.Vector3) {
+	volume, pan := Spatialize(p.Listener, p.ListenerYaw, pos, p.Config)
+	if volume <= 0 {
+		return
+	}
+	p.play(name, volume, pan)
+}
+
+// PlayUI plays a non-positional sound at full volume, centered
+func (p *Player) PlayUI(name string) {
+	p.play(name, p.Config.Volume, panCenter)
+}
+
+func (p *Player) play(name string, volume, pan float32) {
+	if !rl.IsAudioDeviceReady() || p.missing[name] {
+		return
+	}
+
+	snd, err := p.assets.LoadSound(name)
+	if err != nil {
+		p.missing[name] = true
+		return
+	}
+
+	rl.SetSoundVolume(snd, volume)
+	rl.SetSoundPan(snd, pan)
+	rl.PlaySound(snd)
+}
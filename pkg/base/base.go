@@ -1,8 +1,12 @@
 package base
 
 import (
+	"math"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 
+	"github.com/chazu/herzog-drei/pkg/graphics"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
 	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
@@ -15,40 +19,146 @@ const (
 	OwnerPlayer2
 )
 
+// Team returns the unit team an owner fields, false for neutral
+func (o Owner) Team() (unit.Team, bool) {
+	switch o {
+	case OwnerPlayer1:
+		return unit.TeamPlayer, true
+	case OwnerPlayer2:
+		return unit.TeamEnemy, true
+	default:
+		return 0, false
+	}
+}
+
+// String returns the display name for an owner
+func (o Owner) String() string {
+	switch o {
+	case OwnerNeutral:
+		return "Neutral"
+	case OwnerPlayer1:
+		return "Player 1"
+	case OwnerPlayer2:
+		return "Player 2"
+	default:
+		return "Unknown"
+	}
+}
+
+// OwnerOfTeam returns the owner that fields a unit team
+func OwnerOfTeam(t unit.Team) Owner {
+	switch t {
+	case unit.TeamPlayer:
+		return OwnerPlayer1
+	case unit.TeamEnemy:
+		return OwnerPlayer2
+	default:
+		return OwnerNeutral
+	}
+}
+
 // Type represents the kind of base
 type Type int
 
 const (
 	TypeHQ      Type = iota // Main base, losing it = game over
 	TypeOutpost             // Capturable, generates income, spawns units
+	TypeResource            // Capturable, generates extra income, no spawning
+)
+
+// String returns the display name for a base type
+func (t Type) String() string {
+	switch t {
+	case TypeHQ:
+		return "HQ"
+	case TypeOutpost:
+		return "Outpost"
+	case TypeResource:
+		return "Resource Point"
+	default:
+		return "Unknown"
+	}
+}
+
+// VisualState is which form a base is drawn in
+type VisualState int
+
+const (
+	VisualIntact VisualState = iota
+	VisualUnderConstruction
+	VisualUpgrading
+	VisualDestroyed
 )
 
 // Config holds configuration for base behavior
 type Config struct {
 	// Income
-	OutpostIncomeRate float32 // Credits per second for outposts
-	HQIncomeRate      float32 // Credits per second for HQ
+	OutpostIncomeRate  float32 // Credits per second for outposts
+	HQIncomeRate       float32 // Credits per second for HQ
+	ResourceIncomeRate float32 // Credits per second for resource points
 
 	// Capture
-	CaptureTime float32 // Seconds to capture when fully occupied
+	CaptureTime    float32 // Seconds to capture when fully occupied
+	CaptureRadius  float32 // Infantry within this distance occupy the base
+	TransitionTime float32 // Seconds the ownership change animation plays
 
 	// Health
 	HQMaxHealth      float32
 	OutpostMaxHealth float32
 
+	// Turrets (range 0 = none)
+	HQTurretRange       float32
+	HQTurretDamage      float32
+	OutpostTurretRange  float32
+	OutpostTurretDamage float32
+	TurretRate          float32 // Shots per second
+
+	// Friendly infantry sheltering inside a base
+	MaxStationed         int     // Most infantry a base can hold
+	StationedTurretBonus float32 // Extra turret damage per stationed infantry, as a fraction
+
 	// Spawn
-	SpawnCooldown float32 // Minimum time between spawns
+	SpawnCooldown       float32      // Minimum time between spawns
+	HQSpawnOffsets      []rl.Vector3 // HQ spawn points, relative to the base with +Z toward the map center
+	OutpostSpawnOffsets []rl.Vector3 // Outpost spawn points, same convention
+
+	// Tech gating: what an owner must hold to buy each unit type
+	Requirements map[unit.UnitType]Requirement
 }
 
+// spawnSearchRadius is how many tiles a spawn point may move to find
+// passable ground
+const spawnSearchRadius = 4
+
 // DefaultConfig returns the default base configuration
 func DefaultConfig() Config {
 	return Config{
-		OutpostIncomeRate: 15.0, // Credits per second for outposts
-		HQIncomeRate:      5.0,  // Credits per second for HQ
-		CaptureTime:       5.0,
-		HQMaxHealth:       500.0,
-		OutpostMaxHealth:  200.0,
-		SpawnCooldown:     2.0, // Slightly faster spawns
+		OutpostIncomeRate:  15.0, // Credits per second for outposts
+		HQIncomeRate:       5.0,  // Credits per second for HQ
+		ResourceIncomeRate: 40.0, // Worth fighting over
+		CaptureTime:        5.0,
+		CaptureRadius:      3.0,
+		TransitionTime:     1.0,
+		HQMaxHealth:        500.0,
+		OutpostMaxHealth:   200.0,
+		SpawnCooldown:      2.0, // Slightly faster spawns
+
+		// Light defenses, enough to see off a lone raider
+		HQTurretRange:       8.0,
+		HQTurretDamage:      10.0,
+		OutpostTurretRange:  6.0,
+		OutpostTurretDamage: 6.0,
+		TurretRate:          1.0,
+
+		// A full base hits twice as hard
+		MaxStationed:         4,
+		StationedTurretBonus: 0.25,
+
+		// Spread out so a busy base doesn't stack units on one tile
+		HQSpawnOffsets:      []rl.Vector3{{X: -1.5, Z: 3}, {X: 0, Z: 3}, {X: 1.5, Z: 3}},
+		OutpostSpawnOffsets: []rl.Vector3{{X: -1, Z: 2}, {X: 1, Z: 2}},
+
+		Requirements: DefaultRequirements(),
 	}
 }
 
@@ -66,60 +176,158 @@ type Base struct {
 	CaptureProgress float32 // 0.0 to 1.0, who is capturing
 	CapturingOwner  Owner   // Who is currently capturing (if any)
 
+	// Ownership change animation
+	PreviousOwner   Owner   // Owner before the last capture
+	TransitionTimer float32 // Seconds left in the change animation
+	transitionTime  float32 // Length of the current animation
+
 	// State
-	Health    float32
-	MaxHealth float32
+	Health      float32
+	MaxHealth   float32
+	damageTaken float32 // Since the last ConsumeDamage
+	healthScale float32 // Owner's handicap MaxHealth is scaled by
+
+	// Construction and upgrades
+	BuildProgress   float32 // 0 to 1, bases start fully built
+	UpgradeProgress float32 // 0 to 1 while Upgrading
+	Upgrading       bool
 
 	// Economy
 	IncomeRate     float32
-	AccumulatedIncome float32
+	AccumulatedIncome float64 // float64 so long matches don't drift
+	owedIncome     []IncomeShare // Earned for previous owners, not yet collected
 
 	// Spawning
-	SpawnPoint    rl.Vector3      // Where units spawn
+	SpawnPoint    rl.Vector3      // Primary spawn point, the first of SpawnPoints
+	SpawnPoints   []rl.Vector3    // Where units spawn, used in turn
+	nextSpawn     int             // Index of the spawn point used next
 	SpawnCooldown float32         // Time until next spawn allowed
 	SpawnQueue    []unit.UnitType // Units waiting to spawn
 
+	// Rally point spawned units are sent toward, and the order they get
+	RallyPoint rl.Vector3
+	RallyOrder unit.Order // OrderNone just moves them there
+	HasRally   bool
+
+	// Defensive turret
+	TurretRange    float32
+	TurretDamage   float32
+	TurretCooldown float32       // Seconds until the turret can fire again
+	TurretTarget   *unit.Unit    // What the turret is shooting at, nil if idle
+	Stance         DefenseStance // How eagerly the turret engages
+	threatTimer    float32       // Seconds the base still counts as under attack
+
 	// Infantry occupying this base (for capture mechanic)
 	OccupyingInfantry int   // Count of infantry inside
 	OccupyingOwner    Owner // Owner of occupying infantry
+
+	// Neutral defenders the map places here, they must be cleared before
+	// the base can be captured
+	Garrison    []unit.UnitType // Defenders deployed at match start
+	GarrisonIDs []uint32        // Units deployed for the garrison
+	Garrisoned  bool            // Some defenders are still alive
+
+	// The owner's infantry sheltering inside, each one boosting the turret
+	// and holding off one capturing infantry
+	Stationed []*unit.Unit
 }
 
 // NewBase creates a new base at the given position
 func NewBase(id int, baseType Type, position rl.Vector3, owner Owner, cfg Config) *Base {
-	var maxHealth, incomeRate float32
-	if baseType == TypeHQ {
+	var maxHealth, incomeRate, turretRange, turretDamage float32
+	switch baseType {
+	case TypeHQ:
 		maxHealth = cfg.HQMaxHealth
 		incomeRate = cfg.HQIncomeRate
-	} else {
+		turretRange, turretDamage = cfg.HQTurretRange, cfg.HQTurretDamage
+	case TypeResource:
+		maxHealth = cfg.OutpostMaxHealth
+		incomeRate = cfg.ResourceIncomeRate
+	default:
 		maxHealth = cfg.OutpostMaxHealth
 		incomeRate = cfg.OutpostIncomeRate
+		turretRange, turretDamage = cfg.OutpostTurretRange, cfg.OutpostTurretDamage
 	}
 
-	// Spawn point is slightly in front of the base
-	spawnPoint := rl.Vector3{
-		X: position.X,
-		Y: 0,
-		Z: position.Z + 2.0,
-	}
-
-	return &Base{
+	b := &Base{
 		ID:            id,
 		Type:          baseType,
 		Position:      position,
 		Owner:         owner,
 		Health:        maxHealth,
 		MaxHealth:     maxHealth,
+		healthScale:   1,
 		IncomeRate:    incomeRate,
-		SpawnPoint:    spawnPoint,
+		TurretRange:   turretRange,
+		TurretDamage:  turretDamage,
+		BuildProgress: 1.0,
 		SpawnQueue:    make([]unit.UnitType, 0, 8),
+		RallyOrder:    unit.OrderDefendPosition,
 	}
+
+	// Spawn points are slightly in front of the base
+	offsets := cfg.OutpostSpawnOffsets
+	if baseType == TypeHQ {
+		offsets = cfg.HQSpawnOffsets
+	}
+	b.SetSpawnOffsets(offsets)
+	return b
+}
+
+// SetSpawnOffsets places the base's spawn points. Offsets are relative to
+// the base, turned so +Z faces the map center. With no offsets the base
+// spawns just in front of itself.
+func (b *Base) SetSpawnOffsets(offsets []rl.Vector3) {
+	if len(offsets) == 0 {
+		offsets = []rl.Vector3{{Z: 2}}
+	}
+
+	// Face the map center, bases at the center face +Z
+	facing := 0.0
+	if b.Position.X != 0 || b.Position.Z != 0 {
+		facing = math.Atan2(float64(-b.Position.X), float64(-b.Position.Z))
+	}
+	sin, cos := float32(math.Sin(facing)), float32(math.Cos(facing))
+
+	b.SpawnPoints = make([]rl.Vector3, len(offsets))
+	for i, o := range offsets {
+		b.SpawnPoints[i] = rl.Vector3{
+			X: b.Position.X + o.X*cos + o.Z*sin,
+			Y: 0,
+			Z: b.Position.Z - o.X*sin + o.Z*cos,
+		}
+	}
+	b.SpawnPoint = b.SpawnPoints[0]
+	b.nextSpawn = 0
+}
+
+// ResolveSpawnPoints moves each spawn point onto the nearest passable tile
+func (b *Base) ResolveSpawnPoints(tm *tilemap.TileMap) {
+	for i, sp := range b.SpawnPoints {
+		if x, z, ok := tm.NearestPassable(sp.X, sp.Z, spawnSearchRadius); ok {
+			b.SpawnPoints[i] = rl.Vector3{X: x, Y: 0, Z: z}
+		}
+	}
+	b.SpawnPoint = b.SpawnPoints[0]
+}
+
+// NextSpawnPoint returns where the next unit will spawn
+func (b *Base) NextSpawnPoint() rl.Vector3 {
+	if len(b.SpawnPoints) == 0 {
+		return b.SpawnPoint
+	}
+	return b.SpawnPoints[b.nextSpawn%len(b.SpawnPoints)]
 }
 
 // Update updates the base state for the frame
 func (b *Base) Update(dt float32, cfg Config) {
-	// Generate income if owned
-	if b.Owner != OwnerNeutral {
-		b.AccumulatedIncome += b.IncomeRate * dt
+	if dt <= 0 {
+		return
+	}
+
+	// Generate income if owned and still standing
+	if b.Owner != OwnerNeutral && !b.IsDestroyed() {
+		b.AccumulatedIncome += float64(b.IncomeRate) * float64(dt)
 	}
 
 	// Update capture progress
@@ -129,6 +337,14 @@ func (b *Base) Update(dt float32, cfg Config) {
 	if b.SpawnCooldown > 0 {
 		b.SpawnCooldown -= dt
 	}
+
+	// Play out the ownership change animation
+	if b.TransitionTimer > 0 {
+		b.TransitionTimer -= dt
+		if b.TransitionTimer < 0 {
+			b.TransitionTimer = 0
+		}
+	}
 }
 
 func (b *Base) updateCapture(dt float32, cfg Config) {
@@ -156,6 +372,13 @@ func (b *Base) updateCapture(dt float32, cfg Config) {
 		return
 	}
 
+	// Infantry inside hold off as many attackers, capture stalls while
+	// they match or outnumber them
+	capturers := b.OccupyingInfantry - len(b.Stationed)
+	if capturers <= 0 {
+		return
+	}
+
 	// Enemy or neutral capturing
 	if b.CapturingOwner != b.OccupyingOwner {
 		// New capturer, reset progress
@@ -164,17 +387,110 @@ func (b *Base) updateCapture(dt float32, cfg Config) {
 	}
 
 	// Progress capture based on infantry count
-	captureSpeed := float32(b.OccupyingInfantry) * dt / cfg.CaptureTime
+	captureSpeed := float32(capturers) * dt / cfg.CaptureTime
 	b.CaptureProgress += captureSpeed
 
 	if b.CaptureProgress >= 1.0 {
-		// Capture complete!
-		b.Owner = b.CapturingOwner
-		b.CaptureProgress = 0
-		b.CapturingOwner = OwnerNeutral
-		b.OccupyingInfantry = 0 // Infantry "merge" into garrison
-		b.OccupyingOwner = OwnerNeutral
+		// Capture complete! Infantry "merge" into the garrison
+		b.startTransition(cfg.TransitionTime)
+		b.SetOwner(b.CapturingOwner)
+	}
+}
+
+// SetOwner hands the base to a new owner. Everything that was the old
+// owner's goes with it: queued units, rally point, upgrade, turret stance
+// and target, and infantry stationed inside. Any capture in progress ends.
+// Income earned so far is still the old owner's and is paid out to them at
+// the next collection, the new owner earning from here on. Doesn't play the
+// ownership change animation.
+func (b *Base) SetOwner(owner Owner) {
+	if owner == b.Owner {
+		return
+	}
+
+	if b.AccumulatedIncome > 0 {
+		b.owedIncome = append(b.owedIncome, IncomeShare{Owner: b.Owner, Amount: b.AccumulatedIncome})
+		b.AccumulatedIncome = 0
 	}
+	b.Owner = owner
+	b.SpawnQueue = b.SpawnQueue[:0]
+	b.SpawnCooldown = 0
+	b.HasRally = false
+	b.CancelUpgrade()
+	b.Stance = StanceFireAtWill
+	b.TurretTarget = nil
+	b.Stationed = nil
+
+	b.CaptureProgress = 0
+	b.CapturingOwner = OwnerNeutral
+	b.OccupyingInfantry = 0
+	b.OccupyingOwner = OwnerNeutral
+}
+
+// SetHealthScale applies an owner's health handicap, rescaling MaxHealth
+// from whatever scale it had before and keeping the share of health left
+func (b *Base) SetHealthScale(scale float32) {
+	if scale <= 0 || scale == b.healthScale {
+		return
+	}
+	ratio := scale / b.healthScale
+	b.MaxHealth *= ratio
+	b.Health *= ratio
+	b.healthScale = scale
+}
+
+// startTransition begins the ownership change animation from the current
+// owner, restarting it if one is already playing
+func (b *Base) startTransition(duration float32) {
+	b.PreviousOwner = b.Owner
+	b.TransitionTimer = duration
+	b.transitionTime = duration
+}
+
+// TransitionProgress returns how far the ownership change animation has
+// played, 0 to 1 (1 when no change is in progress)
+func (b *Base) TransitionProgress() float32 {
+	if b.TransitionTimer <= 0 || b.transitionTime <= 0 {
+		return 1
+	}
+	return 1 - b.TransitionTimer/b.transitionTime
+}
+
+// DisplayColor returns the base's color, blending from the previous owner's
+// while an ownership change animates
+func (b *Base) DisplayColor() rl.Color {
+	return graphics.LerpColor(OwnerColor(b.PreviousOwner), OwnerColor(b.Owner), b.TransitionProgress())
+}
+
+// FlagColor returns the flag's color: the old owner's while it's lowered,
+// the new owner's as it's raised
+func (b *Base) FlagColor() rl.Color {
+	if b.TransitionProgress() < 0.5 {
+		return OwnerColor(b.PreviousOwner)
+	}
+	return OwnerColor(b.Owner)
+}
+
+// FlagHeight returns how far up its pole the flag is, 0 to 1. During an
+// ownership change it lowers for the first half and raises for the second.
+func (b *Base) FlagHeight() float32 {
+	p := b.TransitionProgress()
+	if p < 0.5 {
+		return 1 - p*2
+	}
+	return p*2 - 1
+}
+
+// ClearQueue empties the spawn queue and returns the units that were waiting
+func (b *Base) ClearQueue() []unit.UnitType {
+	cleared := b.SpawnQueue
+	b.SpawnQueue = make([]unit.UnitType, 0, 8)
+	return cleared
+}
+
+// CanSpawn returns true if this kind of base can produce units
+func (b *Base) CanSpawn() bool {
+	return b.Type != TypeResource
 }
 
 // QueueUnit adds a unit to the spawn queue
@@ -182,17 +498,55 @@ func (b *Base) QueueUnit(unitType unit.UnitType) {
 	if b.Owner == OwnerNeutral {
 		return // Can't spawn from neutral bases
 	}
+	if !b.CanSpawn() {
+		return // Resource points only generate income
+	}
 	b.SpawnQueue = append(b.SpawnQueue, unitType)
 }
 
+// SetRallyPoint sets where units from this base should gather, keeping
+// the rally order
+func (b *Base) SetRallyPoint(pos rl.Vector3) {
+	b.SetRally(pos, b.RallyOrder)
+}
+
+// SetRally sets where units from this base should gather and the order
+// they're given there
+func (b *Base) SetRally(pos rl.Vector3, order unit.Order) {
+	b.RallyPoint = pos
+	b.RallyOrder = order
+	b.HasRally = true
+}
+
+// Rally sends a unit fresh from this base to its rally point, under the
+// rally order if the unit can follow it or just moving there if not.
+// Returns false if the base has no rally point.
+func (b *Base) Rally(u *unit.Unit) bool {
+	if !b.HasRally {
+		return false
+	}
+	if u.CanFollowOrder(b.RallyOrder) {
+		u.SetOrder(b.RallyOrder, b.RallyPoint)
+	} else {
+		u.SetObjective(b.RallyPoint)
+	}
+	return true
+}
+
+// ClearRallyPoint removes the base's rally point
+func (b *Base) ClearRallyPoint() {
+	b.HasRally = false
+}
+
 // TrySpawn attempts to spawn the next unit in queue
-// Returns the unit type and true if a spawn occurred
-func (b *Base) TrySpawn(cfg Config) (unit.UnitType, bool) {
-	if len(b.SpawnQueue) == 0 {
-		return 0, false
+// Returns the unit type, the spawn point it goes to, and true if a spawn
+// occurred. Spawn points are used in turn.
+func (b *Base) TrySpawn(cfg Config) (unit.UnitType, rl.Vector3, bool) {
+	if len(b.SpawnQueue) == 0 || b.IsDestroyed() {
+		return 0, rl.Vector3{}, false
 	}
 	if b.SpawnCooldown > 0 {
-		return 0, false
+		return 0, rl.Vector3{}, false
 	}
 
 	// Spawn the unit
@@ -200,29 +554,99 @@ func (b *Base) TrySpawn(cfg Config) (unit.UnitType, bool) {
 	b.SpawnQueue = b.SpawnQueue[1:]
 	b.SpawnCooldown = cfg.SpawnCooldown
 
-	return unitType, true
+	spawnPoint := b.NextSpawnPoint()
+	b.nextSpawn++
+
+	return unitType, spawnPoint, true
+}
+
+// IncomeShare is income a base earned for one owner
+type IncomeShare struct {
+	Owner  Owner
+	Amount float64
 }
 
 // CollectIncome collects and resets accumulated income
-func (b *Base) CollectIncome() float32 {
+func (b *Base) CollectIncome() float64 {
 	income := b.AccumulatedIncome
 	b.AccumulatedIncome = 0
 	return income
 }
 
-// TakeDamage applies damage to the base
+// CollectOwedIncome collects and resets the income the base earned for
+// previous owners before changing hands, nil if it hasn't since the last
+// collection
+func (b *Base) CollectOwedIncome() []IncomeShare {
+	owed := b.owedIncome
+	b.owedIncome = nil
+	return owed
+}
+
+// TakeDamage applies damage to the base in whole hit points. Any hit puts
+// the base under attack, waking a turret holding fire.
 func (b *Base) TakeDamage(amount float32) {
+	amount = unit.HitPoints(amount)
+	if amount > 0 {
+		b.threatTimer = threatMemory
+	}
 	b.Health -= amount
+	b.damageTaken += amount
 	if b.Health < 0 {
 		b.Health = 0
 	}
 }
 
+// ConsumeDamage returns the damage taken since the last call and resets it
+func (b *Base) ConsumeDamage() float32 {
+	d := b.damageTaken
+	b.damageTaken = 0
+	return d
+}
+
+// IsUnderConstruction returns true until the base is fully built
+func (b *Base) IsUnderConstruction() bool {
+	return b.BuildProgress < 1
+}
+
+// StartUpgrade begins upgrading the base from no progress
+func (b *Base) StartUpgrade() {
+	b.Upgrading = true
+	b.UpgradeProgress = 0
+}
+
+// CancelUpgrade abandons any upgrade in progress
+func (b *Base) CancelUpgrade() {
+	b.Upgrading = false
+	b.UpgradeProgress = 0
+}
+
+// VisualState returns how the base should be drawn. Destruction shows over
+// construction, and construction over an upgrade.
+func (b *Base) VisualState() VisualState {
+	switch {
+	case b.IsDestroyed():
+		return VisualDestroyed
+	case b.IsUnderConstruction():
+		return VisualUnderConstruction
+	case b.Upgrading:
+		return VisualUpgrading
+	default:
+		return VisualIntact
+	}
+}
+
 // IsDestroyed returns true if the base has no health
 func (b *Base) IsDestroyed() bool {
 	return b.Health <= 0
 }
 
+// FootprintRadius returns how far from its center the base's main
+// structure reaches
+func (b *Base) FootprintRadius() float32 {
+	w, _, d := footprint(b)
+	return float32(math.Max(float64(w), float64(d))) / 2
+}
+
 // SetOccupyingInfantry sets the infantry occupying this base for capture
 func (b *Base) SetOccupyingInfantry(count int, owner Owner) {
 	b.OccupyingInfantry = count
@@ -231,7 +655,12 @@ func (b *Base) SetOccupyingInfantry(count int, owner Owner) {
 
 // GetOwnerColor returns the color associated with the base's owner
 func (b *Base) GetOwnerColor() rl.Color {
-	switch b.Owner {
+	return OwnerColor(b.Owner)
+}
+
+// OwnerColor returns the color associated with an owner
+func OwnerColor(o Owner) rl.Color {
+	switch o {
 	case OwnerPlayer1:
 		return rl.Blue
 	case OwnerPlayer2:
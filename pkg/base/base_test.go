@@ -0,0 +1,125 @@
+package base
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// credits returns both players' credits
+func credits(m *Manager) (float32, float32) {
+	return m.GetCredits(OwnerPlayer1), m.GetCredits(OwnerPlayer2)
+}
+
+func TestOwnershipReroutesIncome(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	b := m.AddBase(TypeOutpost, rl.NewVector3(0, 0, 0), OwnerPlayer1)
+	rate := b.IncomeRate
+
+	p1, p2 := credits(m)
+	m.Update(1)
+	if got1, got2 := credits(m); got1-p1 != rate || got2 != p2 {
+		t.Fatalf("player 1 earned %v, player 2 %v, want %v to player 1", got1-p1, got2-p2, rate)
+	}
+
+	m.TransferBase(b, OwnerPlayer2)
+	p1, p2 = credits(m)
+	m.Update(1)
+	if got1, got2 := credits(m); got1 != p1 || got2-p2 != rate {
+		t.Fatalf("after transfer player 1 earned %v, player 2 %v, want %v to player 2", got1-p1, got2-p2, rate)
+	}
+}
+
+func TestCaptureKeepsIncomeEarnedBeforeIt(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	b := m.AddBase(TypeOutpost, rl.NewVector3(0, 0, 0), OwnerPlayer1)
+	rate := b.IncomeRate
+
+	// Player 2 finishes taking the base partway through the frame's accrual
+	b.OccupyingOwner, b.OccupyingInfantry = OwnerPlayer2, 1
+	b.CapturingOwner, b.CaptureProgress = OwnerPlayer2, 0.99
+	p1, p2 := credits(m)
+	m.Update(1)
+	if b.Owner != OwnerPlayer2 {
+		t.Fatal("base wasn't captured")
+	}
+	if got1, got2 := credits(m); got1-p1 != rate || got2 != p2 {
+		t.Errorf("player 1 earned %v, player 2 %v for the capture frame, want %v to player 1", got1-p1, got2-p2, rate)
+	}
+}
+
+func TestLongMatchIncomeIsExact(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	hq := m.AddBase(TypeHQ, rl.NewVector3(0, 0, 0), OwnerPlayer1)
+	outpost := m.AddBase(TypeOutpost, rl.NewVector3(20, 0, 0), OwnerPlayer1)
+
+	// Ten minutes at 60 frames a second, player 2 taking the outpost on the
+	// frame halfway through
+	const frames, captureFrame = 36000, 18000
+	dt := float32(1.0 / 60)
+	for i := 1; i <= frames; i++ {
+		if i == captureFrame {
+			outpost.OccupyingOwner, outpost.OccupyingInfantry = OwnerPlayer2, 1
+			outpost.CapturingOwner, outpost.CaptureProgress = OwnerPlayer2, 0.9999
+		}
+		m.Update(dt)
+		outpost.OccupyingInfantry = 0
+	}
+	if outpost.Owner != OwnerPlayer2 {
+		t.Fatal("outpost wasn't captured")
+	}
+
+	step := float64(dt)
+	want1 := StartingCredits + step*(frames*float64(hq.IncomeRate)+captureFrame*float64(outpost.IncomeRate))
+	want2 := StartingCredits + step*(frames-captureFrame)*float64(outpost.IncomeRate)
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"player 1", m.Player1.Credits, want1},
+		{"player 2", m.Player2.Credits, want2},
+	} {
+		if diff := c.got - c.want; diff > 1e-6 || diff < -1e-6 {
+			t.Errorf("%s has %.9f credits, want %.9f", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestHealthHandicapScalesBases(t *testing.T) {
+	cfg := DefaultConfig()
+	m := NewManager(cfg)
+	m.Player2.HealthMultiplier = 1.5
+
+	hq := m.AddBase(TypeHQ, rl.NewVector3(0, 0, 0), OwnerPlayer2)
+	if hq.MaxHealth != cfg.HQMaxHealth*1.5 || hq.Health != hq.MaxHealth {
+		t.Fatalf("handicapped HQ at %v/%v, want full at %v", hq.Health, hq.MaxHealth, cfg.HQMaxHealth*1.5)
+	}
+
+	// A captured base takes on its new owner's handicap, as hurt as before
+	outpost := m.AddBase(TypeOutpost, rl.NewVector3(20, 0, 0), OwnerPlayer1)
+	outpost.Health = outpost.MaxHealth / 2
+	m.TransferBase(outpost, OwnerPlayer2)
+	if outpost.MaxHealth != cfg.OutpostMaxHealth*1.5 || outpost.Health != outpost.MaxHealth/2 {
+		t.Errorf("captured outpost at %v/%v, want half of %v", outpost.Health, outpost.MaxHealth, cfg.OutpostMaxHealth*1.5)
+	}
+	m.TransferBase(outpost, OwnerPlayer1)
+	if outpost.MaxHealth != cfg.OutpostMaxHealth {
+		t.Errorf("recaptured outpost has %v max health, want %v", outpost.MaxHealth, cfg.OutpostMaxHealth)
+	}
+}
+
+func TestLethalDamageLeavesBaseAtZero(t *testing.T) {
+	b := NewBase(1, TypeOutpost, rl.NewVector3(0, 0, 0), OwnerPlayer2, DefaultConfig())
+	for i := 0; i < 10000 && !b.IsDestroyed(); i++ {
+		b.TakeDamage(3.5 * 0.7)
+	}
+	if b.Health != 0 || !b.IsDestroyed() {
+		t.Errorf("after lethal chip damage: %v health, destroyed %v", b.Health, b.IsDestroyed())
+	}
+
+	b = NewBase(2, TypeHQ, rl.NewVector3(0, 0, 0), OwnerPlayer2, DefaultConfig())
+	b.TakeDamage(b.Health + 0.4)
+	if b.Health != 0 || !b.IsDestroyed() {
+		t.Errorf("after lethal hit: %v health, destroyed %v", b.Health, b.IsDestroyed())
+	}
+}
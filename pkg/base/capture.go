@@ -0,0 +1,84 @@
+package base
+
+import (
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// CountInfantryNearBase counts capture-capable units within radius of a
+// base, split by player, whatever they're doing. Any of them there contests
+// the base.
+func CountInfantryNearBase(units []*unit.Unit, base *Base, radius float32) (p1, p2 int) {
+	return countInfantry(units, base, radius, false)
+}
+
+// CountCapturersNearBase counts capture-capable units holding ground within
+// radius of a base, split by player. Only units capturing or standing idle
+// count, so infantry passing through or fighting don't.
+func CountCapturersNearBase(units []*unit.Unit, base *Base, radius float32) (p1, p2 int) {
+	return countInfantry(units, base, radius, true)
+}
+
+// countInfantry counts capture-capable units near a base, only those
+// holding ground if holding is set
+func countInfantry(units []*unit.Unit, base *Base, radius float32, holding bool) (p1, p2 int) {
+	for _, u := range units {
+		if !u.Config.CanCapture || u.IsDead() || u.IsCarried() || u.IsSpawning() {
+			continue
+		}
+		if holding && u.State != unit.StateCapturing && u.State != unit.StateIdle {
+			continue
+		}
+		if u.DistanceToPoint(base.Position) > radius {
+			continue
+		}
+
+		switch u.Team {
+		case unit.TeamPlayer:
+			p1++
+		case unit.TeamEnemy:
+			p2++
+		}
+	}
+	return p1, p2
+}
+
+// GarrisonAlive counts a base's garrison defenders still alive
+func GarrisonAlive(units []*unit.Unit, base *Base) int {
+	alive := 0
+	for _, u := range units {
+		if u.IsDead() {
+			continue
+		}
+		for _, id := range base.GarrisonIDs {
+			if u.ID == id {
+				alive++
+				break
+			}
+		}
+	}
+	return alive
+}
+
+// UpdateCaptures sets each base's occupying infantry from the units holding
+// ground near it, which drives capture progress in Update. Contested bases
+// (both players' infantry present, fighting or not) count as unoccupied so
+// neither side makes capture progress, and so do bases whose garrison
+// still holds.
+func (m *Manager) UpdateCaptures(units []*unit.Unit) {
+	for _, base := range m.Bases {
+		base.Garrisoned = len(base.GarrisonIDs) > 0 && GarrisonAlive(units, base) > 0
+		p1, p2 := CountInfantryNearBase(units, base, m.Config.CaptureRadius)
+		c1, c2 := CountCapturersNearBase(units, base, m.Config.CaptureRadius)
+
+		switch {
+		case base.Garrisoned:
+			base.SetOccupyingInfantry(0, OwnerNeutral)
+		case p2 == 0 && c1 > 0:
+			base.SetOccupyingInfantry(c1, OwnerPlayer1)
+		case p1 == 0 && c2 > 0:
+			base.SetOccupyingInfantry(c2, OwnerPlayer2)
+		default:
+			base.SetOccupyingInfantry(0, OwnerNeutral)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package base
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// infantryAt returns ready infantry for a team in a state
+func infantryAt(id uint32, team unit.Team, pos rl.Vector3, state unit.State) *unit.Unit {
+	u := unit.New(id, unit.TypeInfantry, team, pos)
+	u.SpawnTimer = 0
+	u.State = state
+	return u
+}
+
+func TestFightingDefenderContestsCapture(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	b := m.AddBase(TypeOutpost, rl.NewVector3(0, 0, 0), OwnerPlayer1)
+
+	attacker := infantryAt(1, unit.TeamEnemy, rl.NewVector3(1, 0, 0), unit.StateCapturing)
+	defender := infantryAt(2, unit.TeamPlayer, rl.NewVector3(-1, 0, 0), unit.StateAttacking)
+	units := []*unit.Unit{attacker, defender}
+
+	m.UpdateCaptures(units)
+	if b.OccupyingInfantry != 0 {
+		t.Fatalf("contested base occupied by %d for %v", b.OccupyingInfantry, b.OccupyingOwner)
+	}
+
+	// With the defender gone the attacker takes the point
+	defender.Health = 0
+	m.UpdateCaptures(units)
+	if b.OccupyingInfantry != 1 || b.OccupyingOwner != OwnerPlayer2 {
+		t.Fatalf("occupied by %d for %v, want 1 for player 2", b.OccupyingInfantry, b.OccupyingOwner)
+	}
+}
+
+func TestPassingInfantryDoesNotCapture(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	b := m.AddBase(TypeOutpost, rl.NewVector3(0, 0, 0), OwnerNeutral)
+
+	m.UpdateCaptures([]*unit.Unit{infantryAt(1, unit.TeamPlayer, rl.NewVector3(1, 0, 0), unit.StateMoving)})
+	if b.OccupyingInfantry != 0 {
+		t.Fatal("infantry moving through occupies the base")
+	}
+}
+
+func TestCaptureOrderHoldsBase(t *testing.T) {
+	u := infantryAt(1, unit.TeamPlayer, rl.NewVector3(5, 0, 0), unit.StateIdle)
+	u.SetOrder(unit.OrderCaptureOutpost, rl.NewVector3(0, 0, 0))
+	for i := 0; i < 600 && u.State != unit.StateCapturing; i++ {
+		u.Update(1.0 / 60)
+	}
+	if u.State != unit.StateCapturing {
+		t.Fatalf("infantry at the outpost is %v, want capturing", u.State)
+	}
+
+	u.SetOrder(unit.OrderDefendPosition, u.Position)
+	if u.State == unit.StateCapturing {
+		t.Error("new order left the unit capturing")
+	}
+}
+
+func TestGarrisonBlocksCaptureUntilCleared(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	b := m.AddBase(TypeOutpost, rl.NewVector3(0, 0, 0), OwnerNeutral)
+	defenders := []*unit.Unit{
+		infantryAt(10, unit.TeamNeutral, rl.NewVector3(2.5, 0, 0), unit.StateIdle),
+		infantryAt(11, unit.TeamNeutral, rl.NewVector3(-2.5, 0, 0), unit.StateIdle),
+	}
+	b.GarrisonIDs = []uint32{10, 11}
+	capturer := infantryAt(1, unit.TeamPlayer, rl.NewVector3(0.5, 0, 0), unit.StateCapturing)
+	units := append([]*unit.Unit{capturer}, defenders...)
+
+	// hold keeps the capturer on the point for a number of seconds
+	hold := func(seconds int) {
+		for i := 0; i < seconds*60; i++ {
+			m.UpdateCaptures(units)
+			m.Update(1.0 / 60)
+		}
+	}
+
+	hold(30)
+	if b.Owner != OwnerNeutral || !b.Garrisoned {
+		t.Fatalf("garrisoned outpost owned by %v after 30s", b.Owner)
+	}
+
+	// One defender left still holds it
+	defenders[0].Health = 0
+	hold(30)
+	if b.Owner != OwnerNeutral {
+		t.Fatalf("outpost taken with a defender alive")
+	}
+
+	defenders[1].Health = 0
+	hold(30)
+	if b.Garrisoned || b.Owner != OwnerPlayer1 {
+		t.Errorf("cleared outpost owned by %v, garrisoned %v, want taken by player 1", b.Owner, b.Garrisoned)
+	}
+}
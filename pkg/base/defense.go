@@ -0,0 +1,114 @@
+package base
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// DefenseStance is how eagerly a base's turret engages enemies
+type DefenseStance int
+
+const (
+	StanceFireAtWill DefenseStance = iota // Fire at any enemy in range
+	StanceHoldFire                        // Fire only while the base is under attack
+)
+
+// String returns the display name for a stance
+func (s DefenseStance) String() string {
+	switch s {
+	case StanceFireAtWill:
+		return "Fire at will"
+	case StanceHoldFire:
+		return "Hold fire"
+	default:
+		return "Unknown"
+	}
+}
+
+// Next returns the following stance, wrapping around
+func (s DefenseStance) Next() DefenseStance {
+	return (s + 1) % (StanceHoldFire + 1)
+}
+
+// threatMemory is how long a base counts as under attack after it was last
+// damaged
+const threatMemory = 3.0
+
+// HasTurret returns true if the base has a defensive turret
+func (b *Base) HasTurret() bool {
+	return b.TurretRange > 0 && b.TurretDamage > 0
+}
+
+// IsThreatened returns true if the base was damaged recently
+func (b *Base) IsThreatened() bool {
+	return b.threatTimer > 0
+}
+
+// WillEngage returns true if the turret's stance lets it fire now. A base
+// holding fire still shoots back while under attack.
+func (b *Base) WillEngage() bool {
+	return b.Stance == StanceFireAtWill || b.IsThreatened()
+}
+
+// CycleStance switches the base's turret to the next stance
+func (b *Base) CycleStance() {
+	b.Stance = b.Stance.Next()
+}
+
+// UpdateDefenses aims and fires each owned base's turret at the nearest
+// enemy unit in range, as its stance allows
+func (m *Manager) UpdateDefenses(dt float32, units []*unit.Unit) {
+	if dt <= 0 {
+		return // Turrets hold fire while paused
+	}
+	for _, base := range m.Bases {
+		if base.threatTimer > 0 {
+			base.threatTimer -= dt
+		}
+		if base.TurretCooldown > 0 {
+			base.TurretCooldown -= dt
+		}
+
+		team, ok := base.Owner.Team()
+		if !ok || !base.HasTurret() || base.IsDestroyed() || base.IsUnderConstruction() || !base.WillEngage() {
+			base.TurretTarget = nil
+			continue
+		}
+
+		base.TurretTarget = m.nearestEnemyInRange(base, team, units)
+		if base.TurretTarget == nil || base.TurretCooldown > 0 {
+			continue
+		}
+		base.TurretTarget.TakeDamage(base.turretDamage(m.Config))
+		if m.Config.TurretRate > 0 {
+			base.TurretCooldown = 1.0 / m.Config.TurretRate
+		}
+	}
+}
+
+// nearestEnemyInRange returns the closest unit hostile to a team within the
+// base's turret range
+func (m *Manager) nearestEnemyInRange(base *Base, team unit.Team, units []*unit.Unit) *unit.Unit {
+	var nearest *unit.Unit
+	nearestDist := base.TurretRange
+	for _, u := range units {
+		if !u.IsTargetable() || u.IsCarried() || m.Alliances.AreAllied(team, u.Team) {
+			continue
+		}
+		if dist := u.DistanceToPoint(base.Position); dist <= nearestDist {
+			nearest = u
+			nearestDist = dist
+		}
+	}
+	return nearest
+}
+
+// turretMuzzle returns where a base's turret fires from
+func (b *Base) turretMuzzle() rl.Vector3 {
+	height := float32(1.5)
+	if b.Type == TypeHQ {
+		height = 2.5
+	}
+	return rl.Vector3{X: b.Position.X, Y: b.Position.Y + height, Z: b.Position.Z}
+}
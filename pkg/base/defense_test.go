@@ -0,0 +1,55 @@
+package base
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestHoldFireTurretOnlyShootsBack(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	hq := m.AddBase(TypeHQ, rl.NewVector3(0, 0, 0), OwnerPlayer1)
+	if !hq.HasTurret() {
+		t.Fatal("HQ has no turret")
+	}
+	hq.Stance = StanceHoldFire
+
+	enemy := unit.New(1, unit.TypeTank, unit.TeamEnemy, rl.NewVector3(hq.TurretRange/2, 0, 0))
+	enemy.SpawnTimer = 0
+	enemy.State = unit.StateMoving
+	units := []*unit.Unit{enemy}
+	run := func(seconds float32) {
+		for i := 0; i < int(seconds*60); i++ {
+			m.UpdateDefenses(1.0/60, units)
+		}
+	}
+
+	run(2)
+	if enemy.Health != enemy.MaxHealth {
+		t.Fatalf("hold-fire turret shot a passing enemy for %v", enemy.MaxHealth-enemy.Health)
+	}
+
+	// Once the base is hit it fires back
+	hq.TakeDamage(5)
+	run(1)
+	if enemy.Health == enemy.MaxHealth {
+		t.Fatal("hold-fire turret didn't shoot back when the base was attacked")
+	}
+
+	// And holds again once the attack is over
+	run(threatMemory)
+	health := enemy.Health
+	run(2)
+	if enemy.Health != health {
+		t.Errorf("hold-fire turret kept shooting long after the attack")
+	}
+
+	// Fire at will engages without being attacked
+	hq.Stance = StanceFireAtWill
+	run(1)
+	if enemy.Health == health {
+		t.Error("fire-at-will turret ignored an enemy in range")
+	}
+}
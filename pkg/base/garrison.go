@@ -0,0 +1,95 @@
+package base
+
+import (
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// CanStation returns true if a unit could shelter inside the base: it's
+// infantry of the owning team and there's room
+func (b *Base) CanStation(u *unit.Unit, cfg Config) bool {
+	team, ok := b.Owner.Team()
+	if !ok || u.Team != team || !u.CanFollowOrder(unit.OrderGarrison) {
+		return false
+	}
+	return !b.IsDestroyed() && len(b.Stationed) < cfg.MaxStationed
+}
+
+// Station shelters a unit inside the base, returns false if it can't.
+// The unit must also be detached from the unit manager.
+func (b *Base) Station(u *unit.Unit, cfg Config) bool {
+	if !b.CanStation(u, cfg) {
+		return false
+	}
+	b.Stationed = append(b.Stationed, u)
+	return true
+}
+
+// Unstation takes the most recently stationed unit back out of the base,
+// nil if it's empty
+func (b *Base) Unstation() *unit.Unit {
+	if len(b.Stationed) == 0 {
+		return nil
+	}
+	u := b.Stationed[len(b.Stationed)-1]
+	b.Stationed = b.Stationed[:len(b.Stationed)-1]
+	return u
+}
+
+// turretDamage returns the damage of one turret shot, boosted by the
+// infantry inside
+func (b *Base) turretDamage(cfg Config) float32 {
+	return b.TurretDamage * (1 + cfg.StationedTurretBonus*float32(len(b.Stationed)))
+}
+
+// UpdateStationing moves units under OrderGarrison into the base they were
+// sent to once they reach it. A unit turned away from a full or lost base
+// holds position where it stands instead. Destroyed bases lose everyone
+// inside.
+func (m *Manager) UpdateStationing(units *unit.Manager) {
+	for _, base := range m.Bases {
+		if base.IsDestroyed() {
+			base.Stationed = nil
+		}
+	}
+
+	// Detach compacts the unit list, so gather the units headed in first
+	garrisoning := make([]*unit.Unit, 0)
+	for _, u := range units.GetUnits() {
+		if u.Order == unit.OrderGarrison && !u.IsDead() && !u.IsCarried() && !u.IsSpawning() {
+			garrisoning = append(garrisoning, u)
+		}
+	}
+
+	for _, u := range garrisoning {
+		base := m.GetBaseAt(u.OrderTarget, m.Config.CaptureRadius)
+		if base == nil || !base.CanStation(u, m.Config) {
+			u.SetOrder(unit.OrderDefendPosition, u.Position)
+			continue
+		}
+		if u.DistanceToPoint(base.Position) > m.Config.CaptureRadius {
+			continue
+		}
+		if units.Detach(u.ID) != nil {
+			base.Station(u, m.Config)
+		}
+	}
+}
+
+// ExtractStationed brings one unit out of an owner's base at its next spawn
+// point. Returns nil if the base isn't the owner's, is empty, or the unit
+// cap is reached.
+func (m *Manager) ExtractStationed(baseID int, owner Owner, units *unit.Manager) *unit.Unit {
+	base := m.GetBase(baseID)
+	if base == nil || base.Owner != owner {
+		return nil
+	}
+	u := base.Unstation()
+	if u == nil {
+		return nil
+	}
+	if !units.Attach(u, base.NextSpawnPoint()) {
+		base.Stationed = append(base.Stationed, u)
+		return nil
+	}
+	return u
+}
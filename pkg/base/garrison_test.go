@@ -0,0 +1,84 @@
+package base
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// sendToGarrison spawns ready infantry next to a base, ordered inside
+func sendToGarrison(um *unit.Manager, b *Base) *unit.Unit {
+	u := um.Spawn(unit.TypeInfantry, unit.TeamPlayer, rl.NewVector3(b.Position.X+0.5, 0, b.Position.Z))
+	u.SpawnTimer = 0
+	u.State = unit.StateIdle
+	u.SetOrder(unit.OrderGarrison, b.Position)
+	return u
+}
+
+// inManager returns true if the unit manager holds a unit
+func inManager(um *unit.Manager, u *unit.Unit) bool {
+	for _, other := range um.GetUnits() {
+		if other == u {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGarrisonAndExtractInfantry(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	um := unit.NewManager(20)
+	b := m.AddBase(TypeOutpost, rl.NewVector3(10, 0, 10), OwnerPlayer1)
+
+	u := sendToGarrison(um, b)
+	m.UpdateStationing(um)
+	if len(b.Stationed) != 1 || b.Stationed[0] != u {
+		t.Fatalf("%d stationed, want the infantry", len(b.Stationed))
+	}
+	if inManager(um, u) {
+		t.Error("stationed infantry is still an active unit")
+	}
+
+	out := m.ExtractStationed(b.ID, OwnerPlayer1, um)
+	if out != u || len(b.Stationed) != 0 {
+		t.Fatalf("extracted %v, %d still inside", out, len(b.Stationed))
+	}
+	if !inManager(um, u) || u.Order != unit.OrderNone {
+		t.Errorf("extracted infantry isn't back as an idle unit")
+	}
+	if m.ExtractStationed(b.ID, OwnerPlayer1, um) != nil {
+		t.Error("extracted from an empty base")
+	}
+}
+
+func TestFullGarrisonTurnsInfantryAway(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	um := unit.NewManager(20)
+	b := m.AddBase(TypeOutpost, rl.NewVector3(10, 0, 10), OwnerPlayer1)
+
+	for i := 0; i < m.Config.MaxStationed; i++ {
+		sendToGarrison(um, b)
+	}
+	m.UpdateStationing(um)
+	if len(b.Stationed) != m.Config.MaxStationed {
+		t.Fatalf("%d stationed, want the %d that fit", len(b.Stationed), m.Config.MaxStationed)
+	}
+
+	extra := sendToGarrison(um, b)
+	m.UpdateStationing(um)
+	if len(b.Stationed) != m.Config.MaxStationed || !inManager(um, extra) {
+		t.Errorf("full base took another unit, %d inside", len(b.Stationed))
+	}
+	if extra.Order == unit.OrderGarrison {
+		t.Error("turned-away infantry still trying to garrison")
+	}
+
+	// Everyone inside is lost with the base
+	b.TakeDamage(b.MaxHealth * 10)
+	m.UpdateStationing(um)
+	if len(b.Stationed) != 0 {
+		t.Errorf("%d still stationed in a destroyed base", len(b.Stationed))
+	}
+}
@@ -0,0 +1,44 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// Inspect returns the base's display info
+func (b *Base) Inspect() unit.InspectInfo {
+	info := unit.InspectInfo{
+		Name:      b.Type.String(),
+		Side:      b.Owner.String(),
+		Health:    b.Health,
+		MaxHealth: b.MaxHealth,
+		Stats: []unit.InspectStat{
+			{Label: "Income", Value: fmt.Sprintf("%.0f/s", b.IncomeRate)},
+		},
+	}
+	if b.HasTurret() {
+		info.Order = "Turret: " + b.Stance.String()
+	}
+	if len(b.SpawnQueue) > 0 {
+		info.Stats = append(info.Stats, unit.InspectStat{Label: "Queued", Value: fmt.Sprint(len(b.SpawnQueue))})
+	}
+	if len(b.Stationed) > 0 {
+		info.Stats = append(info.Stats, unit.InspectStat{Label: "Garrison", Value: fmt.Sprint(len(b.Stationed))})
+	}
+	if b.CaptureProgress > 0 {
+		info.Stats = append(info.Stats, unit.InspectStat{Label: "Capture", Value: fmt.Sprintf("%.0f%%", b.CaptureProgress*100)})
+	}
+	return info
+}
+
+// InspectAs returns the base's display info as an owner sees it: bases it
+// doesn't hold and can't see are redacted
+func (b *Base) InspectAs(viewer Owner, vis graphics.Visibility) unit.InspectInfo {
+	info := b.Inspect()
+	if b.Owner != viewer && !graphics.IsVisible(vis, b.Position) {
+		return info.Redact()
+	}
+	return info
+}
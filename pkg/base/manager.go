@@ -1,12 +1,33 @@
 package base
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
+// StartingCredits is how many credits each player begins a match with
+const StartingCredits = 500
+
 // PlayerState tracks economy and game state for a player
 type PlayerState struct {
-	Credits float32
+	Credits float64 // float64 so per-frame income doesn't lose precision
+
+	// Handicap
+	HealthMultiplier float32 // Scales max health of this player's bases and units (0 = 1x)
+}
+
+// HealthScale returns the player's health multiplier, defaulting to 1
+func (p *PlayerState) HealthScale() float32 {
+	if p.HealthMultiplier <= 0 {
+		return 1.0
+	}
+	return p.HealthMultiplier
 }
 
 // Manager manages all bases in the game
@@ -18,6 +39,14 @@ type Manager struct {
 	// Player economies
 	Player1 PlayerState
 	Player2 PlayerState
+
+	// Alliance table shared with the unit manager (nil = no alliances)
+	Alliances *unit.Alliances
+
+	// Bases that changed hands during the last Update, and those handed
+	// over by TransferBase since, reported after the next one
+	recentCaptures  []*Base
+	pendingCaptures []*Base
 }
 
 // NewManager creates a new base manager
@@ -26,33 +55,125 @@ func NewManager(cfg Config) *Manager {
 		Config: cfg,
 		Bases:  make([]*Base, 0, 16),
 		nextID: 1,
-		Player1: PlayerState{Credits: 500}, // Starting credits
-		Player2: PlayerState{Credits: 500},
+		Player1: PlayerState{Credits: StartingCredits},
+		Player2: PlayerState{Credits: StartingCredits},
 	}
 }
 
+// Reset removes all bases and restores starting economies for a new match
+func (m *Manager) Reset() {
+	m.Bases = m.Bases[:0]
+	m.nextID = 1
+	m.recentCaptures = m.recentCaptures[:0]
+	m.pendingCaptures = m.pendingCaptures[:0]
+	m.Player1.Credits = StartingCredits // Handicaps carry over between matches
+	m.Player2.Credits = StartingCredits
+}
+
 // AddBase creates and adds a new base
 func (m *Manager) AddBase(baseType Type, position rl.Vector3, owner Owner) *Base {
 	base := NewBase(m.nextID, baseType, position, owner, m.Config)
 	m.nextID++
+
+	// Apply the owner's health handicap
+	base.SetHealthScale(m.healthScale(owner))
+
 	m.Bases = append(m.Bases, base)
 	return base
 }
 
+// ResolveSpawnPoints moves every base's spawn points onto passable ground.
+// Call after the bases and map are created.
+func (m *Manager) ResolveSpawnPoints(tm *tilemap.TileMap) {
+	for _, base := range m.Bases {
+		base.ResolveSpawnPoints(tm)
+	}
+}
+
 // Update updates all bases and collects income
 func (m *Manager) Update(dt float32) {
+	m.recentCaptures = append(m.recentCaptures[:0], m.pendingCaptures...)
+	m.pendingCaptures = m.pendingCaptures[:0]
+	if dt <= 0 {
+		return // Paused: no income, capture, or spawning
+	}
+
 	for _, base := range m.Bases {
+		// Allies garrison each other's bases rather than capturing them
+		if base.OccupyingOwner != base.Owner && m.AreAllied(base.OccupyingOwner, base.Owner) {
+			base.OccupyingInfantry = 0
+		}
+
+		previousOwner := base.Owner
 		base.Update(dt, m.Config)
+		if base.Owner != previousOwner {
+			base.SetHealthScale(m.healthScale(base.Owner))
+			m.recentCaptures = append(m.recentCaptures, base)
+		}
 
-		// Collect income for owners
+		// Collect income for owners, including what a base earned for
+		// whoever held it before a capture this frame
+		for _, share := range base.CollectOwedIncome() {
+			if account := m.creditAccount(share.Owner); account != nil {
+				account.Credits += share.Amount
+			}
+		}
 		income := base.CollectIncome()
-		switch base.Owner {
-		case OwnerPlayer1:
-			m.Player1.Credits += income
-		case OwnerPlayer2:
-			m.Player2.Credits += income
+		if account := m.creditAccount(base.Owner); account != nil {
+			account.Credits += income
+		}
+	}
+}
+
+// TransferBase hands a base to a new owner outside of capture, for
+// scenarios and scripting, playing the usual ownership change animation.
+// It's reported by RecentCaptures after the next Update like any capture.
+func (m *Manager) TransferBase(b *Base, owner Owner) {
+	if b.Owner == owner {
+		return
+	}
+	b.startTransition(m.Config.TransitionTime)
+	b.SetOwner(owner)
+	b.SetHealthScale(m.healthScale(owner))
+	m.pendingCaptures = append(m.pendingCaptures, b)
+}
+
+// RecentCaptures returns the bases captured during the last Update,
+// including any handed over by TransferBase before it
+func (m *Manager) RecentCaptures() []*Base {
+	return m.recentCaptures
+}
+
+// AreAllied returns true if two owners are on the same side.
+// Neutral is never allied with anyone.
+func (m *Manager) AreAllied(a, b Owner) bool {
+	teamA, okA := a.Team()
+	teamB, okB := b.Team()
+	if !okA || !okB {
+		return false
+	}
+	return m.Alliances.AreAllied(teamA, teamB)
+}
+
+// healthScale returns an owner's health handicap, 1 for neutral
+func (m *Manager) healthScale(owner Owner) float32 {
+	if player := m.GetPlayer(owner); player != nil {
+		return player.HealthScale()
+	}
+	return 1
+}
+
+// creditAccount returns the player state an owner's credits live in. With
+// shared credits, allies all use the lowest-numbered ally's pool.
+func (m *Manager) creditAccount(owner Owner) *PlayerState {
+	if m.Alliances != nil && m.Alliances.SharedCredits {
+		for _, ally := range []Owner{OwnerPlayer1, OwnerPlayer2} {
+			if m.AreAllied(ally, owner) {
+				return m.GetPlayer(ally)
+			}
 		}
 	}
+	return m.GetPlayer(owner)
 }
 
 // GetBase returns a base by ID
@@ -89,6 +210,54 @@ func (m *Manager) GetBasesOwnedBy(owner Owner) []*Base {
 	return result
 }
 
+// NearestReachableOwnedBase returns the owner's spawn-capable base
+// nearest a point that the pathfinder can reach from it. Bases are tried
+// nearest first, so usually only one reachability check runs. If none are
+// reachable (or pathfinder is nil) the nearest by straight line is
+// returned instead.
+func (m *Manager) NearestReachableOwnedBase(from rl.Vector3, owner Owner, pathfinder *unit.Pathfinder) *Base {
+	var candidates []*Base
+	for _, base := range m.Bases {
+		if base.Owner == owner && base.CanSpawn() {
+			candidates = append(candidates, base)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	distance := func(b *Base) float32 {
+		dx := b.Position.X - from.X
+		dz := b.Position.Z - from.Z
+		return dx*dx + dz*dz // squared distance is fine for comparison
+	}
+	slices.SortStableFunc(candidates, func(a, b *Base) int {
+		return cmp.Compare(distance(a), distance(b))
+	})
+
+	if pathfinder != nil {
+		start := rl.Vector2{X: from.X, Y: from.Z}
+		for _, base := range candidates {
+			goal := rl.Vector2{X: base.SpawnPoint.X, Y: base.SpawnPoint.Z}
+			if pathfinder.IsReachable(start, goal, false) {
+				return base
+			}
+		}
+	}
+	return candidates[0] // Cut off from all of them by water or mountains
+}
+
+// IncomeRate returns an owner's total income in credits per second
+func (m *Manager) IncomeRate(owner Owner) float32 {
+	rate := float32(0)
+	for _, base := range m.Bases {
+		if base.Owner == owner && !base.IsDestroyed() {
+			rate += base.IncomeRate
+		}
+	}
+	return rate
+}
+
 // GetHQ returns the HQ for a specific owner (nil if destroyed or not found)
 func (m *Manager) GetHQ(owner Owner) *Base {
 	for _, base := range m.Bases {
@@ -114,59 +283,92 @@ func (m *Manager) IsGameOver() Owner {
 	return OwnerNeutral // Game continues
 }
 
-// SpendCredits attempts to spend credits for a player
-// Returns true if successful, false if insufficient funds
-func (m *Manager) SpendCredits(owner Owner, amount float32) bool {
-	var player *PlayerState
+// GetPlayer returns the state for a player (nil for neutral)
+func (m *Manager) GetPlayer(owner Owner) *PlayerState {
 	switch owner {
 	case OwnerPlayer1:
-		player = &m.Player1
+		return &m.Player1
 	case OwnerPlayer2:
-		player = &m.Player2
+		return &m.Player2
 	default:
+		return nil
+	}
+}
+
+// SpendCredits attempts to spend credits for a player
+// Returns true if successful, false if insufficient funds
+func (m *Manager) SpendCredits(owner Owner, amount float32) bool {
+	player := m.creditAccount(owner)
+	if player == nil {
 		return false
 	}
 
-	if player.Credits >= amount {
-		player.Credits -= amount
+	if player.Credits >= float64(amount) {
+		player.Credits -= float64(amount)
 		return true
 	}
 	return false
 }
 
+// AddCredits gives credits to a player (refunds, rewards)
+func (m *Manager) AddCredits(owner Owner, amount float32) {
+	if player := m.creditAccount(owner); player != nil {
+		player.Credits += float64(amount)
+	}
+}
+
 // GetCredits returns credits for a player
 func (m *Manager) GetCredits(owner Owner) float32 {
-	switch owner {
-	case OwnerPlayer1:
-		return m.Player1.Credits
-	case OwnerPlayer2:
-		return m.Player2.Credits
-	default:
+	player := m.creditAccount(owner)
+	if player == nil {
 		return 0
 	}
+	return float32(player.Credits)
 }
 
-// CreateDefaultMap creates a standard symmetric map layout
-func (m *Manager) CreateDefaultMap() {
-	// Player 1 HQ (bottom of map)
-	m.AddBase(TypeHQ, rl.NewVector3(0, 0, -15), OwnerPlayer1)
+// CreateDefaultMap creates the standard symmetric layout centered on a
+// tile map, so every base lies on the map
+func (m *Manager) CreateDefaultMap(tm *tilemap.TileMap) {
+	m.CreateFromLayout(tm, tilemap.StandardLayout(tm.Width, tm.Height))
 
-	// Player 2 HQ (top of map)
-	m.AddBase(TypeHQ, rl.NewVector3(0, 0, 15), OwnerPlayer2)
+	// Center outpost, held by a neutral garrison
+	x, z := tm.TileToWorld(tm.Width/2, tm.Height/2)
+	if center := m.GetBaseAt(rl.NewVector3(x, 0, z), tm.TileSize/2); center != nil {
+		center.Garrison = []unit.UnitType{unit.TypeInfantry, unit.TypeInfantry, unit.TypeTank}
+	}
+}
+
+// LoadFromMap adds the bases a map file places. The map must give each
+// player exactly one HQ; if not, no bases are added.
+func (m *Manager) LoadFromMap(data *tilemap.MapData) error {
+	if err := data.Layout.ValidateHQs(); err != nil {
+		return fmt.Errorf("map bases: %w", err)
+	}
+	m.CreateFromLayout(data.Map, data.Layout)
+	return nil
+}
 
-	// Neutral outposts in a symmetric pattern
-	// Center outpost
-	m.AddBase(TypeOutpost, rl.NewVector3(0, 0, 0), OwnerNeutral)
+// CreateFromLayout adds a base at each site of a map's layout, placed at
+// the center of its tile
+func (m *Manager) CreateFromLayout(tm *tilemap.TileMap, layout tilemap.Layout) {
+	for _, s := range layout.Sites {
+		baseType := TypeOutpost
+		switch s.Kind {
+		case tilemap.SiteHQ:
+			baseType = TypeHQ
+		case tilemap.SiteResource:
+			baseType = TypeResource
+		}
 
-	// Side outposts
-	m.AddBase(TypeOutpost, rl.NewVector3(-10, 0, -5), OwnerNeutral)
-	m.AddBase(TypeOutpost, rl.NewVector3(10, 0, -5), OwnerNeutral)
-	m.AddBase(TypeOutpost, rl.NewVector3(-10, 0, 5), OwnerNeutral)
-	m.AddBase(TypeOutpost, rl.NewVector3(10, 0, 5), OwnerNeutral)
+		owner := OwnerNeutral
+		switch s.Side {
+		case 1:
+			owner = OwnerPlayer1
+		case 2:
+			owner = OwnerPlayer2
+		}
 
-	// Corner outposts
-	m.AddBase(TypeOutpost, rl.NewVector3(-8, 0, -10), OwnerPlayer1) // Near P1
-	m.AddBase(TypeOutpost, rl.NewVector3(8, 0, -10), OwnerPlayer1)
-	m.AddBase(TypeOutpost, rl.NewVector3(-8, 0, 10), OwnerPlayer2) // Near P2
-	m.AddBase(TypeOutpost, rl.NewVector3(8, 0, 10), OwnerPlayer2)
+		x, z := tm.TileToWorld(s.X, s.Y)
+		m.AddBase(baseType, rl.NewVector3(x, 0, z), owner)
+	}
 }
@@ -0,0 +1,150 @@
+package base
+
+import (
+	"path/filepath"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestDefaultMapBasesOnTileMap(t *testing.T) {
+	tm := tilemap.GenerateTestMap(64, 48)
+	m := NewManager(DefaultConfig())
+	m.CreateDefaultMap(tm)
+
+	if len(m.Bases) != len(tilemap.StandardLayout(64, 48).Sites) {
+		t.Fatalf("%d bases, want one per layout site", len(m.Bases))
+	}
+	for _, b := range m.Bases {
+		if x, y := tm.WorldToTile(b.Position.X, b.Position.Z); !tm.InBounds(x, y) {
+			t.Errorf("base %d at %v is off the map", b.ID, b.Position)
+		}
+	}
+
+	// A player's bases light up the fog around them
+	fog := tilemap.NewFogOfWar(tm, tilemap.FogMemoryFull)
+	fog.BeginFrame(0)
+	for _, b := range m.GetBasesOwnedBy(OwnerPlayer1) {
+		fog.Reveal(b.Position.X, b.Position.Z, 8)
+	}
+	hq := m.GetHQ(OwnerPlayer1)
+	near := hq.Position
+	near.X += 2
+	if !fog.IsVisibleAt(near) {
+		t.Error("ground beside player 1's HQ isn't visible")
+	}
+
+	garrisoned := 0
+	for _, b := range m.Bases {
+		if len(b.Garrison) > 0 {
+			garrisoned++
+		}
+	}
+	if garrisoned != 1 {
+		t.Errorf("%d garrisoned bases, want the center outpost", garrisoned)
+	}
+}
+
+func TestNearestReachableBaseSkipsCutOffOnes(t *testing.T) {
+	tm := tilemap.NewTileMap(40, 20)
+	tm.FillRect(20, 0, 21, 19, tilemap.TerrainWater)
+	pf := unit.NewPathfinder(tm.Width, tm.Height, tm.TileSize)
+	pf.SyncFromTileMap(tm)
+
+	m := NewManager(DefaultConfig())
+	across := m.AddBase(TypeOutpost, rl.NewVector3(25.5, 0, 10.5), OwnerPlayer1)
+	sameBank := m.AddBase(TypeOutpost, rl.NewVector3(2.5, 0, 10.5), OwnerPlayer1)
+	m.AddBase(TypeResource, rl.NewVector3(16.5, 0, 10.5), OwnerPlayer1) // Can't spawn
+	m.ResolveSpawnPoints(tm)
+
+	from := rl.NewVector3(17.5, 0, 10.5)
+	if got := m.NearestReachableOwnedBase(from, OwnerPlayer1, pf); got != sameBank {
+		t.Errorf("picked base %d, want %d on the same side of the river", got.ID, sameBank.ID)
+	}
+	if got := m.NearestReachableOwnedBase(from, OwnerPlayer1, nil); got != across {
+		t.Errorf("without a pathfinder picked base %d, want the nearest, %d", got.ID, across.ID)
+	}
+	if got := m.NearestReachableOwnedBase(from, OwnerPlayer2, pf); got != nil {
+		t.Errorf("player 2 has no bases but got %d", got.ID)
+	}
+}
+
+func TestLoadFromMap(t *testing.T) {
+	tm := tilemap.NewTileMap(20, 20)
+	data := &tilemap.MapData{Map: tm, Layout: tilemap.Layout{Sites: []tilemap.Site{
+		{Kind: tilemap.SiteHQ, Side: 1, X: 10, Y: 2},
+		{Kind: tilemap.SiteHQ, Side: 2, X: 10, Y: 17},
+		{Kind: tilemap.SiteOutpost, Side: 0, X: 10, Y: 10},
+		{Kind: tilemap.SiteOutpost, Side: 1, X: 4, Y: 5},
+		{Kind: tilemap.SiteOutpost, Side: 2, X: 16, Y: 14},
+	}}}
+
+	// Through the map format and back, as a custom map would arrive
+	path := filepath.Join(t.TempDir(), "small.map")
+	if err := tilemap.SaveMap(data, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := tilemap.LoadMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(DefaultConfig())
+	if err := m.LoadFromMap(loaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Bases) != 5 {
+		t.Fatalf("%d bases, want 5", len(m.Bases))
+	}
+
+	counts := map[Owner]map[Type]int{}
+	for _, b := range m.Bases {
+		if counts[b.Owner] == nil {
+			counts[b.Owner] = map[Type]int{}
+		}
+		counts[b.Owner][b.Type]++
+	}
+	for _, owner := range []Owner{OwnerPlayer1, OwnerPlayer2} {
+		if counts[owner][TypeHQ] != 1 || counts[owner][TypeOutpost] != 1 {
+			t.Errorf("owner %d has %d HQs and %d outposts, want 1 of each", owner, counts[owner][TypeHQ], counts[owner][TypeOutpost])
+		}
+	}
+	if counts[OwnerNeutral][TypeOutpost] != 1 {
+		t.Errorf("%d neutral outposts, want 1", counts[OwnerNeutral][TypeOutpost])
+	}
+
+	x, z := tm.TileToWorld(10, 17)
+	if hq := m.GetHQ(OwnerPlayer2); hq == nil || hq.Position != rl.NewVector3(x, 0, z) {
+		t.Errorf("player 2's HQ is %v, want at (%v, %v)", hq, x, z)
+	}
+}
+
+func TestLoadFromMapRejectsBadHQs(t *testing.T) {
+	tm := tilemap.NewTileMap(20, 20)
+	layouts := map[string][]tilemap.Site{
+		"no HQs": {
+			{Kind: tilemap.SiteOutpost, Side: 1, X: 4, Y: 5},
+		},
+		"player 2 without an HQ": {
+			{Kind: tilemap.SiteHQ, Side: 1, X: 10, Y: 2},
+		},
+		"two HQs for player 1": {
+			{Kind: tilemap.SiteHQ, Side: 1, X: 10, Y: 2},
+			{Kind: tilemap.SiteHQ, Side: 1, X: 4, Y: 2},
+			{Kind: tilemap.SiteHQ, Side: 2, X: 10, Y: 17},
+		},
+	}
+	for name, sites := range layouts {
+		m := NewManager(DefaultConfig())
+		err := m.LoadFromMap(&tilemap.MapData{Map: tm, Layout: tilemap.Layout{Sites: sites}})
+		if err == nil {
+			t.Errorf("%s: loaded, want an error", name)
+		}
+		if len(m.Bases) != 0 {
+			t.Errorf("%s: %d bases added from a rejected map", name, len(m.Bases))
+		}
+	}
+}
@@ -1,17 +1,48 @@
 package base
 
 import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
 	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
+// PurchaseOption ties a purchasable unit type to its hotkey
+type PurchaseOption struct {
+	UnitType unit.UnitType
+	Key      int32  // raylib key code
+	KeyLabel string // Label shown in the purchase panel
+}
+
+// PurchaseOptions is the single list of units that can be bought, in panel
+// order. Costs and names come from the unit package.
+var PurchaseOptions = []PurchaseOption{
+	{UnitType: unit.TypeInfantry, Key: rl.KeyOne, KeyLabel: "1"},
+	{UnitType: unit.TypeTank, Key: rl.KeyTwo, KeyLabel: "2"},
+	{UnitType: unit.TypeMotorcycle, Key: rl.KeyThree, KeyLabel: "3"},
+	{UnitType: unit.TypeSAM, Key: rl.KeyFour, KeyLabel: "4"},
+	{UnitType: unit.TypeBoat, Key: rl.KeyFive, KeyLabel: "5"},
+	{UnitType: unit.TypeSupply, Key: rl.KeySix, KeyLabel: "6"},
+}
+
 // AllUnitTypes lists all purchasable unit types
-var AllUnitTypes = []unit.UnitType{
-	unit.TypeInfantry,
-	unit.TypeTank,
-	unit.TypeMotorcycle,
-	unit.TypeSAM,
-	unit.TypeBoat,
-	unit.TypeSupply,
+var AllUnitTypes = purchasableTypes()
+
+func purchasableTypes() []unit.UnitType {
+	types := make([]unit.UnitType, len(PurchaseOptions))
+	for i, opt := range PurchaseOptions {
+		types[i] = opt.UnitType
+	}
+	return types
+}
+
+// IsPurchasable returns true if the unit type can be bought at a base
+func IsPurchasable(unitType unit.UnitType) bool {
+	for _, opt := range PurchaseOptions {
+		if opt.UnitType == unitType {
+			return true
+		}
+	}
+	return false
 }
 
 // UnitCost returns the credit cost for a unit type
@@ -44,6 +75,21 @@ func (m *Manager) TryPurchaseUnit(baseID int, unitType unit.UnitType, owner Owne
 		return false
 	}
 
+	// Resource points have no spawn queue, and rubble builds nothing
+	if !base.CanSpawn() || base.IsDestroyed() {
+		return false
+	}
+
+	// Only units in the purchase list can be bought
+	if !IsPurchasable(unitType) {
+		return false
+	}
+
+	// Tech gating
+	if m.CheckRequirements(owner, unitType) != nil {
+		return false
+	}
+
 	// Check cost
 	cost := UnitCost(unitType)
 	if !m.SpendCredits(owner, cost) {
@@ -55,13 +101,41 @@ func (m *Manager) TryPurchaseUnit(baseID int, unitType unit.UnitType, owner Owne
 	return true
 }
 
-// GetPurchasableUnits returns units that can be purchased with current credits
+// TryPurchaseUnits queues up to count units, stopping at the first one the
+// owner can't afford. Each unit is paid for in full or not at all.
+// Returns how many were queued.
+func (m *Manager) TryPurchaseUnits(baseID int, unitType unit.UnitType, owner Owner, count int) int {
+	queued := 0
+	for queued < count && m.TryPurchaseUnit(baseID, unitType, owner) {
+		queued++
+	}
+	return queued
+}
+
+// ClearQueue cancels everything waiting at one of the owner's bases and
+// refunds the full cost. Returns the credits refunded.
+func (m *Manager) ClearQueue(baseID int, owner Owner) float32 {
+	base := m.GetBase(baseID)
+	if base == nil || base.Owner != owner {
+		return 0
+	}
+
+	refund := float32(0)
+	for _, ut := range base.ClearQueue() {
+		refund += UnitCost(ut)
+	}
+	m.AddCredits(owner, refund)
+	return refund
+}
+
+// GetPurchasableUnits returns units that can be purchased with current
+// credits and whose prerequisites the owner holds
 func (m *Manager) GetPurchasableUnits(owner Owner) []unit.UnitType {
 	credits := m.GetCredits(owner)
 	available := make([]unit.UnitType, 0, len(AllUnitTypes))
 
 	for _, ut := range AllUnitTypes {
-		if UnitCost(ut) <= credits {
+		if UnitCost(ut) <= credits && m.CheckRequirements(owner, ut) == nil {
 			available = append(available, ut)
 		}
 	}
@@ -0,0 +1,82 @@
+package base
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestPurchaseEachUnitType(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Requirements = nil // Every type on sale from the start
+	m := NewManager(cfg)
+	hq := m.AddBase(TypeHQ, rl.Vector3{}, OwnerPlayer1)
+	m.AddCredits(OwnerPlayer1, 10000)
+
+	types := []unit.UnitType{
+		unit.TypeInfantry,
+		unit.TypeTank,
+		unit.TypeMotorcycle,
+		unit.TypeSAM,
+		unit.TypeBoat,
+		unit.TypeSupply,
+	}
+	for _, ut := range types {
+		before := m.GetCredits(OwnerPlayer1)
+		if !m.TryPurchaseUnit(hq.ID, ut, OwnerPlayer1) {
+			t.Errorf("couldn't buy %v", ut)
+			continue
+		}
+		if queued := hq.SpawnQueue[len(hq.SpawnQueue)-1]; queued != ut {
+			t.Errorf("bought %v, queued %v", ut, queued)
+		}
+		if spent, cost := before-m.GetCredits(OwnerPlayer1), float32(unit.GetConfig(ut).Cost); spent != cost {
+			t.Errorf("%v charged %v, costs %v", ut, spent, cost)
+		}
+	}
+	if len(hq.SpawnQueue) != len(types) {
+		t.Errorf("%d units queued, want one of each of %d types", len(hq.SpawnQueue), len(types))
+	}
+}
+
+func TestBulkPurchaseStopsWhenBroke(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Requirements = nil
+	m := NewManager(cfg)
+	hq := m.AddBase(TypeHQ, rl.Vector3{}, OwnerPlayer1)
+	cost := UnitCost(unit.TypeInfantry)
+	m.GetPlayer(OwnerPlayer1).Credits = float64(cost * 2.5)
+
+	if queued := m.TryPurchaseUnits(hq.ID, unit.TypeInfantry, OwnerPlayer1, 5); queued != 2 {
+		t.Errorf("queued %d of 5 with credits for 2.5", queued)
+	}
+	if len(hq.SpawnQueue) != 2 {
+		t.Errorf("%d units in the queue, want 2", len(hq.SpawnQueue))
+	}
+	if left := m.GetCredits(OwnerPlayer1); left != cost/2 {
+		t.Errorf("%v credits left, want %v with nothing partly charged", left, cost/2)
+	}
+
+	// Clearing the queue gives back everything it cost
+	m.AddCredits(OwnerPlayer1, 1000)
+	before := m.GetCredits(OwnerPlayer1)
+	m.TryPurchaseUnits(hq.ID, unit.TypeTank, OwnerPlayer1, 1)
+	want := 2*cost + UnitCost(unit.TypeTank)
+	if got := m.ClearQueue(hq.ID, OwnerPlayer1); got != want {
+		t.Errorf("refunded %v, want %v", got, want)
+	}
+	if len(hq.SpawnQueue) != 0 {
+		t.Errorf("%d units still queued", len(hq.SpawnQueue))
+	}
+	if got := m.GetCredits(OwnerPlayer1); got != before+2*cost {
+		t.Errorf("%v credits after the refund, want %v", got, before+2*cost)
+	}
+
+	// Only the owner can clear a base's queue
+	m.TryPurchaseUnits(hq.ID, unit.TypeInfantry, OwnerPlayer1, 1)
+	if got := m.ClearQueue(hq.ID, OwnerPlayer2); got != 0 || len(hq.SpawnQueue) != 1 {
+		t.Errorf("player 2 cleared player 1's queue for %v", got)
+	}
+}
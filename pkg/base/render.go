@@ -17,19 +17,44 @@ func NewRenderer() *Renderer {
 // Draw renders all bases
 func (r *Renderer) Draw(mgr *Manager) {
 	for _, base := range mgr.Bases {
-		if base.IsDestroyed() {
+		state := base.VisualState()
+		switch {
+		case state == VisualDestroyed:
 			r.drawDestroyed(base)
-		} else if base.Type == TypeHQ {
+		case state == VisualUnderConstruction:
+			r.drawConstruction(base)
+		case base.Type == TypeHQ:
 			r.drawHQ(base)
-		} else {
+		case base.Type == TypeResource:
+			r.drawResource(base)
+		default:
 			r.drawOutpost(base)
 		}
+		if state == VisualUpgrading {
+			r.drawUpgrade(base)
+		}
+
+		if base.TurretTarget != nil {
+			r.drawTurretFire(base)
+		}
+
+		if state != VisualDestroyed && state != VisualUnderConstruction && base.CanSpawn() {
+			r.drawSpawnQueue(base)
+			r.drawRallyPoint(base)
+		}
 	}
 }
 
+// drawTurretFire draws the turret's line of fire
+func (r *Renderer) drawTurretFire(b *Base) {
+	target := b.TurretTarget.Position
+	target.Y += 0.3
+	rl.DrawLine3D(b.turretMuzzle(), target, rl.Orange)
+}
+
 func (r *Renderer) drawHQ(b *Base) {
 	pos := b.Position
-	ownerColor := b.GetOwnerColor()
+	ownerColor := b.DisplayColor()
 
 	// HQ is a larger, more elaborate structure
 	// Main building
@@ -45,9 +70,9 @@ func (r *Renderer) drawHQ(b *Base) {
 	antennaPos := rl.Vector3{X: pos.X, Y: pos.Y + 4.5, Z: pos.Z}
 	rl.DrawCylinder(antennaPos, 0.1, 0.1, 2.0, 8, rl.DarkGray)
 
-	// Flag at top (colored by owner)
-	flagPos := rl.Vector3{X: pos.X + 0.3, Y: pos.Y + 5.0, Z: pos.Z}
-	rl.DrawCube(flagPos, 0.6, 0.4, 0.05, ownerColor)
+	// Flag at top (colored by owner), lowered and raised on capture
+	flagPos := rl.Vector3{X: pos.X + 0.3, Y: pos.Y + 3.8 + 1.2*b.FlagHeight(), Z: pos.Z}
+	rl.DrawCube(flagPos, 0.6, 0.4, 0.05, b.FlagColor())
 
 	// Door
 	doorPos := rl.Vector3{X: pos.X, Y: pos.Y - 0.5, Z: pos.Z + 2.01}
@@ -69,7 +94,7 @@ func (r *Renderer) drawHQ(b *Base) {
 
 func (r *Renderer) drawOutpost(b *Base) {
 	pos := b.Position
-	ownerColor := b.GetOwnerColor()
+	ownerColor := b.DisplayColor()
 
 	// Outpost is a smaller structure
 	// Main building
@@ -85,9 +110,9 @@ func (r *Renderer) drawOutpost(b *Base) {
 	polePos := rl.Vector3{X: pos.X, Y: pos.Y + 2.0, Z: pos.Z}
 	rl.DrawCylinder(polePos, 0.05, 0.05, 1.0, 8, rl.DarkGray)
 
-	// Small flag
-	flagPos := rl.Vector3{X: pos.X + 0.2, Y: pos.Y + 2.3, Z: pos.Z}
-	rl.DrawCube(flagPos, 0.4, 0.25, 0.03, ownerColor)
+	// Small flag, lowered and raised on capture
+	flagPos := rl.Vector3{X: pos.X + 0.2, Y: pos.Y + 1.7 + 0.6*b.FlagHeight(), Z: pos.Z}
+	rl.DrawCube(flagPos, 0.4, 0.25, 0.03, b.FlagColor())
 
 	// Health bar
 	r.drawHealthBar(b, 2.5)
@@ -101,6 +126,33 @@ func (r *Renderer) drawOutpost(b *Base) {
 	r.drawSpawnPoint(b)
 }
 
+func (r *Renderer) drawResource(b *Base) {
+	pos := b.Position
+	ownerColor := b.DisplayColor()
+
+	// Resource point is a low pad with a derrick on top
+	padPos := rl.Vector3{X: pos.X, Y: pos.Y + 0.2, Z: pos.Z}
+	rl.DrawCube(padPos, 2.5, 0.4, 2.5, ownerColor)
+	rl.DrawCubeWires(padPos, 2.5, 0.4, 2.5, rl.Black)
+
+	// Derrick tower
+	towerPos := rl.Vector3{X: pos.X, Y: pos.Y + 0.4, Z: pos.Z}
+	rl.DrawCylinderWires(towerPos, 0.7, 0.15, 2.5, 4, rl.DarkGray)
+
+	// Gold stockpile marks it as an income objective
+	stockPos := rl.Vector3{X: pos.X + 0.8, Y: pos.Y + 0.6, Z: pos.Z + 0.8}
+	rl.DrawCube(stockPos, 0.5, 0.4, 0.5, rl.Gold)
+	rl.DrawCubeWires(stockPos, 0.5, 0.4, 0.5, rl.Black)
+
+	// Health bar
+	r.drawHealthBar(b, 3.2)
+
+	// Capture progress bar (if being captured)
+	if b.CaptureProgress > 0 {
+		r.drawCaptureBar(b)
+	}
+}
+
 func (r *Renderer) drawDestroyed(b *Base) {
 	pos := b.Position
 
@@ -124,6 +176,79 @@ func (r *Renderer) drawDestroyed(b *Base) {
 	}
 }
 
+// footprint returns the size of a base's main structure
+func footprint(b *Base) (width, height, depth float32) {
+	switch b.Type {
+	case TypeHQ:
+		return 4.0, 3.0, 4.0
+	case TypeResource:
+		return 2.5, 0.4, 2.5
+	default:
+		return 2.0, 1.5, 2.0
+	}
+}
+
+// drawConstruction draws a partly built structure inside scaffolding
+func (r *Renderer) drawConstruction(b *Base) {
+	pos := b.Position
+	w, h, d := footprint(b)
+
+	// The structure rises from the ground as it's built
+	built := h * b.BuildProgress
+	if built > 0 {
+		corePos := rl.Vector3{X: pos.X, Y: pos.Y - h/2 + built/2, Z: pos.Z}
+		rl.DrawCube(corePos, w, built, d, b.DisplayColor())
+		rl.DrawCubeWires(corePos, w, built, d, rl.Black)
+	}
+
+	// Scaffolding frame around the full finished size
+	framePos := rl.Vector3{X: pos.X, Y: pos.Y + 0.1, Z: pos.Z}
+	rl.DrawCubeWires(framePos, w+0.4, h+0.2, d+0.4, rl.Brown)
+	for _, corner := range [][2]float32{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+		polePos := rl.Vector3{X: pos.X + corner[0]*(w/2+0.2), Y: pos.Y - h/2, Z: pos.Z + corner[1]*(d/2+0.2)}
+		rl.DrawCylinder(polePos, 0.05, 0.05, h+0.2, 6, rl.Brown)
+	}
+
+	r.drawProgressBar(b, h/2+1.0, b.BuildProgress, rl.Orange)
+}
+
+// drawUpgrade draws a crane beside a base being upgraded
+func (r *Renderer) drawUpgrade(b *Base) {
+	pos := b.Position
+	w, h, _ := footprint(b)
+
+	// Mast beside the building, jib reaching over it
+	mastHeight := h + 2.0
+	mastBase := rl.Vector3{X: pos.X + w/2 + 0.6, Y: pos.Y - h/2, Z: pos.Z}
+	rl.DrawCylinder(mastBase, 0.12, 0.12, mastHeight, 6, rl.Yellow)
+
+	top := rl.Vector3{X: mastBase.X, Y: mastBase.Y + mastHeight, Z: pos.Z}
+	jibEnd := rl.Vector3{X: pos.X, Y: top.Y, Z: pos.Z}
+	rl.DrawLine3D(top, jibEnd, rl.Yellow)
+
+	// The load rises with progress
+	hookY := pos.Y + h/2 + 0.3 + (mastHeight-h-0.6)*b.UpgradeProgress
+	hook := rl.Vector3{X: pos.X, Y: hookY, Z: pos.Z}
+	rl.DrawLine3D(jibEnd, hook, rl.DarkGray)
+	rl.DrawCube(hook, 0.3, 0.3, 0.3, rl.Orange)
+
+	r.drawProgressBar(b, mastHeight-h/2+0.5, b.UpgradeProgress, rl.Yellow)
+}
+
+// drawProgressBar draws a construction or upgrade progress bar
+func (r *Renderer) drawProgressBar(b *Base, yOffset, progress float32, color rl.Color) {
+	pos := b.Position
+	barWidth := float32(2.0)
+	barHeight := float32(0.1)
+
+	barPos := rl.Vector3{X: pos.X, Y: pos.Y + yOffset, Z: pos.Z}
+	rl.DrawCube(barPos, barWidth, barHeight, 0.1, rl.DarkGray)
+
+	fillWidth := barWidth * progress
+	fillPos := rl.Vector3{X: pos.X - (barWidth-fillWidth)/2, Y: pos.Y + yOffset, Z: pos.Z + 0.05}
+	rl.DrawCube(fillPos, fillWidth, barHeight, 0.05, color)
+}
+
 func (r *Renderer) drawHealthBar(b *Base, yOffset float32) {
 	pos := b.Position
 	barWidth := float32(2.0)
@@ -200,12 +325,49 @@ func (r *Renderer) drawSpawnPoint(b *Base) {
 		return // Neutral bases don't show spawn points
 	}
 
-	sp := b.SpawnPoint
 	ownerColor := b.GetOwnerColor()
 
-	// Draw a small marker at spawn point
-	rl.DrawCylinder(sp, 0.3, 0.3, 0.05, 16, lightenColor(ownerColor))
-	rl.DrawCylinderWires(sp, 0.3, 0.3, 0.05, 16, ownerColor)
+	// Draw a small marker at each spawn point
+	for _, sp := range b.SpawnPoints {
+		rl.DrawCylinder(sp, 0.3, 0.3, 0.05, 16, lightenColor(ownerColor))
+		rl.DrawCylinderWires(sp, 0.3, 0.3, 0.05, 16, ownerColor)
+	}
+}
+
+func (r *Renderer) drawSpawnQueue(b *Base) {
+	if len(b.SpawnQueue) == 0 {
+		return
+	}
+
+	// One pip per queued unit, lined up beside the spawn point
+	maxPips := 8
+	ownerColor := b.GetOwnerColor()
+	for i := 0; i < len(b.SpawnQueue) && i < maxPips; i++ {
+		pipPos := rl.Vector3{
+			X: b.SpawnPoint.X + 0.6 + float32(i)*0.25,
+			Y: 0.15,
+			Z: b.SpawnPoint.Z,
+		}
+		rl.DrawCube(pipPos, 0.15, 0.15, 0.15, lightenColor(ownerColor))
+		rl.DrawCubeWires(pipPos, 0.15, 0.15, 0.15, ownerColor)
+	}
+}
+
+func (r *Renderer) drawRallyPoint(b *Base) {
+	if !b.HasRally || b.Owner == OwnerNeutral {
+		return
+	}
+
+	ownerColor := b.GetOwnerColor()
+	start := rl.Vector3{X: b.SpawnPoint.X, Y: 0.1, Z: b.SpawnPoint.Z}
+	end := rl.Vector3{X: b.RallyPoint.X, Y: 0.1, Z: b.RallyPoint.Z}
+	rl.DrawLine3D(start, end, lightenColor(ownerColor))
+
+	// Small flag at the rally location
+	poleTop := rl.Vector3{X: end.X, Y: 1.0, Z: end.Z}
+	rl.DrawLine3D(end, poleTop, rl.DarkGray)
+	flagPos := rl.Vector3{X: end.X + 0.15, Y: 0.9, Z: end.Z}
+	rl.DrawCube(flagPos, 0.3, 0.2, 0.02, ownerColor)
 }
 
 // DrawUI renders base-related UI elements
@@ -220,19 +382,6 @@ func (r *Renderer) DrawUI(mgr *Manager, screenWidth, screenHeight int) {
 
 	baseText := fmt.Sprintf("Bases: You:%d  Neutral:%d  Enemy:%d", p1Bases, neutralBases, p2Bases)
 	rl.DrawText(baseText, 10, 55, 14, rl.White)
-
-	// Game over check
-	loser := mgr.IsGameOver()
-	if loser != OwnerNeutral {
-		var winText string
-		if loser == OwnerPlayer1 {
-			winText = "PLAYER 2 WINS!"
-		} else {
-			winText = "PLAYER 1 WINS!"
-		}
-		textWidth := rl.MeasureText(winText, 40)
-		rl.DrawText(winText, int32(screenWidth/2)-textWidth/2, int32(screenHeight/2)-20, 40, rl.Gold)
-	}
 }
 
 // drawPurchasePanel renders the unit purchase UI
@@ -247,7 +396,7 @@ func (r *Renderer) drawPurchasePanel(mgr *Manager, screenHeight int) {
 	rl.DrawText(creditsText, panelX, 35, 18, rl.Yellow)
 
 	// Panel background
-	panelHeight := lineHeight*int32(len(AllUnitTypes)) + 30
+	panelHeight := lineHeight*int32(len(PurchaseOptions)) + 30
 	rl.DrawRectangle(panelX-5, panelY-5, panelWidth, panelHeight, rl.Color{R: 0, G: 0, B: 0, A: 150})
 
 	// Title
@@ -255,23 +404,26 @@ func (r *Renderer) drawPurchasePanel(mgr *Manager, screenHeight int) {
 	panelY += 25
 
 	// Unit list with costs
-	keys := []string{"1", "2", "3", "4", "5", "6"}
-	credits := mgr.Player1.Credits
+	credits := mgr.GetCredits(OwnerPlayer1)
+
+	for _, opt := range PurchaseOptions {
+		cost := UnitCost(opt.UnitType)
+		name := UnitName(opt.UnitType)
 
-	for i, ut := range AllUnitTypes {
-		cost := UnitCost(ut)
-		name := UnitName(ut)
+		// Format: [1] Infantry - $100
+		unitText := fmt.Sprintf("[%s] %s - $%.0f", opt.KeyLabel, name, cost)
 
 		// Check if affordable
 		var textColor rl.Color
-		if cost <= credits {
+		if req, locked := mgr.Config.Requirements[opt.UnitType]; locked && !mgr.HasRequirement(OwnerPlayer1, req) {
+			textColor = rl.Color{R: 200, G: 80, B: 80, A: 255} // Red for locked
+			unitText = fmt.Sprintf("[%s] %s - needs %s", opt.KeyLabel, name, req.BaseType)
+		} else if cost <= credits {
 			textColor = rl.Green
 		} else {
 			textColor = rl.Color{R: 128, G: 128, B: 128, A: 255} // Gray for unaffordable
 		}
 
-		// Format: [1] Infantry - $100
-		unitText := fmt.Sprintf("[%s] %s - $%.0f", keys[i], name, cost)
 		rl.DrawText(unitText, panelX, panelY, 14, textColor)
 		panelY += lineHeight
 	}
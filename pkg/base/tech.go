@@ -0,0 +1,66 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// Requirement is what an owner must hold before a unit type can be bought
+type Requirement struct {
+	BaseType Type // An intact base of this type must be owned
+}
+
+// RequirementError is returned when a purchase is refused because the
+// owner lacks a unit's prerequisite
+type RequirementError struct {
+	UnitType unit.UnitType
+	Needs    Requirement
+}
+
+// Error describes what is missing, e.g. "Tank requires an Outpost"
+func (e *RequirementError) Error() string {
+	return fmt.Sprintf("%s requires %s", UnitName(e.UnitType), e.Needs)
+}
+
+// String returns the requirement as shown to the player
+func (r Requirement) String() string {
+	name := r.BaseType.String()
+	switch name[0] {
+	case 'A', 'E', 'H', 'I', 'O', 'U':
+		return "an " + name
+	default:
+		return "a " + name
+	}
+}
+
+// DefaultRequirements gates heavier units behind captured bases. Anything
+// not listed can always be bought.
+func DefaultRequirements() map[unit.UnitType]Requirement {
+	return map[unit.UnitType]Requirement{
+		unit.TypeTank: {BaseType: TypeOutpost},
+		unit.TypeBoat: {BaseType: TypeResource},
+	}
+}
+
+// HasRequirement returns true if the owner holds an intact base that
+// satisfies the requirement
+func (m *Manager) HasRequirement(owner Owner, req Requirement) bool {
+	for _, b := range m.Bases {
+		if b.Owner == owner && b.Type == req.BaseType && !b.IsDestroyed() {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRequirements returns a *RequirementError if the owner can't buy a
+// unit type yet, nil otherwise. Only new purchases are checked: losing a
+// prerequisite never takes away units already built or paid for.
+func (m *Manager) CheckRequirements(owner Owner, unitType unit.UnitType) error {
+	req, ok := m.Config.Requirements[unitType]
+	if !ok || m.HasRequirement(owner, req) {
+		return nil
+	}
+	return &RequirementError{UnitType: unitType, Needs: req}
+}
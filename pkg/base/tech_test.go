@@ -0,0 +1,54 @@
+package base
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestTankNeedsAnOutpost(t *testing.T) {
+	m := NewManager(DefaultConfig())
+	hq := m.AddBase(TypeHQ, rl.NewVector3(0, 0, 0), OwnerPlayer1)
+	outpost := m.AddBase(TypeOutpost, rl.NewVector3(10, 0, 0), OwnerNeutral)
+	m.AddCredits(OwnerPlayer1, 10000)
+
+	err := m.CheckRequirements(OwnerPlayer1, unit.TypeTank)
+	var reqErr *RequirementError
+	if !errors.As(err, &reqErr) || reqErr.Needs.BaseType != TypeOutpost {
+		t.Fatalf("tank without an outpost: %v, want a requirement error", err)
+	}
+	if err.Error() != "Tank requires an Outpost" {
+		t.Errorf("requirement reads %q", err.Error())
+	}
+	before := m.GetCredits(OwnerPlayer1)
+	if m.TryPurchaseUnit(hq.ID, unit.TypeTank, OwnerPlayer1) {
+		t.Error("bought a tank without an outpost")
+	}
+	if m.GetCredits(OwnerPlayer1) != before {
+		t.Error("refused tank was charged for")
+	}
+	if slices.Contains(m.GetPurchasableUnits(OwnerPlayer1), unit.TypeTank) {
+		t.Error("tank listed as purchasable without an outpost")
+	}
+
+	outpost.SetOwner(OwnerPlayer1)
+	if !m.TryPurchaseUnit(hq.ID, unit.TypeTank, OwnerPlayer1) {
+		t.Fatal("couldn't buy a tank while holding an outpost")
+	}
+	if !slices.Contains(m.GetPurchasableUnits(OwnerPlayer1), unit.TypeTank) {
+		t.Error("tank not listed as purchasable with an outpost")
+	}
+
+	// Losing the outpost stops new tanks, but the one bought stays queued
+	outpost.TakeDamage(outpost.MaxHealth * 10)
+	if m.TryPurchaseUnit(hq.ID, unit.TypeTank, OwnerPlayer1) {
+		t.Error("bought a tank after the outpost was destroyed")
+	}
+	if len(hq.SpawnQueue) != 1 || hq.SpawnQueue[0] != unit.TypeTank {
+		t.Errorf("queue is %v, want the tank already bought", hq.SpawnQueue)
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 
+	"github.com/chazu/herzog-drei/pkg/base"
 	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/projectile"
 	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
@@ -20,8 +22,24 @@ type Config struct {
 	MechRespawnDelay float32 // Seconds before mech respawns
 	MechSpawnInvuln  float32 // Seconds of invulnerability after spawn
 
+	// Escalating respawn: each recent death adds RespawnPenalty seconds, up
+	// to MaxRespawnDelay, and one death is forgiven per PenaltyDecayTime
+	// seconds without dying (RespawnPenalty 0 = flat delay)
+	RespawnPenalty   float32
+	MaxRespawnDelay  float32
+	PenaltyDecayTime float32
+
 	// Effects
 	ExplosionDuration float32
+	DecalLifetime     float32 // Seconds an impact mark lingers on the ground
+	MaxDecals         int     // Oldest decals are dropped past this
+
+	// Floating damage numbers
+	ShowDamageText        bool
+	DamageTextLifetime    float32 // Seconds a number stays up
+	DamageTextRise        float32 // World units a number drifts up over its life
+	DamageTextMergeWindow float32 // Hits this soon after a number add to it
+	MaxDamageTexts        int     // Oldest numbers are dropped past this
 }
 
 // DefaultConfig returns default combat configuration
@@ -32,7 +50,20 @@ func DefaultConfig() Config {
 		MechHitboxRadius: 0.6,
 		MechRespawnDelay: 3.0,
 		MechSpawnInvuln:  2.0,
+
+		RespawnPenalty:   2.0,
+		MaxRespawnDelay:  12.0,
+		PenaltyDecayTime: 30.0,
+
 		ExplosionDuration: 0.5,
+		DecalLifetime:     8.0,
+		MaxDecals:         64,
+
+		ShowDamageText:        true,
+		DamageTextLifetime:    1.0,
+		DamageTextRise:        1.5,
+		DamageTextMergeWindow: 0.25,
+		MaxDamageTexts:        32,
 	}
 }
 
@@ -45,30 +76,73 @@ type Explosion struct {
 	Elapsed  float32
 	Color    rl.Color
 	Active   bool
+	Team     unit.Team // Side that caused it, the player's own are never hidden by fog
+
+	// Splash damage at the center, falling off to none at MaxRadius
+	// (0 = just an effect)
+	Damage    float32
+	detonated bool // Damage has been applied
+}
+
+// Decal is a fading mark left on the ground by an impact
+type Decal struct {
+	Position rl.Vector3
+	Radius   float32
+	Elapsed  float32
+	Duration float32
 }
 
 // System manages combat interactions
 type System struct {
 	Config Config
 
+	// Every projectile in flight, the mech's and units' alike. Give the
+	// mech this pool to fire into.
+	Projectiles *projectile.Pool
+
+	// Bases projectiles can damage (nil = none)
+	bases *base.Manager
+
 	// Effects
-	explosions []Explosion
+	explosions  []Explosion
+	decals      []Decal
+	damageTexts []DamageText
 
 	// Mech respawn
 	mechDead        bool
 	respawnTimer    float32
 	invulnTimer     float32
 	respawnPosition rl.Vector3
+
+	// Respawn penalty bookkeeping, on the system's own match clock
+	clock         float32
+	deaths        int     // Recent deaths, before decay since lastDeathTime
+	lastDeathTime float32
 }
 
 // NewSystem creates a new combat system
 func NewSystem(cfg Config) *System {
 	return &System{
-		Config:     cfg,
-		explosions: make([]Explosion, 0, 32),
+		Config:      cfg,
+		Projectiles: projectile.NewPool(64),
+		explosions:  make([]Explosion, 0, 32),
 	}
 }
 
+// Reset clears all effects and respawn state for a new match
+func (s *System) Reset() {
+	s.Projectiles.Clear()
+	s.explosions = s.explosions[:0]
+	s.decals = s.decals[:0]
+	s.damageTexts = s.damageTexts[:0]
+	s.mechDead = false
+	s.respawnTimer = 0
+	s.invulnTimer = 0
+	s.clock = 0
+	s.deaths = 0
+	s.lastDeathTime = 0
+}
+
 // SetRespawnPosition sets where the mech will respawn
 func (s *System) SetRespawnPosition(pos rl.Vector3) {
 	s.respawnPosition = pos
@@ -76,66 +150,213 @@ func (s *System) SetRespawnPosition(pos rl.Vector3) {
 
 // Update runs combat checks and updates effects
 func (s *System) Update(dt float32, playerMech *mech.Mech, unitMgr *unit.Manager) {
+	// Nothing flies, burns, or counts down while time is stopped
+	if dt <= 0 {
+		return
+	}
+	s.clock += dt
+
 	// Handle mech respawn
 	s.updateMechRespawn(dt, playerMech)
 
+	// Projectiles fly on and hit whether or not whoever fired them lives.
+	// Point defense gets a shot at them before they reach anything.
+	s.Projectiles.Update(dt)
+	s.fireUnitShots(unitMgr.Shots())
+	s.checkInterceptions(unitMgr)
+	s.checkProjectileUnitCollisions(unitMgr)
+	s.checkProjectileBaseCollisions(unitMgr)
+	if !playerMech.IsDead() && s.invulnTimer <= 0 {
+		s.checkProjectileMechCollisions(playerMech, unitMgr)
+	}
+
+	// Mark where missed shots came down
+	s.spawnImpacts()
+
+	// Blasts hurt whatever's near them, even while the mech is down
+	s.applySplash(unitMgr)
+
 	// Skip combat checks if mech is dead or invulnerable
 	if playerMech.IsDead() {
 		return
 	}
 
-	// Check mech projectiles vs enemy units
-	s.checkProjectileUnitCollisions(playerMech, unitMgr)
+	// Pick the target the mech's next shots lead
+	candidates := unitMgr.GetEnemiesInRadius(playerMech.Position, playerMech.Config.AimAssistRange, playerMech.Team)
+	playerMech.AimTarget = playerMech.PickAimTarget(candidates)
 
 	// Check unit attacks vs mech (if not invulnerable)
 	if s.invulnTimer <= 0 {
 		s.checkUnitMechCollisions(playerMech, unitMgr)
 	}
 
+	// Show what got hurt this frame
+	s.collectDamage(playerMech, unitMgr)
+
 	// Update effects
 	s.updateExplosions(dt)
+	s.updateDecals(dt)
+	s.updateDamageTexts(dt)
 }
 
-// checkProjectileUnitCollisions checks mech projectiles hitting units
-func (s *System) checkProjectileUnitCollisions(playerMech *mech.Mech, unitMgr *unit.Manager) {
-	enemies := unitMgr.GetUnitsByTeam(unit.TeamEnemy)
+// checkInterceptions lets point-defense units shoot down hostile
+// projectiles that come within their intercept radius. Each interceptor is
+// limited to its intercept rate, so heavy fire gets through.
+func (s *System) checkInterceptions(unitMgr *unit.Manager) {
+	interceptors := make([]*unit.Unit, 0)
+	for _, u := range unitMgr.GetUnits() {
+		if u.IsTargetable() && u.Config.InterceptRadius > 0 {
+			interceptors = append(interceptors, u)
+		}
+	}
+	if len(interceptors) == 0 {
+		return
+	}
 
-	for i := range playerMech.Projectiles {
-		proj := &playerMech.Projectiles[i]
+	projectiles := s.Projectiles.Active()
+	for i := range projectiles {
+		proj := &projectiles[i]
 		if !proj.Alive {
 			continue
 		}
 
-		for _, enemy := range enemies {
-			if enemy.IsDead() {
+		for _, u := range interceptors {
+			if unitMgr.Alliances.AreAllied(u.Team, proj.Team) || !u.CanIntercept() {
+				continue
+			}
+			if distance3D(proj.Position, u.Position) > u.Config.InterceptRadius {
+				continue
+			}
+			proj.Alive = false
+			u.Intercept()
+			s.spawnHitEffect(proj.Position, u.Team)
+			break
+		}
+	}
+}
+
+// fireUnitShots puts shots units fired into flight alongside the mech's
+func (s *System) fireUnitShots(shots []unit.Shot) {
+	for _, shot := range shots {
+		s.fireUnitShot(shot)
+	}
+}
+
+// fireUnitShot puts one unit shot into flight
+func (s *System) fireUnitShot(shot unit.Shot) {
+	s.Projectiles.Fire(projectile.Projectile{
+		Position:    shot.From,
+		Velocity:    shot.Velocity,
+		Damage:      shot.Damage,
+		Style:       unitShotStyle(shot.Type),
+		MaxLife:     projectile.LifetimeForRange(shot.Range, rl.Vector3Length(shot.Velocity)),
+		Team:        shot.Team,
+		ShooterID:   shot.ShooterID,
+		ShooterType: shot.Type,
+	})
+}
+
+// unitShotStyle returns how a unit type's shots look
+func unitShotStyle(t unit.UnitType) projectile.Style {
+	switch t {
+	case unit.TypeSAM:
+		return projectile.MissileStyle
+	case unit.TypeTank, unit.TypeBoat:
+		return projectile.CannonStyle
+	default:
+		return projectile.TracerStyle
+	}
+}
+
+// checkProjectileUnitCollisions checks projectiles hitting units hostile
+// to whoever fired them, along the whole path each moved this frame
+func (s *System) checkProjectileUnitCollisions(unitMgr *unit.Manager) {
+	// Only units within a projectile's last move plus the widest hitbox can
+	// have been hit, so each one checks just those
+	widest := float32(0)
+	for _, u := range unitMgr.GetUnits() {
+		widest = float32(math.Max(float64(widest), float64(s.unitHitboxRadius(u))))
+	}
+
+	projectiles := s.Projectiles.Active()
+	for i := range projectiles {
+		proj := &projectiles[i]
+		if !proj.Alive || !hitsGround(proj) {
+			continue
+		}
+
+		reach := distance3D(proj.Previous, proj.Position) + s.Config.ProjectileRadius + widest
+		for _, target := range unitMgr.QueryRadius(proj.Position, reach) {
+			if !target.IsTargetable() || target.IsCarried() || proj.HasHit(target.ID) {
+				continue
+			}
+			if unitMgr.Alliances.AreAllied(target.Team, proj.Team) {
 				continue
 			}
 
 			// Check collision
-			dist := distance3D(proj.Position, enemy.Position)
-			hitRadius := s.Config.ProjectileRadius + s.Config.UnitHitboxRadius
+			hitRadius := s.Config.ProjectileRadius + s.unitHitboxRadius(target)
+			if !proj.Sweeps(target.Position, hitRadius) {
+				continue
+			}
 
-			if dist <= hitRadius {
-				// Hit! Apply damage
-				enemy.TakeDamage(proj.Damage)
-				proj.Alive = false
+			// Hit! Apply damage, scaled by the matchup if a unit fired
+			damage := proj.Damage
+			if proj.ShooterID != unit.MechID {
+				damage *= unit.DamageMultiplier(proj.ShooterType, target.Config.Type)
+			}
+			target.TakeDamage(damage)
 
-				// Spawn hit effect
-				s.spawnHitEffect(proj.Position)
+			// Spawn hit effect
+			s.spawnHitEffect(proj.Position, proj.Team)
 
-				// Spawn explosion if enemy died
-				if enemy.IsDead() {
-					s.spawnExplosion(enemy.Position, 1.0, rl.Orange)
+			// Spawn explosion if the target died, crediting the unit that
+			// fired if it's still around
+			if target.IsDead() {
+				s.spawnDeathExplosion(target, proj.Team)
+				if shooter := unitMgr.GetUnitByID(proj.ShooterID); shooter != nil && proj.ShooterID != unit.MechID {
+					shooter.Kills++
 				}
+			}
+
+			// Piercing shots carry on to the next unit in their path
+			if !proj.RegisterHit(target.ID) {
 				break
 			}
 		}
 	}
 }
 
-// checkUnitMechCollisions checks if units are attacking the mech
+// checkProjectileMechCollisions checks hostile projectiles hitting the mech
+func (s *System) checkProjectileMechCollisions(playerMech *mech.Mech, unitMgr *unit.Manager) {
+	hitRadius := s.Config.ProjectileRadius + s.Config.MechHitboxRadius
+
+	projectiles := s.Projectiles.Active()
+	for i := range projectiles {
+		proj := &projectiles[i]
+		if !proj.Alive || proj.HasHit(unit.MechID) || unitMgr.Alliances.AreAllied(playerMech.Team, proj.Team) {
+			continue
+		}
+		if !proj.Sweeps(playerMech.Position, hitRadius) {
+			continue
+		}
+
+		playerMech.TakeDamage(proj.Damage * s.mechDamageMultiplier(playerMech, proj.ShooterType))
+		s.spawnHitEffect(proj.Position, proj.Team)
+		proj.RegisterHit(unit.MechID)
+
+		if playerMech.IsDead() {
+			s.onMechDeath(playerMech)
+			return
+		}
+	}
+}
+
+// checkUnitMechCollisions has units in range fire on the mech. Units with
+// projectiles lead the mech and fire a shot it can dodge, anything else
+// hits instantly.
 func (s *System) checkUnitMechCollisions(playerMech *mech.Mech, unitMgr *unit.Manager) {
-	enemies := unitMgr.GetEnemiesInRadius(playerMech.Position, 10.0, unit.TeamPlayer)
+	enemies := unitMgr.GetEnemiesInRadius(playerMech.Position, 10.0, playerMech.Team)
 
 	for _, enemy := range enemies {
 		if enemy.IsDead() {
@@ -158,29 +379,62 @@ func (s *System) checkUnitMechCollisions(playerMech *mech.Mech, unitMgr *unit.Ma
 		}
 
 		// Attack if cooldown ready (using existing unit attack rate)
-		if enemy.AttackCooldown <= 0 {
-			playerMech.TakeDamage(enemy.Config.AttackDamage)
-			enemy.AttackCooldown = 1.0 / enemy.Config.AttackRate
+		if enemy.AttackCooldown > 0 {
+			continue
+		}
+		if enemy.FiresProjectiles() {
+			lead := unit.ComputeLeadPoint(enemy.Position, playerMech.Position, playerMech.Velocity, enemy.Config.ProjectileSpeed)
+			s.fireUnitShot(enemy.FireAt(lead))
+			continue
+		}
 
-			// Spawn small hit effect
-			s.spawnHitEffect(playerMech.Position)
+		playerMech.TakeDamage(enemy.Config.AttackDamage * s.mechDamageMultiplier(playerMech, enemy.Config.Type))
+		enemy.AttackCooldown = 1.0 / enemy.Config.AttackRate
 
-			// Check if mech died
-			if playerMech.IsDead() {
-				s.onMechDeath(playerMech)
-				return
-			}
+		// Spawn small hit effect
+		s.spawnHitEffect(playerMech.Position, enemy.Team)
+
+		// Check if mech died
+		if playerMech.IsDead() {
+			s.onMechDeath(playerMech)
+			return
 		}
 	}
 }
 
+// hitsGround returns true if a projectile can hit things on the ground.
+// Missiles from units that only shoot at air, like SAMs, fly over them.
+func hitsGround(proj *projectile.Projectile) bool {
+	return proj.ShooterID == unit.MechID || unit.GetConfig(proj.ShooterType).CanAttackGround
+}
+
+// mechDamageMultiplier returns how much of its damage a unit type deals to
+// the mech, which only matters to the matchup while it flies
+func (s *System) mechDamageMultiplier(playerMech *mech.Mech, attacker unit.UnitType) float32 {
+	if playerMech.Mode == mech.ModeJet {
+		return unit.AirDamageMultiplier(attacker)
+	}
+	return 1.0
+}
+
+// unitHitboxRadius returns the unit's own hitbox radius, or the configured
+// default for types that don't specify one
+func (s *System) unitHitboxRadius(u *unit.Unit) float32 {
+	if u.Config.HitboxRadius > 0 {
+		return u.Config.HitboxRadius
+	}
+	return s.Config.UnitHitboxRadius
+}
+
 // onMechDeath handles mech death
 func (s *System) onMechDeath(playerMech *mech.Mech) {
 	s.mechDead = true
-	s.respawnTimer = s.Config.MechRespawnDelay
+	s.respawnTimer = s.RespawnDelay()
+	s.deaths = s.RecentDeaths() + 1
+	s.lastDeathTime = s.clock
 
 	// Big explosion
-	s.spawnExplosion(playerMech.Position, 2.0, rl.Red)
+	s.spawnExplosion(playerMech.Position, 2.0, rl.Red, playerMech.Team)
 }
 
 // updateMechRespawn handles mech respawn timing
@@ -206,14 +460,39 @@ func (s *System) respawnMech(playerMech *mech.Mech) {
 	playerMech.Position = s.respawnPosition
 	playerMech.Velocity = rl.Vector3{}
 	playerMech.Health = playerMech.MaxHealth
+	playerMech.RefillEnergy()
+	playerMech.RefillBoost()
 	playerMech.Mode = mech.ModeJet
 	playerMech.State = mech.StateIdle
-	playerMech.Projectiles = playerMech.Projectiles[:0]
+	playerMech.RefillAmmo()
 
 	s.mechDead = false
 	s.invulnTimer = s.Config.MechSpawnInvuln
 }
 
+// RecentDeaths returns how many mech deaths still count toward the respawn
+// penalty, after forgiving one for each PenaltyDecayTime since the last
+func (s *System) RecentDeaths() int {
+	n := s.deaths
+	if s.Config.PenaltyDecayTime > 0 {
+		n -= int((s.clock - s.lastDeathTime) / s.Config.PenaltyDecayTime)
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// RespawnDelay returns how long the mech would wait to respawn if it died
+// now: the base delay plus the penalty for recent deaths, capped
+func (s *System) RespawnDelay() float32 {
+	delay := s.Config.MechRespawnDelay + s.Config.RespawnPenalty*float32(s.RecentDeaths())
+	if s.Config.MaxRespawnDelay > 0 && delay > s.Config.MaxRespawnDelay {
+		delay = s.Config.MaxRespawnDelay
+	}
+	return delay
+}
+
 // IsMechDead returns true if mech is waiting to respawn
 func (s *System) IsMechDead() bool {
 	return s.mechDead
@@ -234,8 +513,8 @@ func (s *System) GetInvulnTimer() float32 {
 	return s.invulnTimer
 }
 
-// spawnExplosion creates an explosion effect
-func (s *System) spawnExplosion(pos rl.Vector3, size float32, color rl.Color) {
+// spawnExplosion creates an explosion effect caused by a team
+func (s *System) spawnExplosion(pos rl.Vector3, size float32, color rl.Color, team unit.Team) {
 	s.explosions = append(s.explosions, Explosion{
 		Position:  pos,
 		Radius:    0.1,
@@ -244,11 +523,60 @@ func (s *System) spawnExplosion(pos rl.Vector3, size float32, color rl.Color) {
 		Elapsed:   0,
 		Color:     color,
 		Active:    true,
+		Team:      team,
 	})
 }
 
-// spawnHitEffect creates a small hit particle effect
-func (s *System) spawnHitEffect(pos rl.Vector3) {
+// spawnExplosionWithDamage creates an explosion that also damages units
+// around it once, the next time the system updates
+func (s *System) spawnExplosionWithDamage(pos rl.Vector3, radius, damage float32, color rl.Color, team unit.Team) {
+	s.spawnExplosion(pos, radius, color, team)
+	s.explosions[len(s.explosions)-1].Damage = damage
+}
+
+// spawnDeathExplosion blows up a destroyed unit, with its death blast if
+// it has one
+func (s *System) spawnDeathExplosion(u *unit.Unit, team unit.Team) {
+	if u.Config.DeathBlastDamage > 0 && u.Config.DeathBlastRadius > 0 {
+		s.spawnExplosionWithDamage(u.Position, u.Config.DeathBlastRadius, u.Config.DeathBlastDamage, rl.Orange, team)
+		return
+	}
+	s.spawnExplosion(u.Position, 1.0, rl.Orange, team)
+}
+
+// applySplash damages units caught in explosions that haven't gone off yet,
+// scaling from full damage at the center to none at the edge. Dead units
+// (including whatever blew up) are skipped. Units destroyed by a blast
+// explode in turn on the next update, so wrecks chain through clusters.
+func (s *System) applySplash(unitMgr *unit.Manager) {
+	n := len(s.explosions)
+	for i := 0; i < n; i++ {
+		e := &s.explosions[i]
+		if e.Damage <= 0 || e.detonated {
+			continue
+		}
+		e.detonated = true
+		pos, radius, damage, team := e.Position, e.MaxRadius, e.Damage, e.Team
+
+		for _, u := range unitMgr.GetUnitsInRadius(pos, radius) {
+			if !u.IsTargetable() || u.IsCarried() {
+				continue
+			}
+			falloff := 1 - distance3D(pos, u.Position)/radius
+			if falloff <= 0 {
+				continue
+			}
+			u.TakeDamage(damage * falloff)
+			if u.IsDead() {
+				// May grow the slice, so e isn't used past here
+				s.spawnDeathExplosion(u, team)
+			}
+		}
+	}
+}
+
+// spawnHitEffect creates a small hit particle effect caused by a team
+func (s *System) spawnHitEffect(pos rl.Vector3, team unit.Team) {
 	s.explosions = append(s.explosions, Explosion{
 		Position:  pos,
 		Radius:    0.05,
@@ -257,9 +585,59 @@ func (s *System) spawnHitEffect(pos rl.Vector3) {
 		Elapsed:   0,
 		Color:     rl.Yellow,
 		Active:    true,
+		Team:      team,
 	})
 }
 
+// spawnImpacts turns projectiles that came down this frame into ground
+// impacts
+func (s *System) spawnImpacts() {
+	for _, impact := range s.Projectiles.Impacts() {
+		s.spawnImpact(impact.Position, impact.Team)
+	}
+}
+
+// spawnImpact creates a dust puff and a scorch mark on the ground
+func (s *System) spawnImpact(pos rl.Vector3, team unit.Team) {
+	ground := rl.Vector3{X: pos.X, Y: 0, Z: pos.Z}
+	s.explosions = append(s.explosions, Explosion{
+		Position:  ground,
+		Radius:    0.05,
+		MaxRadius: 0.4,
+		Duration:  0.3,
+		Elapsed:   0,
+		Color:     rl.Gray,
+		Active:    true,
+		Team:      team,
+	})
+
+	s.decals = append(s.decals, Decal{
+		Position: ground,
+		Radius:   0.3,
+		Duration: s.Config.DecalLifetime,
+	})
+	if s.Config.MaxDecals > 0 && len(s.decals) > s.Config.MaxDecals {
+		s.decals = s.decals[len(s.decals)-s.Config.MaxDecals:]
+	}
+}
+
+// updateDecals ages ground decals and drops faded ones
+func (s *System) updateDecals(dt float32) {
+	active := s.decals[:0]
+	for _, d := range s.decals {
+		d.Elapsed += dt
+		if d.Elapsed < d.Duration {
+			active = append(active, d)
+		}
+	}
+	s.decals = active
+}
+
+// GetDecals returns active ground decals for rendering
+func (s *System) GetDecals() []Decal {
+	return s.decals
+}
+
 // updateExplosions updates explosion animations
 func (s *System) updateExplosions(dt float32) {
 	active := s.explosions[:0]
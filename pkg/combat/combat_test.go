@@ -0,0 +1,370 @@
+package combat
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/projectile"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// shotThroughTank fires a shot from a unit type straight through an enemy
+// tank and returns the damage the tank took
+func shotThroughTank(t *testing.T, shooter unit.UnitType) float32 {
+	t.Helper()
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	tank := um.Spawn(unit.TypeTank, unit.TeamPlayer, rl.NewVector3(5, 0, 0))
+	tank.SpawnTimer = 0
+	tank.State = unit.StateIdle
+	m := mech.New(rl.NewVector3(50, 3, 50), mech.DefaultConfig())
+
+	s.fireUnitShot(unit.Shot{
+		From:      rl.NewVector3(0, 0.5, 0),
+		Velocity:  rl.NewVector3(20, 0, 0),
+		Damage:    40,
+		Range:     15,
+		Team:      unit.TeamEnemy,
+		ShooterID: 99,
+		Type:      shooter,
+	})
+	for i := 0; i < 60; i++ {
+		s.Update(1.0/60, m, um)
+	}
+	return tank.MaxHealth - tank.Health
+}
+
+func TestSAMMissilesPassOverGroundUnits(t *testing.T) {
+	if dmg := shotThroughTank(t, unit.TypeSAM); dmg != 0 {
+		t.Errorf("SAM missile did %v damage to a tank", dmg)
+	}
+	if dmg := shotThroughTank(t, unit.TypeTank); dmg <= 0 {
+		t.Error("tank shell did no damage to a tank")
+	}
+}
+
+func TestSplashFallsOffWithDistance(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	spawn := func(x float32) *unit.Unit {
+		u := um.Spawn(unit.TypeTank, unit.TeamPlayer, rl.NewVector3(x, 0, 0))
+		u.SpawnTimer = 0
+		u.State = unit.StateIdle
+		return u
+	}
+	center, half, outside := spawn(0), spawn(2), spawn(4.5)
+
+	s.spawnExplosionWithDamage(rl.NewVector3(0, 0, 0), 4, 40, rl.Orange, unit.TeamEnemy)
+	s.applySplash(um)
+
+	full := center.MaxHealth - center.Health
+	if full <= 0 {
+		t.Fatal("unit at the center took no damage")
+	}
+	if got := half.MaxHealth - half.Health; got < full*0.45 || got > full*0.55 {
+		t.Errorf("unit at half the radius took %v, want about half of %v", got, full)
+	}
+	if got := outside.MaxHealth - outside.Health; got != 0 {
+		t.Errorf("unit outside the radius took %v", got)
+	}
+
+	// The blast goes off once
+	s.applySplash(um)
+	if got := center.MaxHealth - center.Health; got != full {
+		t.Errorf("blast hit again, center unit took %v then %v", full, got-full)
+	}
+}
+
+// grazes fires a mech shot along the ground that passes a unit at a
+// sideways offset from its center, and reports whether it hit
+func grazes(ut unit.UnitType, hitbox, offset float32) bool {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	target := um.Spawn(ut, unit.TeamEnemy, rl.NewVector3(5, 0, offset))
+	target.Config.HitboxRadius = hitbox
+	target.SpawnTimer = 0
+	target.State = unit.StateIdle
+	m := mech.New(rl.NewVector3(50, 3, 50), mech.DefaultConfig())
+
+	s.Projectiles.Fire(projectile.Projectile{
+		Position:  rl.NewVector3(0, 0.01, 0), // Just off the ground
+		Velocity:  rl.NewVector3(20, 0, 0),
+		Damage:    10,
+		MaxLife:   1,
+		Team:      unit.TeamPlayer,
+		ShooterID: unit.MechID,
+	})
+	for i := 0; i < 60; i++ {
+		s.Update(1.0/60, m, um)
+	}
+	return target.Health < target.MaxHealth
+}
+
+func TestHitboxSizeDecidesGrazes(t *testing.T) {
+	cfg := DefaultConfig()
+	tank, infantry := unit.GetConfig(unit.TypeTank), unit.GetConfig(unit.TypeInfantry)
+	if tank.HitboxRadius <= infantry.HitboxRadius {
+		t.Fatalf("tank hitbox %v isn't larger than infantry's %v", tank.HitboxRadius, infantry.HitboxRadius)
+	}
+
+	// Between the two radii: clips the tank, passes the infantry
+	offset := cfg.ProjectileRadius + (tank.HitboxRadius+infantry.HitboxRadius)/2
+	if !grazes(unit.TypeTank, tank.HitboxRadius, offset) {
+		t.Errorf("shot %v from center missed a tank with a %v hitbox", offset, tank.HitboxRadius)
+	}
+	if grazes(unit.TypeInfantry, infantry.HitboxRadius, offset) {
+		t.Errorf("shot %v from center hit infantry with a %v hitbox", offset, infantry.HitboxRadius)
+	}
+
+	// Types without their own radius use the default
+	inside := cfg.ProjectileRadius + cfg.UnitHitboxRadius - 0.05
+	outside := cfg.ProjectileRadius + cfg.UnitHitboxRadius + 0.05
+	if !grazes(unit.TypeInfantry, 0, inside) || grazes(unit.TypeInfantry, 0, outside) {
+		t.Errorf("unit without a hitbox radius doesn't use the default %v", cfg.UnitHitboxRadius)
+	}
+}
+
+// lineOfTanks spawns enemy tanks along the x axis
+func lineOfTanks(um *unit.Manager, xs ...float32) []*unit.Unit {
+	tanks := make([]*unit.Unit, len(xs))
+	for i, x := range xs {
+		tanks[i] = um.Spawn(unit.TypeTank, unit.TeamEnemy, rl.NewVector3(x, 0, 0))
+		tanks[i].SpawnTimer = 0
+		tanks[i].State = unit.StateIdle
+	}
+	return tanks
+}
+
+// fireAlongX fires a mech shot down the x axis with a pierce budget and
+// lets it fly out
+func fireAlongX(s *System, um *unit.Manager, speed float32, pierce int) {
+	m := mech.New(rl.NewVector3(50, 3, 50), mech.DefaultConfig())
+	s.Projectiles.Fire(projectile.Projectile{
+		Position:  rl.NewVector3(0, 0.01, 0),
+		Velocity:  rl.NewVector3(speed, 0, 0),
+		Damage:    10,
+		MaxLife:   12 / speed,
+		Pierce:    pierce,
+		Team:      unit.TeamPlayer,
+		ShooterID: unit.MechID,
+	})
+	for i := 0; i < int(60*12/speed)+1; i++ {
+		s.Update(1.0/60, m, um)
+	}
+}
+
+func TestPiercingShotStopsAfterBudget(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	tanks := lineOfTanks(um, 4, 6, 8)
+
+	fireAlongX(s, um, 20, 1)
+	for i, tank := range tanks {
+		hit := tank.Health < tank.MaxHealth
+		if want := i < 2; hit != want {
+			t.Errorf("tank %d hit = %v, want %v with one pierce", i, hit, want)
+		}
+	}
+}
+
+func TestPiercingShotHitsEachUnitOnce(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	tank := lineOfTanks(um, 4)[0]
+
+	// Slow enough to spend many frames inside the hitbox
+	fireAlongX(s, um, 2, 5)
+	want := unit.HitPoints(10 * (1 - tank.Config.Armor))
+	if got := tank.MaxHealth - tank.Health; got != want {
+		t.Errorf("tank took %v, want one hit of %v", got, want)
+	}
+}
+
+func TestPointDefenseInterceptsAtItsRate(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	spawn := func(ut unit.UnitType, pos rl.Vector3) *unit.Unit {
+		u := um.Spawn(ut, unit.TeamPlayer, pos)
+		u.SpawnTimer = 0
+		u.State = unit.StateIdle
+		return u
+	}
+	sam := spawn(unit.TypeSAM, rl.NewVector3(5, 0, 1.5))
+	tank := spawn(unit.TypeTank, rl.NewVector3(8, 0, 0))
+	m := mech.New(rl.NewVector3(50, 3, 50), mech.DefaultConfig())
+
+	// fire sends a tank shell past the SAM at the tank, returning the
+	// damage the tank took
+	fire := func(team unit.Team) float32 {
+		before := tank.Health
+		s.fireUnitShot(unit.Shot{
+			From:      rl.NewVector3(0, 0.5, 0),
+			Velocity:  rl.NewVector3(20, 0, 0),
+			Damage:    40,
+			Range:     15,
+			Team:      team,
+			ShooterID: 99,
+			Type:      unit.TypeTank,
+		})
+		for i := 0; i < 60; i++ {
+			s.Update(1.0/60, m, um)
+		}
+		return before - tank.Health
+	}
+
+	if dmg := fire(unit.TeamEnemy); dmg != 0 {
+		t.Errorf("tank took %v from a shell that passed the SAM", dmg)
+	}
+	if sam.CanIntercept() {
+		t.Error("SAM ready again right after intercepting")
+	}
+
+	// Reloading, the next shell gets through
+	if dmg := fire(unit.TeamEnemy); dmg <= 0 {
+		t.Error("second shell was intercepted while the SAM was reloading")
+	}
+
+	// Once reloaded it ignores friendly fire and stays ready
+	sam.InterceptCooldown = 0
+	fire(unit.TeamPlayer)
+	if !sam.CanIntercept() {
+		t.Error("SAM spent its point defense on a friendly shell")
+	}
+}
+
+func TestMechAndUnitShotsShareOnePool(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	spawn := func(ut unit.UnitType, team unit.Team, pos rl.Vector3) *unit.Unit {
+		u := um.Spawn(ut, team, pos)
+		u.SpawnTimer = 0
+		u.State = unit.StateIdle
+		return u
+	}
+	enemyTank := spawn(unit.TypeTank, unit.TeamEnemy, rl.NewVector3(0, 0, 5))
+	playerTank := spawn(unit.TypeTank, unit.TeamPlayer, rl.NewVector3(10, 0, 5))
+	shooter := spawn(unit.TypeTank, unit.TeamEnemy, rl.NewVector3(10, 0, 0))
+
+	// The mech fires north at the enemy tank, into the system's pool
+	m := mech.New(rl.NewVector3(0, 0, 0), mech.DefaultConfig())
+	m.Projectiles = s.Projectiles
+	m.InputShoot = true
+	m.Update(1.0 / 60)
+	m.InputShoot = false
+
+	// The enemy tank fires north at the player's, then is gone before
+	// its shell lands
+	s.fireUnitShot(unit.Shot{
+		From:      rl.NewVector3(10, 0.5, 0.5),
+		Velocity:  rl.NewVector3(0, 0, 20),
+		Damage:    40,
+		Range:     15,
+		Team:      unit.TeamEnemy,
+		ShooterID: shooter.ID,
+		Type:      unit.TypeTank,
+	})
+	um.Detach(shooter.ID)
+
+	if n := s.Projectiles.Count(); n != 2 {
+		t.Fatalf("%d projectiles in the pool, want the mech's and the tank's", n)
+	}
+	s.Update(1.0/60, m, um)
+	for _, p := range s.Projectiles.Active() {
+		if p.Position == p.Previous {
+			t.Errorf("shot from %d didn't move", p.ShooterID)
+		}
+	}
+
+	for i := 0; i < 60; i++ {
+		s.Update(1.0/60, m, um)
+	}
+	if enemyTank.Health == enemyTank.MaxHealth {
+		t.Error("mech shot didn't hit the enemy tank")
+	}
+	if playerTank.Health == playerTank.MaxHealth {
+		t.Error("shell from a removed tank didn't hit")
+	}
+	if n := s.Projectiles.Count(); n != 0 {
+		t.Errorf("%d projectiles still in flight", n)
+	}
+}
+
+func TestQuickDeathsEscalateRespawn(t *testing.T) {
+	cfg := DefaultConfig()
+	s := NewSystem(cfg)
+	m := mech.New(rl.NewVector3(0, 0, 0), mech.DefaultConfig())
+
+	var delays []float32
+	for i := 0; i < 3; i++ {
+		s.onMechDeath(m)
+		delays = append(delays, s.respawnTimer)
+		s.respawnMech(m)
+		s.clock += 1
+	}
+	if delays[0] != cfg.MechRespawnDelay {
+		t.Errorf("first death waits %.1fs, want the base %.1fs", delays[0], cfg.MechRespawnDelay)
+	}
+	if delays[1] <= delays[0] || delays[2] <= delays[1] {
+		t.Errorf("quick deaths didn't lengthen the respawn: %v", delays)
+	}
+
+	for i := 0; i < 20; i++ {
+		s.onMechDeath(m)
+		s.respawnMech(m)
+	}
+	if got := s.RespawnDelay(); got != cfg.MaxRespawnDelay {
+		t.Errorf("respawn delay after many deaths = %.1fs, want capped at %.1fs", got, cfg.MaxRespawnDelay)
+	}
+}
+
+func TestSurvivingDecaysRespawnPenalty(t *testing.T) {
+	cfg := DefaultConfig()
+	s := NewSystem(cfg)
+	m := mech.New(rl.NewVector3(0, 0, 0), mech.DefaultConfig())
+	for i := 0; i < 3; i++ {
+		s.onMechDeath(m)
+		s.respawnMech(m)
+	}
+	penalized := s.RespawnDelay()
+
+	s.clock += cfg.PenaltyDecayTime
+	if got := s.RespawnDelay(); got >= penalized {
+		t.Errorf("respawn delay still %.1fs after surviving a while, was %.1fs", got, penalized)
+	}
+	s.clock += cfg.PenaltyDecayTime * 10
+	if got := s.RespawnDelay(); got != cfg.MechRespawnDelay {
+		t.Errorf("respawn delay %.1fs after a long survival, want back to %.1fs", got, cfg.MechRespawnDelay)
+	}
+}
+
+func TestUnitShotDamagesMechOnArrival(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	m := mech.New(rl.NewVector3(0, 0, 0), mech.DefaultConfig())
+
+	s.fireUnitShot(unit.Shot{
+		From:      rl.NewVector3(10, 0.5, 0),
+		Velocity:  rl.NewVector3(-20, 0, 0),
+		Damage:    20,
+		Range:     15,
+		Team:      unit.TeamEnemy,
+		ShooterID: 99,
+		Type:      unit.TypeTank,
+	})
+	s.Update(1.0/60, m, um)
+	if m.Health != m.MaxHealth {
+		t.Fatal("shot hurt the mech before it could get there")
+	}
+
+	for i := 0; i < 60; i++ {
+		s.Update(1.0/60, m, um)
+	}
+	if m.Health == m.MaxHealth {
+		t.Error("unit shot didn't hurt the mech")
+	}
+	if n := s.Projectiles.Count(); n != 0 {
+		t.Errorf("%d projectiles still in flight after hitting", n)
+	}
+}
@@ -0,0 +1,112 @@
+package combat
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// DamageText is a floating number showing damage dealt to something
+type DamageText struct {
+	Position rl.Vector3 // Where the number starts rising from
+	Amount   float32
+	Elapsed  float32
+	Duration float32
+
+	source any // What took the damage, hits on it close together add up
+}
+
+// Progress returns how far through its lifetime the text is, 0 to 1
+func (d DamageText) Progress() float32 {
+	if d.Duration <= 0 {
+		return 1
+	}
+	t := d.Elapsed / d.Duration
+	if t > 1 {
+		return 1
+	}
+	return t
+}
+
+// CurrentPosition returns where the text is now, having risen by up to rise
+func (d DamageText) CurrentPosition(rise float32) rl.Vector3 {
+	pos := d.Position
+	pos.Y += rise * d.Progress()
+	return pos
+}
+
+// Alpha returns the text's opacity, holding solid for the first half of its
+// life and fading out over the second
+func (d DamageText) Alpha() uint8 {
+	t := d.Progress()
+	if t < 0.5 {
+		return 255
+	}
+	return uint8(255 * (1 - t) * 2)
+}
+
+// DamageColor returns the color a damage number is drawn in by severity
+func DamageColor(amount float32) rl.Color {
+	switch {
+	case amount >= 25:
+		return rl.Red
+	case amount >= 10:
+		return rl.Yellow
+	default:
+		return rl.White
+	}
+}
+
+// ReportDamage shows a damage number for a hit on source at a position.
+// Use this for things the combat system doesn't track itself, like bases.
+func (s *System) ReportDamage(source any, pos rl.Vector3, amount float32) {
+	if !s.Config.ShowDamageText || amount <= 0 {
+		return
+	}
+
+	// Fold rapid hits on the same source into its latest number
+	for i := len(s.damageTexts) - 1; i >= 0; i-- {
+		d := &s.damageTexts[i]
+		if d.source == source && d.Elapsed < s.Config.DamageTextMergeWindow {
+			d.Amount += amount
+			return
+		}
+	}
+
+	s.damageTexts = append(s.damageTexts, DamageText{
+		Position: rl.Vector3{X: pos.X, Y: pos.Y + 1.0, Z: pos.Z},
+		Amount:   amount,
+		Duration: s.Config.DamageTextLifetime,
+		source:   source,
+	})
+	if s.Config.MaxDamageTexts > 0 && len(s.damageTexts) > s.Config.MaxDamageTexts {
+		s.damageTexts = s.damageTexts[len(s.damageTexts)-s.Config.MaxDamageTexts:]
+	}
+}
+
+// collectDamage turns damage taken this frame by units and the mech into
+// floating numbers
+func (s *System) collectDamage(playerMech *mech.Mech, unitMgr *unit.Manager) {
+	for _, u := range unitMgr.GetUnits() {
+		s.ReportDamage(u, u.Position, u.ConsumeDamage())
+	}
+	s.ReportDamage(playerMech, playerMech.Position, playerMech.ConsumeDamage())
+}
+
+// updateDamageTexts ages floating numbers and drops finished ones
+func (s *System) updateDamageTexts(dt float32) {
+	active := s.damageTexts[:0]
+	for _, d := range s.damageTexts {
+		d.Elapsed += dt
+		if d.Elapsed < d.Duration {
+			active = append(active, d)
+		}
+	}
+	s.damageTexts = active
+}
+
+// GetDamageTexts returns active floating damage numbers for rendering
+func (s *System) GetDamageTexts() []DamageText {
+	return s.damageTexts
+}
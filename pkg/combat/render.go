@@ -2,27 +2,62 @@ package combat
 
 import (
 	"fmt"
+	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
+	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/projectile"
+	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
-// Renderer handles rendering of combat effects
-type Renderer struct{}
+// explosionDebris is how many debris particles an explosion asks for at
+// full quality
+const explosionDebris = 12
+
+// Renderer handles rendering of combat effects and projectiles
+type Renderer struct {
+	Graphics graphics.Settings
+
+	// Visibility hides effects the player can't see (nil = show all)
+	Visibility graphics.Visibility
+
+	projectiles *projectile.Renderer
+}
 
 // NewRenderer creates a new combat renderer
 func NewRenderer() *Renderer {
-	return &Renderer{}
+	return &Renderer{
+		Graphics:    graphics.DefaultSettings(),
+		projectiles: projectile.NewRenderer(),
+	}
 }
 
-// Draw renders all combat effects
+// Draw renders all combat effects and projectiles in flight
 func (r *Renderer) Draw(sys *System) {
+	r.drawDecals(sys)
 	r.drawExplosions(sys)
+
+	r.projectiles.Graphics = r.Graphics
+	r.projectiles.Visibility = r.Visibility
+	r.projectiles.Draw(sys.Projectiles)
+}
+
+// drawDecals renders scorch marks left by impacts
+func (r *Renderer) drawDecals(sys *System) {
+	for _, d := range sys.GetDecals() {
+		// Fade out over the decal's lifetime
+		alpha := uint8(160 * (1.0 - d.Elapsed/d.Duration))
+		pos := rl.Vector3{X: d.Position.X, Y: 0.02, Z: d.Position.Z}
+		rl.DrawCylinder(pos, d.Radius, d.Radius, 0.01, 12, rl.Color{R: 30, G: 25, B: 20, A: alpha})
+	}
 }
 
 // drawExplosions renders explosion effects
 func (r *Renderer) drawExplosions(sys *System) {
 	for _, e := range sys.GetExplosions() {
-		if !e.Active {
+		if !r.ShowsExplosion(e) {
 			continue
 		}
 
@@ -30,17 +65,85 @@ func (r *Renderer) drawExplosions(sys *System) {
 		t := e.Elapsed / e.Duration
 		alpha := uint8(255 * (1.0 - t))
 
+		rings, slices := r.Graphics.SphereRings, r.Graphics.SphereSlices
+
 		// Inner core (bright)
 		coreColor := rl.Color{R: 255, G: 255, B: 200, A: alpha}
-		rl.DrawSphere(e.Position, e.Radius*0.3, coreColor)
+		rl.DrawSphereEx(e.Position, e.Radius*0.3, rings, slices, coreColor)
 
 		// Outer ring (colored)
 		outerColor := rl.Color{R: e.Color.R, G: e.Color.G, B: e.Color.B, A: alpha / 2}
-		rl.DrawSphere(e.Position, e.Radius, outerColor)
+		rl.DrawSphereEx(e.Position, e.Radius, rings, slices, outerColor)
 
 		// Draw ring on ground
 		groundPos := rl.Vector3{X: e.Position.X, Y: 0.05, Z: e.Position.Z}
 		rl.DrawCircle3D(groundPos, e.Radius*1.5, rl.Vector3{X: 1, Y: 0, Z: 0}, 90, outerColor)
+
+		r.drawDebris(e, t, alpha)
+	}
+}
+
+// ShowsExplosion returns true if an explosion should be drawn: it's still
+// going and either the player caused it or can see where it is
+func (r *Renderer) ShowsExplosion(e Explosion) bool {
+	if !e.Active {
+		return false
+	}
+	return e.Team == unit.TeamPlayer || graphics.IsVisible(r.Visibility, e.Position)
+}
+
+// ShowsDamageText returns true if a damage number should be drawn: it's on
+// something of the player's or somewhere the player can see
+func (r *Renderer) ShowsDamageText(d DamageText) bool {
+	switch source := d.source.(type) {
+	case *unit.Unit:
+		if source.Team == unit.TeamPlayer {
+			return true
+		}
+	case *mech.Mech:
+		if source.Team == unit.TeamPlayer {
+			return true
+		}
+	}
+	return graphics.IsVisible(r.Visibility, d.Position)
+}
+
+// drawDebris throws debris outward from an explosion, scaled by quality
+func (r *Renderer) drawDebris(e Explosion, t float32, alpha uint8) {
+	count := r.Graphics.ParticleCount(explosionDebris)
+	debrisColor := rl.Color{R: 90, G: 80, B: 70, A: alpha}
+
+	for i := 0; i < count; i++ {
+		angle := float64(i) / float64(count) * 2 * math.Pi
+		dist := e.MaxRadius * 1.5 * t
+		// Debris arcs up then falls back down
+		height := e.MaxRadius * 2 * t * (1 - t)
+
+		pos := rl.Vector3{
+			X: e.Position.X + float32(math.Cos(angle))*dist,
+			Y: e.Position.Y + height,
+			Z: e.Position.Z + float32(math.Sin(angle))*dist,
+		}
+		rl.DrawCube(pos, 0.1, 0.1, 0.1, debrisColor)
+	}
+}
+
+// DrawDamageTexts draws floating damage numbers over the 3D view. Call it
+// in 2D mode; numbers are projected from world space so they always face
+// the camera.
+func (r *Renderer) DrawDamageTexts(sys *System, camera rl.Camera3D) {
+	const fontSize = 16
+	for _, d := range sys.GetDamageTexts() {
+		if !r.ShowsDamageText(d) {
+			continue
+		}
+		pos := rl.GetWorldToScreen(d.CurrentPosition(sys.Config.DamageTextRise), camera)
+		text := fmt.Sprintf("%.0f", d.Amount)
+		width := rl.MeasureText(text, fontSize)
+
+		color := DamageColor(d.Amount)
+		color.A = d.Alpha()
+		rl.DrawText(text, int32(pos.X)-width/2, int32(pos.Y), fontSize, color)
 	}
 }
 
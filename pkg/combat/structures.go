@@ -0,0 +1,61 @@
+package combat
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// baseHitFlag marks a base's ID in a projectile's hit list, so bases and
+// units with the same ID aren't mistaken for each other
+const baseHitFlag = 1 << 31
+
+// SetBaseManager gives the system the bases projectiles can damage (nil =
+// projectiles pass through structures)
+func (s *System) SetBaseManager(bm *base.Manager) {
+	s.bases = bm
+}
+
+// checkProjectileBaseCollisions checks projectiles hitting bases whose
+// owner is hostile to whoever fired them. Neutral and destroyed bases
+// can't be hit. Structures rise from the ground, so a shot anywhere over a
+// base's footprint hits it whatever its height.
+func (s *System) checkProjectileBaseCollisions(unitMgr *unit.Manager) {
+	if s.bases == nil {
+		return
+	}
+
+	projectiles := s.Projectiles.Active()
+	for i := range projectiles {
+		proj := &projectiles[i]
+		if !hitsGround(proj) {
+			continue
+		}
+		for _, b := range s.bases.Bases {
+			if !proj.Alive {
+				break
+			}
+			hitID := baseHitFlag | uint32(b.ID)
+			if b.IsDestroyed() || proj.HasHit(hitID) {
+				continue
+			}
+			team, ok := b.Owner.Team()
+			if !ok || unitMgr.Alliances.AreAllied(team, proj.Team) {
+				continue
+			}
+
+			center := rl.Vector3{X: b.Position.X, Y: proj.Position.Y, Z: b.Position.Z}
+			if !proj.Sweeps(center, s.Config.ProjectileRadius+b.FootprintRadius()) {
+				continue
+			}
+
+			b.TakeDamage(proj.Damage)
+			s.spawnHitEffect(proj.Position, proj.Team)
+			if b.IsDestroyed() {
+				s.spawnExplosion(b.Position, b.FootprintRadius()*1.5, rl.Orange, proj.Team)
+			}
+			proj.RegisterHit(hitID)
+		}
+	}
+}
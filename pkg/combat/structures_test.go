@@ -0,0 +1,56 @@
+package combat
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/mech"
+	"github.com/chazu/herzog-drei/pkg/projectile"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+func TestProjectilesDestroyOutpost(t *testing.T) {
+	s := NewSystem(DefaultConfig())
+	um := unit.NewManager(10)
+	bm := base.NewManager(base.DefaultConfig())
+	s.SetBaseManager(bm)
+	outpost := bm.AddBase(base.TypeOutpost, rl.NewVector3(10, 0, 0), base.OwnerPlayer2)
+	m := mech.New(rl.NewVector3(50, 3, 50), mech.DefaultConfig())
+
+	const maxShots = 100
+	shots := 0
+	for ; shots < maxShots && !outpost.IsDestroyed(); shots++ {
+		before := outpost.Health
+		s.Projectiles.Fire(projectile.Projectile{
+			Position:  rl.NewVector3(0, 1, 0),
+			Velocity:  rl.NewVector3(20, 0, 0),
+			Damage:    40,
+			MaxLife:   projectile.LifetimeForRange(15, 20),
+			Team:      unit.TeamPlayer,
+			ShooterID: unit.MechID,
+		})
+		for i := 0; i < 60; i++ {
+			s.Update(1.0/60, m, um)
+		}
+		if outpost.Health >= before {
+			t.Fatalf("shot %d did no damage to the outpost", shots+1)
+		}
+	}
+	if !outpost.IsDestroyed() {
+		t.Fatalf("outpost survived %d shots at %v health", maxShots, outpost.Health)
+	}
+	if shots < 2 {
+		t.Errorf("outpost destroyed by %d shot", shots)
+	}
+
+	// Rubble earns its owner nothing
+	credits := bm.GetPlayer(base.OwnerPlayer2).Credits
+	for i := 0; i < 600; i++ {
+		bm.Update(1.0 / 60)
+	}
+	if got := bm.GetPlayer(base.OwnerPlayer2).Credits; got != credits {
+		t.Errorf("destroyed outpost earned %v credits", got-credits)
+	}
+}
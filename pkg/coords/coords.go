@@ -0,0 +1,75 @@
+// Package coords converts between world space, grid cells, and screen
+// rectangles that show a whole grid. The tile map, the pathfinder, and the
+// minimap all describe their cells with a Grid so the math lives in one
+// place and agrees everywhere.
+package coords
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Grid is a rectangle of square cells laid over the world's X/Z plane.
+// Cell (0, 0) starts at the origin corner and cells run toward +X and +Z.
+type Grid struct {
+	Width, Height int
+	CellSize      float32 // World units per cell
+
+	OriginX, OriginZ float32 // World position of the outer corner of cell (0, 0)
+}
+
+// NewGrid creates a grid whose first cell's corner is at the world origin
+func NewGrid(width, height int, cellSize float32) Grid {
+	return Grid{Width: width, Height: height, CellSize: cellSize}
+}
+
+// InBounds returns true if a cell lies on the grid
+func (g Grid) InBounds(x, y int) bool {
+	return x >= 0 && x < g.Width && y >= 0 && y < g.Height
+}
+
+// Index returns a cell's position in a row-major slice of the grid
+func (g Grid) Index(x, y int) int {
+	return y*g.Width + x
+}
+
+// CellPosition returns a world position in fractional cell units, so the
+// corner of cell (2, 3) is (2, 3) and its center is (2.5, 3.5)
+func (g Grid) CellPosition(worldX, worldZ float32) (float32, float32) {
+	return (worldX - g.OriginX) / g.CellSize, (worldZ - g.OriginZ) / g.CellSize
+}
+
+// WorldPosition is the inverse of CellPosition
+func (g Grid) WorldPosition(cellX, cellY float32) (float32, float32) {
+	return g.OriginX + cellX*g.CellSize, g.OriginZ + cellY*g.CellSize
+}
+
+// WorldToCell returns the cell containing a world position. Positions off
+// the grid give cells out of bounds, including negative ones.
+func (g Grid) WorldToCell(worldX, worldZ float32) (int, int) {
+	cx, cy := g.CellPosition(worldX, worldZ)
+	return int(math.Floor(float64(cx))), int(math.Floor(float64(cy)))
+}
+
+// CellToWorld returns the world position of a cell's center
+func (g Grid) CellToWorld(x, y int) (float32, float32) {
+	return g.WorldPosition(float32(x)+0.5, float32(y)+0.5)
+}
+
+// WorldToScreen maps a world position into a screen rectangle that shows
+// the whole grid
+func (g Grid) WorldToScreen(worldX, worldZ float32, rect rl.Rectangle) rl.Vector2 {
+	cx, cy := g.CellPosition(worldX, worldZ)
+	return rl.Vector2{
+		X: rect.X + cx*rect.Width/float32(g.Width),
+		Y: rect.Y + cy*rect.Height/float32(g.Height),
+	}
+}
+
+// ScreenToWorld is the inverse of WorldToScreen
+func (g Grid) ScreenToWorld(p rl.Vector2, rect rl.Rectangle) (float32, float32) {
+	cx := (p.X - rect.X) * float32(g.Width) / rect.Width
+	cy := (p.Y - rect.Y) * float32(g.Height) / rect.Height
+	return g.WorldPosition(cx, cy)
+}
@@ -0,0 +1,52 @@
+package coords
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-3
+}
+
+func TestConversionsRoundTrip(t *testing.T) {
+	grids := []Grid{
+		NewGrid(64, 48, 1),
+		NewGrid(10, 20, 2.5),
+		{Width: 7, Height: 5, CellSize: 0.5, OriginX: -3, OriginZ: 4},
+	}
+	rect := rl.Rectangle{X: 20, Y: 30, Width: 160, Height: 120}
+
+	for _, g := range grids {
+		for y := 0; y < g.Height; y++ {
+			for x := 0; x < g.Width; x++ {
+				wx, wz := g.CellToWorld(x, y)
+				if cx, cy := g.WorldToCell(wx, wz); cx != x || cy != y {
+					t.Fatalf("%+v: cell (%d, %d) -> world (%v, %v) -> cell (%d, %d)", g, x, y, wx, wz, cx, cy)
+				}
+
+				fx, fy := g.CellPosition(wx, wz)
+				if bx, bz := g.WorldPosition(fx, fy); !near(bx, wx) || !near(bz, wz) {
+					t.Fatalf("%+v: world (%v, %v) -> cell position -> world (%v, %v)", g, wx, wz, bx, bz)
+				}
+
+				p := g.WorldToScreen(wx, wz, rect)
+				if sx, sz := g.ScreenToWorld(p, rect); !near(sx, wx) || !near(sz, wz) {
+					t.Fatalf("%+v: world (%v, %v) -> screen -> world (%v, %v)", g, wx, wz, sx, sz)
+				}
+			}
+		}
+	}
+}
+
+func TestCellZeroStartsAtOrigin(t *testing.T) {
+	g := NewGrid(64, 48, 1)
+	if x, y := g.WorldToCell(0, 0); x != 0 || y != 0 {
+		t.Errorf("origin is in cell (%d, %d), want (0, 0)", x, y)
+	}
+	if x, y := g.WorldToCell(-0.5, 10); g.InBounds(x, y) {
+		t.Errorf("(-0.5, 10) maps to in-bounds cell (%d, %d)", x, y)
+	}
+}
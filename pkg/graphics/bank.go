@@ -0,0 +1,49 @@
+package graphics
+
+import "math"
+
+// Banking into turns
+const (
+	MaxBank      = 0.4 // Steepest roll into a turn, radians
+	BankMinSpeed = 1.0 // Below this speed things stay level
+
+	bankPerTurnRate   = 0.2 // Radians of roll per radian per second of turning
+	turnRateSmoothing = 6.0 // How quickly a tracked turn rate follows the real one, per second
+)
+
+// BankAngle returns how far something turning at turnRate (radians per
+// second about Y) rolls into the turn, clamped to maxBank either way.
+// Anything slower than BankMinSpeed stays level. The result is a roll about
+// the forward axis: turning left (positive turnRate) dips the left side.
+func BankAngle(turnRate, speed, maxBank float32) float32 {
+	if speed < BankMinSpeed {
+		return 0
+	}
+	bank := -turnRate * bankPerTurnRate
+	if bank > maxBank {
+		return maxBank
+	}
+	if bank < -maxBank {
+		return -maxBank
+	}
+	return bank
+}
+
+// TrackTurnRate eases a tracked turn rate toward the turn made this frame,
+// so banking leans in and levels out smoothly rather than snapping.
+// rotationDelta is the change in heading this frame, wrapped to -Pi..Pi.
+func TrackTurnRate(current, rotationDelta, dt float32) float32 {
+	if dt <= 0 {
+		return current
+	}
+	t := turnRateSmoothing * dt
+	if t > 1 {
+		t = 1
+	}
+	return current + (rotationDelta/dt-current)*t
+}
+
+// Degrees converts radians to degrees for rl.Rotatef
+func Degrees(radians float32) float32 {
+	return radians * 180.0 / math.Pi
+}
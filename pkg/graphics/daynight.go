@@ -0,0 +1,95 @@
+package graphics
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DayCycle tracks time of day for sky color, scene tint, and sight ranges.
+// Time runs from 0 (midnight) through 0.5 (noon) back to 1.
+type DayCycle struct {
+	Enabled   bool
+	DayLength float32 // Real seconds per full day
+	Time      float32 // Fraction of the day, 0-1
+	StartTime float32 // Time a new match begins at
+
+	DaySky    rl.Color
+	NightSky  rl.Color
+	NightTint rl.Color // Overlay laid over the 3D view at full darkness
+
+	NightSightScale float32 // Sight range multiplier at midnight
+}
+
+// NewDayCycle creates a day cycle starting mid-morning
+func NewDayCycle() *DayCycle {
+	return &DayCycle{
+		Enabled:         true,
+		DayLength:       240.0,
+		Time:            0.35,
+		StartTime:       0.35,
+		DaySky:          rl.SkyBlue,
+		NightSky:        rl.NewColor(15, 20, 45, 255),
+		NightTint:       rl.NewColor(10, 15, 50, 140),
+		NightSightScale: 0.6,
+	}
+}
+
+// Reset returns to the match start time
+func (d *DayCycle) Reset() {
+	d.Time = d.StartTime
+}
+
+// Update advances the time of day
+func (d *DayCycle) Update(dt float32) {
+	if !d.Enabled || d.DayLength <= 0 || dt <= 0 {
+		return
+	}
+	d.Time += dt / d.DayLength
+	d.Time -= float32(math.Floor(float64(d.Time)))
+}
+
+// Daylight returns how bright it is, 0 at midnight to 1 at noon.
+// Always 1 with the cycle disabled.
+func (d *DayCycle) Daylight() float32 {
+	if !d.Enabled {
+		return 1
+	}
+	return DaylightAt(d.Time)
+}
+
+// DaylightAt returns the daylight level for a time of day
+func DaylightAt(t float32) float32 {
+	return float32(0.5 - 0.5*math.Cos(2*math.Pi*float64(t)))
+}
+
+// ClearColor returns the sky color to clear the frame with
+func (d *DayCycle) ClearColor() rl.Color {
+	return LerpColor(d.NightSky, d.DaySky, d.Daylight())
+}
+
+// Tint returns the overlay darkening the 3D view, transparent at noon
+func (d *DayCycle) Tint() rl.Color {
+	tint := d.NightTint
+	tint.A = uint8(float32(tint.A) * (1 - d.Daylight()))
+	return tint
+}
+
+// SightScale returns the multiplier applied to sight ranges, 1 in full
+// daylight down to NightSightScale at midnight
+func (d *DayCycle) SightScale() float32 {
+	return d.NightSightScale + (1-d.NightSightScale)*d.Daylight()
+}
+
+// LerpColor blends from a to b by t (0-1)
+func LerpColor(a, b rl.Color, t float32) rl.Color {
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	mix := func(x, y uint8) uint8 {
+		return uint8(float32(x) + (float32(y)-float32(x))*t + 0.5)
+	}
+	return rl.Color{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
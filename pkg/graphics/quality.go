@@ -0,0 +1,92 @@
+package graphics
+
+// Quality is the graphics detail level
+type Quality int
+
+const (
+	QualityLow Quality = iota
+	QualityMedium
+	QualityHigh
+)
+
+// String returns the display name for a quality level
+func (q Quality) String() string {
+	switch q {
+	case QualityLow:
+		return "Low"
+	case QualityMedium:
+		return "Medium"
+	case QualityHigh:
+		return "High"
+	default:
+		return "Unknown"
+	}
+}
+
+// Next returns the following quality level, wrapping from High to Low
+func (q Quality) Next() Quality {
+	return (q + 1) % (QualityHigh + 1)
+}
+
+// Settings holds the effect budgets for a quality level
+type Settings struct {
+	Quality Quality
+
+	ParticleScale float32 // Multiplier on requested particle counts
+	MaxDecals     int     // Ground decals kept before the oldest are dropped
+	Shadows       bool    // Draw blob shadows under units and the mech
+	SphereRings   int32   // Tessellation for effect spheres
+	SphereSlices  int32
+}
+
+// SettingsFor returns the effect budgets for a quality level
+func SettingsFor(q Quality) Settings {
+	switch q {
+	case QualityLow:
+		return Settings{
+			Quality:       QualityLow,
+			ParticleScale: 0.25,
+			MaxDecals:     16,
+			Shadows:       false,
+			SphereRings:   6,
+			SphereSlices:  6,
+		}
+	case QualityMedium:
+		return Settings{
+			Quality:       QualityMedium,
+			ParticleScale: 0.5,
+			MaxDecals:     64,
+			Shadows:       true,
+			SphereRings:   10,
+			SphereSlices:  10,
+		}
+	default:
+		return Settings{
+			Quality:       QualityHigh,
+			ParticleScale: 1.0,
+			MaxDecals:     256,
+			Shadows:       true,
+			SphereRings:   16,
+			SphereSlices:  16,
+		}
+	}
+}
+
+// DefaultSettings returns the settings used when nothing is configured
+func DefaultSettings() Settings {
+	return SettingsFor(QualityHigh)
+}
+
+// ParticleCount scales a requested particle count by the quality level.
+// Any effect that asked for particles gets at least one so gameplay-critical
+// effects stay visible on Low.
+func (s Settings) ParticleCount(requested int) int {
+	if requested <= 0 {
+		return 0
+	}
+	count := int(float32(requested) * s.ParticleScale)
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
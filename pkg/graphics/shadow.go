@@ -0,0 +1,61 @@
+package graphics
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Blob shadow tuning
+const (
+	shadowAlpha      = 64  // Opacity of a shadow cast by something on the ground
+	shadowFadeHeight = 6.0 // Altitude by which a shadow has shrunk and faded to its minimum
+	shadowMinScale   = 0.4 // Size and opacity left to a shadow at or above shadowFadeHeight
+	shadowGroundY    = 0.01
+)
+
+// SunDirection is the way shadows fall across the ground, they slide this
+// far per unit of altitude
+var SunDirection = rl.Vector2{X: 0.35, Y: 0.25}
+
+// Shadow is a blob shadow on the ground
+type Shadow struct {
+	Center rl.Vector3
+	Radius float32
+	Alpha  uint8
+}
+
+// ShadowScale returns how much a shadow shrinks and fades at an altitude,
+// 1 on the ground down to shadowMinScale high in the air
+func ShadowScale(altitude float32) float32 {
+	if altitude <= 0 {
+		return 1
+	}
+	if altitude >= shadowFadeHeight {
+		return shadowMinScale
+	}
+	return 1 - (1-shadowMinScale)*altitude/shadowFadeHeight
+}
+
+// ShadowFor returns the shadow cast by something with a footprint radius at
+// a position, its height above the ground plane being its altitude. Higher
+// things cast smaller, fainter shadows pushed further along SunDirection.
+func ShadowFor(pos rl.Vector3, radius float32) Shadow {
+	altitude := pos.Y
+	if altitude < 0 {
+		altitude = 0
+	}
+	scale := ShadowScale(altitude)
+	return Shadow{
+		Center: rl.Vector3{
+			X: pos.X + SunDirection.X*altitude,
+			Y: shadowGroundY, // Slightly above ground to avoid z-fighting
+			Z: pos.Z + SunDirection.Y*altitude,
+		},
+		Radius: radius * scale,
+		Alpha:  uint8(shadowAlpha * scale),
+	}
+}
+
+// DrawShadow draws a blob shadow
+func DrawShadow(s Shadow) {
+	rl.DrawCylinder(s.Center, s.Radius, s.Radius, 0.01, 16, rl.Color{A: s.Alpha})
+}
@@ -0,0 +1,17 @@
+package graphics
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Visibility tells renderers which parts of the world the player can
+// currently see, so effects in unseen areas don't give away enemy activity
+type Visibility interface {
+	IsVisibleAt(pos rl.Vector3) bool
+}
+
+// IsVisible returns true if the player can see a position. A nil
+// Visibility sees everything.
+func IsVisible(v Visibility, pos rl.Vector3) bool {
+	return v == nil || v.IsVisibleAt(pos)
+}
@@ -0,0 +1,76 @@
+package mech
+
+// MagSize returns how many shots a mode's magazine holds, 0 for unlimited
+func (c Config) MagSize(mode Mode) int {
+	if mode == ModeJet {
+		return c.JetMagSize
+	}
+	return c.RobotMagSize
+}
+
+// otherMode returns the mode the mech transforms into from mode
+func otherMode(mode Mode) Mode {
+	if mode == ModeJet {
+		return ModeRobot
+	}
+	return ModeJet
+}
+
+// IsReloading returns true while the mech is reloading and can't fire
+func (m *Mech) IsReloading() bool {
+	return m.ReloadTimer > 0
+}
+
+// RefillAmmo fills both magazines and ends any reload
+func (m *Mech) RefillAmmo() {
+	m.CurrentAmmo = m.Config.MagSize(m.Mode)
+	m.stowedAmmo = m.Config.MagSize(otherMode(m.Mode))
+	m.ReloadTimer = 0
+}
+
+// swapMagazines stows the current mode's magazine and loads the other, for
+// a transformation. A reload in progress is abandoned.
+func (m *Mech) swapMagazines() {
+	m.CurrentAmmo, m.stowedAmmo = m.stowedAmmo, m.CurrentAmmo
+	m.ReloadTimer = 0
+}
+
+// startReload begins reloading the current magazine
+func (m *Mech) startReload() {
+	if m.Config.ReloadDuration <= 0 {
+		m.CurrentAmmo = m.Config.MagSize(m.Mode)
+		return
+	}
+	m.ReloadTimer = m.Config.ReloadDuration
+}
+
+// spendAmmo uses up a shot, reloading once the magazine runs dry
+func (m *Mech) spendAmmo() {
+	if m.Config.MagSize(m.Mode) <= 0 {
+		return // Unlimited
+	}
+	m.CurrentAmmo--
+	if m.CurrentAmmo <= 0 {
+		m.CurrentAmmo = 0
+		m.startReload()
+	}
+}
+
+// updateReload advances a reload, starting one if the magazine is empty.
+// Returns true while the mech can't fire for reloading.
+func (m *Mech) updateReload(dt float32) bool {
+	if m.ReloadTimer > 0 {
+		m.ReloadTimer -= dt
+		if m.ReloadTimer > 0 {
+			return true
+		}
+		m.ReloadTimer = 0
+		m.CurrentAmmo = m.Config.MagSize(m.Mode)
+		return false
+	}
+	if m.Config.MagSize(m.Mode) > 0 && m.CurrentAmmo <= 0 {
+		m.startReload()
+		return m.IsReloading()
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package mech
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestFireToEmptyReloadsAndResumes(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	m.InputShoot = true
+	step := 1 / cfg.JetFireRate // One shot per frame
+
+	// Fire the magazine dry, a shot each frame
+	for i := 0; i < cfg.JetMagSize; i++ {
+		if m.IsReloading() {
+			t.Fatalf("reloading after %d of %d shots", i, cfg.JetMagSize)
+		}
+		m.Update(step)
+	}
+	if m.CurrentAmmo != 0 || m.Projectiles.Count() != cfg.JetMagSize {
+		t.Fatalf("%d shots fired, %d left, want the whole magazine", m.Projectiles.Count(), m.CurrentAmmo)
+	}
+	if !m.IsReloading() {
+		t.Fatal("empty magazine didn't start reloading")
+	}
+
+	// Holding the trigger through the reload fires nothing
+	m.Update(cfg.ReloadDuration - 0.1)
+	if !m.IsReloading() || m.Projectiles.Count() != cfg.JetMagSize {
+		t.Fatalf("fired or finished reloading early, %v s left", m.ReloadTimer)
+	}
+
+	// The reload finishes on time and firing picks up the same frame
+	m.Update(0.2)
+	if m.IsReloading() {
+		t.Fatal("still reloading after ReloadDuration")
+	}
+	if m.Projectiles.Count() != cfg.JetMagSize+1 || m.CurrentAmmo != cfg.JetMagSize-1 {
+		t.Errorf("%d shots fired, %d left after reloading, want a fresh magazine and one more shot", m.Projectiles.Count(), m.CurrentAmmo)
+	}
+}
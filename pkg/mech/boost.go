@@ -0,0 +1,48 @@
+package mech
+
+// CanBoost returns true if there's boost energy to spend. Once the meter
+// runs dry it must recharge to BoostResumeEnergy before boosting again.
+func (m *Mech) CanBoost() bool {
+	return !m.boostLockout && m.BoostEnergy > 0
+}
+
+// RefillBoost fills the boost meter
+func (m *Mech) RefillBoost() {
+	m.BoostEnergy = m.Config.MaxBoostEnergy
+	m.boostLockout = false
+}
+
+// updateBoost decides whether the jet is boosting this frame and drains or
+// recharges the meter to match. Boosting takes jet mode, movement input,
+// and energy to spend.
+func (m *Mech) updateBoost(dt float32) {
+	moving := m.InputMove.X != 0 || m.InputMove.Y != 0
+	m.Boosting = m.InputBoost && moving && m.Mode == ModeJet && m.State != StateTransforming && m.CanBoost()
+
+	if m.Boosting {
+		m.BoostEnergy -= m.Config.BoostDrain * dt
+		if m.BoostEnergy <= 0 {
+			m.BoostEnergy = 0
+			m.boostLockout = true
+		}
+		return
+	}
+
+	m.BoostEnergy += m.Config.BoostRegen * dt
+	if m.BoostEnergy > m.Config.MaxBoostEnergy {
+		m.BoostEnergy = m.Config.MaxBoostEnergy
+	}
+	if m.boostLockout && m.BoostEnergy >= m.Config.BoostResumeEnergy {
+		m.boostLockout = false
+	}
+}
+
+// jetSpeed returns the jet's top speed this frame, boosted or not and
+// slowed by any load
+func (m *Mech) jetSpeed() float32 {
+	speed := m.Config.JetSpeed * m.loadFactor()
+	if m.Boosting {
+		return speed * m.Config.BoostMultiplier
+	}
+	return speed
+}
@@ -0,0 +1,73 @@
+package mech
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestBoostDrainsAndRegenerates(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	m.InputMove = rl.Vector2{X: 1}
+	m.InputBoost = true
+
+	m.Update(1)
+	if !m.Boosting || m.BoostEnergy != cfg.MaxBoostEnergy-cfg.BoostDrain {
+		t.Fatalf("energy %v after a second of boost, want %v", m.BoostEnergy, cfg.MaxBoostEnergy-cfg.BoostDrain)
+	}
+
+	// Run dry, then the meter is locked out until it recharges enough
+	for i := 0; i < 3; i++ {
+		m.Update(1)
+	}
+	if m.Boosting || m.BoostEnergy >= cfg.BoostResumeEnergy || m.CanBoost() {
+		t.Fatalf("still boosting on %v energy after running dry", m.BoostEnergy)
+	}
+
+	m.InputBoost = false
+	before := m.BoostEnergy
+	m.Update(0.5)
+	if m.BoostEnergy != before+cfg.BoostRegen*0.5 {
+		t.Errorf("energy %v after half a second idle, want %v", m.BoostEnergy, before+cfg.BoostRegen*0.5)
+	}
+
+	m.Update(cfg.BoostResumeEnergy / cfg.BoostRegen)
+	if !m.CanBoost() {
+		t.Errorf("can't boost with %v energy, past the %v to resume", m.BoostEnergy, cfg.BoostResumeEnergy)
+	}
+}
+
+func TestBoostSpeedsUpOnlyWithEnergy(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	m.InputMove = rl.Vector2{X: 1}
+
+	// Cruise up to top speed
+	for i := 0; i < 20; i++ {
+		m.Update(0.1)
+	}
+	if speed := m.HorizontalSpeed(); speed > cfg.JetSpeed+0.01 {
+		t.Fatalf("cruising at %v, over JetSpeed %v", speed, cfg.JetSpeed)
+	}
+
+	// Boosting pulls ahead while energy lasts
+	m.InputBoost = true
+	var fastest float32
+	for m.BoostEnergy > 0 {
+		m.Update(0.1)
+		fastest = max(fastest, m.HorizontalSpeed())
+	}
+	if fastest <= cfg.JetSpeed+1 {
+		t.Errorf("top speed %v while boosting, want well over JetSpeed %v", fastest, cfg.JetSpeed)
+	}
+
+	// Out of energy, the jet falls back to cruising with boost held, at
+	// least until the meter recharges enough to boost again
+	for i := 0; i < 10; i++ {
+		m.Update(0.1)
+	}
+	if speed := m.HorizontalSpeed(); m.Boosting || speed > cfg.JetSpeed+0.01 {
+		t.Errorf("at %v with boost held and no energy, want back to JetSpeed %v", speed, cfg.JetSpeed)
+	}
+}
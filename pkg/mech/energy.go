@@ -0,0 +1,57 @@
+package mech
+
+// Ability is a mech ability that draws from the energy pool
+type Ability int
+
+const (
+	AbilityScan Ability = iota
+)
+
+// String returns the ability's display name
+func (a Ability) String() string {
+	switch a {
+	case AbilityScan:
+		return "Scan"
+	default:
+		return "Unknown"
+	}
+}
+
+// AbilityCost returns the energy an ability costs to use
+func (c Config) AbilityCost(a Ability) float32 {
+	switch a {
+	case AbilityScan:
+		return c.ScanEnergyCost
+	default:
+		return 0
+	}
+}
+
+// HasEnergyFor returns true if the energy pool covers an ability's cost
+func (m *Mech) HasEnergyFor(a Ability) bool {
+	return m.Energy >= m.Config.AbilityCost(a)
+}
+
+// spendEnergy pays for an ability, returning false without spending if the
+// pool is short. Abilities check this before starting their cooldowns so a
+// failed use costs nothing.
+func (m *Mech) spendEnergy(a Ability) bool {
+	if !m.HasEnergyFor(a) {
+		return false
+	}
+	m.Energy -= m.Config.AbilityCost(a)
+	return true
+}
+
+// updateEnergy regenerates the energy pool
+func (m *Mech) updateEnergy(dt float32) {
+	m.Energy += m.Config.EnergyRegen * dt
+	if m.Energy > m.MaxEnergy {
+		m.Energy = m.MaxEnergy
+	}
+}
+
+// RefillEnergy fills the energy pool
+func (m *Mech) RefillEnergy() {
+	m.Energy = m.MaxEnergy
+}
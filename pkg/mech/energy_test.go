@@ -0,0 +1,57 @@
+package mech
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestScanSpendsEnergy(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	cost := cfg.AbilityCost(AbilityScan)
+
+	if !m.StartScan(m.Position) {
+		t.Fatal("couldn't scan on a full energy pool")
+	}
+	if m.Energy != cfg.MaxEnergy-cost {
+		t.Errorf("energy %v after a scan, want %v", m.Energy, cfg.MaxEnergy-cost)
+	}
+}
+
+func TestScanShortOfEnergyFailsFree(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	m.Energy = cfg.AbilityCost(AbilityScan) - 1
+
+	if m.StartScan(m.Position) {
+		t.Fatal("scanned without the energy for it")
+	}
+	if m.Energy != cfg.AbilityCost(AbilityScan)-1 {
+		t.Errorf("failed scan left %v energy", m.Energy)
+	}
+	if m.ScanCooldownTimer != 0 || m.IsScanning() {
+		t.Errorf("failed scan started a %v cooldown", m.ScanCooldownTimer)
+	}
+}
+
+func TestEnergyRegenerates(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	m.Energy = 0
+
+	m.Update(2)
+	if want := 2 * cfg.EnergyRegen; m.Energy != want {
+		t.Errorf("energy %v after two seconds, want %v", m.Energy, want)
+	}
+
+	// Enough regeneration pays for another scan, and the pool stops at full
+	m.Update(cfg.AbilityCost(AbilityScan) / cfg.EnergyRegen)
+	if !m.HasEnergyFor(AbilityScan) {
+		t.Errorf("only %v energy after regenerating a scan's worth", m.Energy)
+	}
+	m.Update(cfg.MaxEnergy / cfg.EnergyRegen)
+	if m.Energy != cfg.MaxEnergy {
+		t.Errorf("energy %v after a long rest, want the %v maximum", m.Energy, cfg.MaxEnergy)
+	}
+}
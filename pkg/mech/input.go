@@ -6,13 +6,45 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// MoveMode controls what the movement keys are relative to
+type MoveMode int
+
+const (
+	MoveWorld  MoveMode = iota // W is always world +Z
+	MoveCamera                 // W moves away from the camera
+	MoveMech                   // W/S drive forward/back, A/D turn (tank controls)
+)
+
+// String returns the display name for a movement mode
+func (mm MoveMode) String() string {
+	switch mm {
+	case MoveWorld:
+		return "World"
+	case MoveCamera:
+		return "Camera"
+	case MoveMech:
+		return "Mech"
+	default:
+		return "Unknown"
+	}
+}
+
+// Next returns the following movement mode, wrapping around
+func (mm MoveMode) Next() MoveMode {
+	return (mm + 1) % (MoveMech + 1)
+}
+
 // InputHandler processes player input for the mech
 type InputHandler struct {
+	MoveMode  MoveMode
+	CameraYaw float32 // Camera heading, needed for camera-relative movement
+
 	transformPressed bool // Track transform key state for edge detection
 	pickupPressed    bool // Track pickup key state for edge detection
 	dropPressed      bool // Track drop key state for edge detection
 	orderNextPressed bool // Track order cycle next key state
 	orderPrevPressed bool // Track order cycle prev key state
+	scanPressed      bool // Track scan key state
 }
 
 // NewInputHandler creates a new input handler
@@ -38,14 +70,26 @@ func (h *InputHandler) Update(m *Mech) {
 		moveX = -1
 	}
 
-	// Normalize diagonal movement
-	if moveX != 0 && moveZ != 0 {
-		invLen := float32(1.0 / math.Sqrt(2))
-		moveX *= invLen
-		moveZ *= invLen
+	if h.MoveMode == MoveMech {
+		// Tank controls: A/D turn, W/S throttle along the current facing
+		m.TankControls = true
+		m.InputTurn = -moveX
+		m.InputMove = TankMoveInput(moveZ, m.Rotation)
+	} else {
+		// Normalize diagonal movement
+		if moveX != 0 && moveZ != 0 {
+			invLen := float32(1.0 / math.Sqrt(2))
+			moveX *= invLen
+			moveZ *= invLen
+		}
+
+		m.TankControls = false
+		m.InputTurn = 0
+		m.InputMove = TransformMoveInput(rl.Vector2{X: moveX, Y: moveZ}, h.MoveMode, h.CameraYaw)
 	}
 
-	m.InputMove = rl.Vector2{X: moveX, Y: moveZ}
+	// Boost input (Left Ctrl) - held, Shift is taken as a modifier
+	m.InputBoost = rl.IsKeyDown(rl.KeyLeftControl)
 
 	// Shooting input (Space or Left Mouse)
 	m.InputShoot = rl.IsKeyDown(rl.KeySpace) || rl.IsMouseButtonDown(rl.MouseLeftButton)
@@ -74,6 +118,11 @@ func (h *InputHandler) Update(m *Mech) {
 	m.InputOrderPrev = orderPrevDown && !h.orderPrevPressed
 	h.orderPrevPressed = orderPrevDown
 
+	// Scan input (V key) - edge triggered
+	scanDown := rl.IsKeyDown(rl.KeyV)
+	m.InputScan = scanDown && !h.scanPressed
+	h.scanPressed = scanDown
+
 	// Handle order cycling immediately
 	if m.InputOrderNext {
 		m.CycleOrderNext()
@@ -82,3 +131,30 @@ func (h *InputHandler) Update(m *Mech) {
 		m.CycleOrderPrev()
 	}
 }
+
+// TransformMoveInput maps raw key input (X = right, Y = forward) to a world
+// X/Z direction. Camera-relative movement rotates it so forward points along
+// the camera's yaw and right points to the screen's right.
+func TransformMoveInput(input rl.Vector2, mode MoveMode, cameraYaw float32) rl.Vector2 {
+	if mode != MoveCamera {
+		return input
+	}
+
+	sin := float32(math.Sin(float64(cameraYaw)))
+	cos := float32(math.Cos(float64(cameraYaw)))
+
+	// Forward is (sin, cos); screen right is (-cos, sin)
+	return rl.Vector2{
+		X: input.Y*sin - input.X*cos,
+		Y: input.Y*cos + input.X*sin,
+	}
+}
+
+// TankMoveInput returns the world movement for a throttle (-1 back to
+// 1 forward) along a facing
+func TankMoveInput(throttle, rotation float32) rl.Vector2 {
+	return rl.Vector2{
+		X: float32(math.Sin(float64(rotation))) * throttle,
+		Y: float32(math.Cos(float64(rotation))) * throttle,
+	}
+}
@@ -1,13 +1,36 @@
 package mech
 
 import (
+	"errors"
 	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 
+	"github.com/chazu/herzog-drei/pkg/graphics"
+	"github.com/chazu/herzog-drei/pkg/projectile"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
 	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
+const (
+	carryOffset      = 0.8 // How far below the mech a carried unit hangs
+	dropSearchRadius = 4   // Tiles searched for passable ground when dropping
+	dropClearance    = 0.8 // Dropped units land at least this far from other units
+	dropSpread       = 0.9 // Radius of the ring several units are dropped in
+)
+
+// Reasons a pickup can fail
+var (
+	ErrCannotCarry   = errors.New("mech can't pick up right now")
+	ErrNoUnit        = errors.New("no unit to pick up")
+	ErrUnitCarried   = errors.New("unit is already being carried")
+	ErrUnitDead      = errors.New("unit is dead")
+	ErrUnitSpawning  = errors.New("unit is still spawning")
+	ErrUnitNotFriend = errors.New("unit is not on our team")
+	ErrTooHeavy      = errors.New("unit is too heavy to carry")
+	ErrCarryFull     = errors.New("mech can't carry any more units")
+)
+
 // Mode represents the mech's current form
 type Mode int
 
@@ -36,18 +59,67 @@ type Config struct {
 	RobotAcceleration float32
 	FlightHeight     float32
 
+	// Jet boost, drawing on its own meter that recharges while not boosting.
+	// A meter run dry must recharge to BoostResumeEnergy before boosting again.
+	BoostMultiplier   float32 // Jet speed multiplier while boosting
+	MaxBoostEnergy    float32
+	BoostDrain        float32 // Energy per second while boosting
+	BoostRegen        float32 // Energy per second otherwise
+	BoostResumeEnergy float32
+
 	// Combat
 	JetFireRate      float32 // shots per second
 	RobotFireRate    float32
 	JetDamage        float32
 	RobotDamage      float32
 	ProjectileSpeed  float32
+	JetRange         float32 // World units a jet shot travels before expiring
+	RobotRange       float32
+	JetPierce        int     // Extra units a jet shot passes through (railgun loadouts)
+	RobotPierce      int
+
+	// Magazines, one per mode (0 = unlimited), refilled over ReloadDuration
+	// seconds once emptied
+	JetMagSize     int
+	RobotMagSize   int
+	ReloadDuration float32
+
+	// Aim assist
+	AimAssistAngle float32 // Max radians off the nose a target can be to get led shots
+	AimAssistRange float32
+
+	// Projectile visuals per weapon
+	JetProjectileStyle   projectile.Style
+	RobotProjectileStyle projectile.Style
 
 	// Health
 	MaxHealth float32
 
+	// Transport: up to MaxCarryCount units weighing no more than
+	// MaxCarryWeight together, the jet losing speed in proportion to its
+	// load, CarrySlowdown of it at the weight limit
+	MaxCarryCount  int
+	MaxCarryWeight float32
+	CarrySlowdown  float32
+
+	// Tank controls
+	TankTurnRate float32 // radians per second
+
 	// Transformation
 	TransformDuration float32 // seconds
+
+	// Energy pool shared by abilities
+	MaxEnergy   float32
+	EnergyRegen float32 // Energy per second
+
+	// Vision
+	SightRange float32 // World units the mech sees around itself
+
+	// Scan ability
+	ScanRadius     float32 // World units revealed around the scan center
+	ScanDuration   float32 // Seconds the reveal lasts
+	ScanCooldown   float32 // Seconds between scans
+	ScanEnergyCost float32
 }
 
 // DefaultConfig returns the default mech configuration
@@ -59,26 +131,50 @@ func DefaultConfig() Config {
 		RobotAcceleration: 20.0,
 		FlightHeight:      3.0,
 
+		BoostMultiplier:   1.8,
+		MaxBoostEnergy:    100.0,
+		BoostDrain:        40.0, // Two and a half seconds from full
+		BoostRegen:        20.0,
+		BoostResumeEnergy: 30.0,
+
 		JetFireRate:     2.0,
 		RobotFireRate:   5.0,
 		JetDamage:       10.0,
 		RobotDamage:     25.0,
 		ProjectileSpeed: 30.0,
+		JetRange:        90.0, // Long-range shots from altitude
+		RobotRange:      45.0, // Rapid fire, shorter reach
+
+		JetMagSize:     12, // Six seconds of fire either way
+		RobotMagSize:   30,
+		ReloadDuration: 2.0,
+
+		AimAssistAngle: 0.35,
+		AimAssistRange: 15.0,
+
+		JetProjectileStyle:   projectile.TracerStyle,
+		RobotProjectileStyle: projectile.CannonStyle,
 
 		MaxHealth: 100.0,
 
+		MaxCarryCount:  4,
+		MaxCarryWeight: 6.0, // A tank, or a squad of infantry
+		CarrySlowdown:  0.5,
+
+		TankTurnRate: 3.0,
+
 		TransformDuration: 0.5,
-	}
-}
 
-// Projectile represents a bullet/missile fired by the mech
-type Projectile struct {
-	Position  rl.Vector3
-	Velocity  rl.Vector3
-	Damage    float32
-	Alive     bool
-	LifeTime  float32
-	MaxLife   float32
+		MaxEnergy:   100.0,
+		EnergyRegen: 5.0,
+
+		SightRange: 14.0, // Sees farther than any unit, from altitude or not
+
+		ScanRadius:     12.0,
+		ScanDuration:   4.0,
+		ScanCooldown:   20.0,
+		ScanEnergyCost: 40.0,
+	}
 }
 
 // Mech represents the player's transforming mech
@@ -89,22 +185,44 @@ type Mech struct {
 	Position rl.Vector3
 	Velocity rl.Vector3
 	Rotation float32 // Y-axis rotation in radians
+	TurnRate float32 // Smoothed turning speed in radians per second, for banking
 
 	// State
 	Mode  Mode
 	State State
 
 	// Health
-	Health    float32
-	MaxHealth float32
+	Health      float32
+	MaxHealth   float32
+	damageTaken float32 // Since the last ConsumeDamage
+
+	// Ability energy
+	Energy    float32
+	MaxEnergy float32
+
+	// Jet boost
+	BoostEnergy  float32
+	Boosting     bool // Boosting this frame
+	boostLockout bool // Ran dry, waiting to recharge to BoostResumeEnergy
 
 	// Combat
 	FireCooldown float32
-	Projectiles  []Projectile
+	Projectiles  *projectile.Pool // Where shots go, share it with the combat system
+	AimTarget    *unit.Unit // Aim-assist target, shots are led toward it
+
+	// Ammo in the current mode's magazine, the other mode's kept stowed
+	CurrentAmmo int
+	ReloadTimer float32 // Remaining reload time (0 = not reloading)
+	stowedAmmo  int
 
 	// Transformation
 	TransformProgress float32 // 0.0 to 1.0, used for animation
 
+	// Scan ability
+	ScanCenter        rl.Vector3 // Where the active scan is centered
+	ScanTimer         float32    // Remaining scan time (0 = inactive)
+	ScanCooldownTimer float32    // Time until scan can be used again
+
 	// Input state (set by player input)
 	InputMove      rl.Vector2 // normalized movement input (x, z)
 	InputShoot     bool
@@ -113,9 +231,13 @@ type Mech struct {
 	InputDrop      bool // Attempt to drop unit
 	InputOrderNext bool // Cycle to next order
 	InputOrderPrev bool // Cycle to previous order
+	InputScan      bool // Activate area scan
+	InputBoost     bool // Jet afterburner, held
+	InputTurn      float32 // Tank-control turning, -1 (right) to 1 (left)
+	TankControls   bool // Steer with InputTurn instead of facing the movement direction
 
 	// Transport system
-	CarriedUnit   *unit.Unit // Currently carried unit (nil if not carrying)
+	CarriedUnits  []*unit.Unit // Currently carried units, in pickup order
 	SelectedOrder unit.Order // Order to assign when dropping
 	Team          unit.Team  // Which team owns this mech
 }
@@ -131,18 +253,31 @@ func New(pos rl.Vector3, cfg Config) *Mech {
 		State:         StateIdle,
 		Health:        cfg.MaxHealth,
 		MaxHealth:     cfg.MaxHealth,
-		Projectiles:   make([]Projectile, 0, 32),
+		Energy:        cfg.MaxEnergy,
+		MaxEnergy:     cfg.MaxEnergy,
+		BoostEnergy:   cfg.MaxBoostEnergy,
+		Projectiles:   projectile.NewPool(32),
 		SelectedOrder: unit.OrderAttackNearest, // Default order
 		Team:          unit.TeamPlayer,         // Default to player team
+		CurrentAmmo:   cfg.MagSize(ModeJet),
+		stowedAmmo:    cfg.MagSize(ModeRobot),
 	}
 }
 
 // Update updates the mech state for the frame
 func (m *Mech) Update(dt float32) {
-	if m.State == StateDead {
+	if m.State == StateDead || dt <= 0 {
 		return
 	}
 
+	// Energy and scan timers run regardless of mode or transformation
+	m.updateEnergy(dt)
+	m.updateScan(dt)
+	m.updateBoost(dt)
+
+	// Keep any carried unit attached beneath the mech
+	m.updateCarried()
+
 	// Update transformation
 	if m.State == StateTransforming {
 		m.updateTransformation(dt)
@@ -156,26 +291,25 @@ func (m *Mech) Update(dt float32) {
 	}
 
 	// Update movement based on mode
+	prevRotation := m.Rotation
 	if m.Mode == ModeJet {
 		m.updateJetMovement(dt)
 	} else {
 		m.updateRobotMovement(dt)
 	}
+	m.TurnRate = graphics.TrackTurnRate(m.TurnRate, normalizeAngle(m.Rotation-prevRotation), dt)
 
 	// Update shooting
 	m.updateShooting(dt)
 
-	// Update projectiles
-	m.updateProjectiles(dt)
-
 	// Update state
 	m.updateState()
 }
 
 func (m *Mech) updateJetMovement(dt float32) {
 	// Jet mode: 8-directional flight at fixed height
-	targetVelX := m.InputMove.X * m.Config.JetSpeed
-	targetVelZ := m.InputMove.Y * m.Config.JetSpeed
+	targetVelX := m.InputMove.X * m.jetSpeed()
+	targetVelZ := m.InputMove.Y * m.jetSpeed()
 
 	// Smooth acceleration
 	accel := m.Config.JetAcceleration * dt
@@ -192,10 +326,7 @@ func (m *Mech) updateJetMovement(dt float32) {
 	m.Position.Z += m.Velocity.Z * dt
 
 	// Update rotation to face movement direction
-	if m.InputMove.X != 0 || m.InputMove.Y != 0 {
-		targetRotation := float32(math.Atan2(float64(m.InputMove.X), float64(m.InputMove.Y)))
-		m.Rotation = lerpAngle(m.Rotation, targetRotation, 10.0*dt)
-	}
+	m.updateFacing(dt, 10.0)
 }
 
 func (m *Mech) updateRobotMovement(dt float32) {
@@ -223,9 +354,24 @@ func (m *Mech) updateRobotMovement(dt float32) {
 	}
 
 	// Update rotation to face movement direction
+	m.updateFacing(dt, 8.0)
+}
+
+// HorizontalSpeed returns how fast the mech is moving across the ground
+func (m *Mech) HorizontalSpeed() float32 {
+	return float32(math.Sqrt(float64(m.Velocity.X*m.Velocity.X + m.Velocity.Z*m.Velocity.Z)))
+}
+
+// updateFacing turns the mech, either steered directly with tank controls
+// or toward the direction it's moving
+func (m *Mech) updateFacing(dt, turnSpeed float32) {
+	if m.TankControls {
+		m.Rotation += m.InputTurn * m.Config.TankTurnRate * dt
+		return
+	}
 	if m.InputMove.X != 0 || m.InputMove.Y != 0 {
 		targetRotation := float32(math.Atan2(float64(m.InputMove.X), float64(m.InputMove.Y)))
-		m.Rotation = lerpAngle(m.Rotation, targetRotation, 8.0*dt)
+		m.Rotation = lerpAngle(m.Rotation, targetRotation, turnSpeed*dt)
 	}
 }
 
@@ -233,6 +379,7 @@ func (m *Mech) startTransformation() {
 	m.State = StateTransforming
 	m.TransformProgress = 0.0
 	m.Velocity = rl.Vector3{} // Stop movement during transform
+	m.swapMagazines()
 }
 
 func (m *Mech) updateTransformation(dt float32) {
@@ -257,23 +404,37 @@ func (m *Mech) updateShooting(dt float32) {
 		m.FireCooldown -= dt
 	}
 
+	// An empty magazine reloads by itself, and nothing fires meanwhile
+	if m.updateReload(dt) {
+		return
+	}
+
 	// Check if we can fire
 	if !m.InputShoot || m.FireCooldown > 0 {
 		return
 	}
 
 	// Get fire rate based on mode
-	var fireRate, damage float32
+	var fireRate, damage, weaponRange float32
+	var style projectile.Style
+	var pierce int
 	if m.Mode == ModeJet {
 		fireRate = m.Config.JetFireRate
 		damage = m.Config.JetDamage
+		weaponRange = m.Config.JetRange
+		style = m.Config.JetProjectileStyle
+		pierce = m.Config.JetPierce
 	} else {
 		fireRate = m.Config.RobotFireRate
 		damage = m.Config.RobotDamage
+		weaponRange = m.Config.RobotRange
+		style = m.Config.RobotProjectileStyle
+		pierce = m.Config.RobotPierce
 	}
 
 	// Fire projectile
 	m.FireCooldown = 1.0 / fireRate
+	m.spendAmmo()
 
 	// Calculate projectile direction (forward)
 	direction := rl.Vector3{
@@ -284,52 +445,100 @@ func (m *Mech) updateShooting(dt float32) {
 
 	// Spawn projectile slightly in front of mech
 	spawnOffset := float32(0.5)
-	proj := Projectile{
-		Position: rl.Vector3{
-			X: m.Position.X + direction.X*spawnOffset,
-			Y: m.Position.Y + 0.5,
-			Z: m.Position.Z + direction.Z*spawnOffset,
-		},
+	spawnPos := rl.Vector3{
+		X: m.Position.X + direction.X*spawnOffset,
+		Y: m.Position.Y + 0.5,
+		Z: m.Position.Z + direction.Z*spawnOffset,
+	}
+
+	// Lead the aim-assist target so moving units are harder to dodge past
+	if m.AimTarget != nil && !m.AimTarget.IsDead() {
+		lead := unit.ComputeLeadPoint(spawnPos, m.AimTarget.Position, m.AimTarget.Velocity, m.Config.ProjectileSpeed)
+		dx := lead.X - spawnPos.X
+		dz := lead.Z - spawnPos.Z
+		if dist := float32(math.Sqrt(float64(dx*dx + dz*dz))); dist > 0 {
+			direction.X = dx / dist
+			direction.Z = dz / dist
+		}
+	}
+
+	m.Projectiles.Fire(projectile.Projectile{
+		Position: spawnPos,
 		Velocity: rl.Vector3{
 			X: direction.X * m.Config.ProjectileSpeed,
 			Y: 0,
 			Z: direction.Z * m.Config.ProjectileSpeed,
 		},
-		Damage:   damage,
-		Alive:    true,
-		LifeTime: 0,
-		MaxLife:  3.0, // 3 seconds before despawn
-	}
-
-	m.Projectiles = append(m.Projectiles, proj)
+		Damage:    damage,
+		Style:     style,
+		MaxLife:   projectile.LifetimeForRange(weaponRange, m.Config.ProjectileSpeed),
+		Pierce:    pierce,
+		Team:      m.Team,
+		ShooterID: unit.MechID,
+	})
 }
 
-func (m *Mech) updateProjectiles(dt float32) {
-	for i := range m.Projectiles {
-		if !m.Projectiles[i].Alive {
-			continue
+func (m *Mech) updateCarried() {
+	below := rl.Vector3{X: m.Position.X, Y: m.Position.Y - carryOffset, Z: m.Position.Z}
+	if below.Y < 0 {
+		below.Y = 0
+	}
+
+	// Several units hang in a tighter version of the ring they'll drop in
+	for i, pos := range DropSpread(below, len(m.CarriedUnits)) {
+		u := m.CarriedUnits[i]
+		u.Position = rl.Vector3{
+			X: below.X + (pos.X-below.X)*carrySpreadScale,
+			Y: below.Y,
+			Z: below.Z + (pos.Z-below.Z)*carrySpreadScale,
 		}
+		u.Rotation = m.Rotation
+	}
+}
 
-		// Update position
-		m.Projectiles[i].Position.X += m.Projectiles[i].Velocity.X * dt
-		m.Projectiles[i].Position.Y += m.Projectiles[i].Velocity.Y * dt
-		m.Projectiles[i].Position.Z += m.Projectiles[i].Velocity.Z * dt
+// carrySpreadScale shrinks the drop ring for units hanging under the mech
+const carrySpreadScale = 0.5
 
-		// Update lifetime
-		m.Projectiles[i].LifeTime += dt
-		if m.Projectiles[i].LifeTime >= m.Projectiles[i].MaxLife {
-			m.Projectiles[i].Alive = false
+func (m *Mech) updateScan(dt float32) {
+	if m.ScanTimer > 0 {
+		m.ScanTimer -= dt
+		if m.ScanTimer < 0 {
+			m.ScanTimer = 0
 		}
 	}
-
-	// Remove dead projectiles (compact slice)
-	alive := m.Projectiles[:0]
-	for _, p := range m.Projectiles {
-		if p.Alive {
-			alive = append(alive, p)
+	if m.ScanCooldownTimer > 0 {
+		m.ScanCooldownTimer -= dt
+		if m.ScanCooldownTimer < 0 {
+			m.ScanCooldownTimer = 0
 		}
 	}
-	m.Projectiles = alive
+
+	if m.InputScan {
+		m.StartScan(m.Position)
+	}
+}
+
+// CanScan returns true if the scan ability is off cooldown and affordable
+func (m *Mech) CanScan() bool {
+	return m.ScanCooldownTimer <= 0 && m.State != StateDead && m.HasEnergyFor(AbilityScan)
+}
+
+// StartScan reveals the area around a point for the scan duration
+// Returns false if the ability is on cooldown or short of energy
+func (m *Mech) StartScan(center rl.Vector3) bool {
+	if !m.CanScan() || !m.spendEnergy(AbilityScan) {
+		return false
+	}
+
+	m.ScanCenter = center
+	m.ScanTimer = m.Config.ScanDuration
+	m.ScanCooldownTimer = m.Config.ScanCooldown
+	return true
+}
+
+// IsScanning returns true while a scan is revealing the map
+func (m *Mech) IsScanning() bool {
+	return m.ScanTimer > 0
 }
 
 func (m *Mech) updateState() {
@@ -351,17 +560,26 @@ func (m *Mech) updateState() {
 	}
 }
 
-// TakeDamage applies damage to the mech
+// TakeDamage applies damage to the mech in whole hit points
 func (m *Mech) TakeDamage(amount float32) {
+	amount = unit.HitPoints(amount)
 	m.Health -= amount
+	m.damageTaken += amount
 	if m.Health < 0 {
 		m.Health = 0
 	}
 }
 
-// Heal restores health to the mech
+// ConsumeDamage returns the damage taken since the last call and resets it
+func (m *Mech) ConsumeDamage() float32 {
+	d := m.damageTaken
+	m.damageTaken = 0
+	return d
+}
+
+// Heal restores health to the mech in whole hit points
 func (m *Mech) Heal(amount float32) {
-	m.Health += amount
+	m.Health += unit.HitPoints(amount)
 	if m.Health > m.MaxHealth {
 		m.Health = m.MaxHealth
 	}
@@ -383,54 +601,213 @@ func (m *Mech) GetForward() rl.Vector3 {
 
 // Transport methods
 
-// CanPickup returns true if the mech can pick up a unit
+// CanPickup returns true if the mech can pick up another unit
 func (m *Mech) CanPickup() bool {
-	return m.Mode == ModeJet && m.CarriedUnit == nil && m.State != StateTransforming
+	return m.Mode == ModeJet && len(m.CarriedUnits) < m.Config.MaxCarryCount && m.State != StateTransforming
 }
 
-// CanDrop returns true if the mech can drop a unit
+// CanDrop returns true if the mech can drop what it's carrying
 func (m *Mech) CanDrop() bool {
-	return m.Mode == ModeJet && m.CarriedUnit != nil && m.State != StateTransforming
+	return m.Mode == ModeJet && m.IsCarrying() && m.State != StateTransforming
 }
 
-// IsCarrying returns true if the mech is carrying a unit
+// PickAimTarget chooses the candidate closest to the mech's nose within the
+// aim-assist cone and range, or nil if none qualify
+func (m *Mech) PickAimTarget(candidates []*unit.Unit) *unit.Unit {
+	var best *unit.Unit
+	bestAngle := m.Config.AimAssistAngle
+
+	for _, u := range candidates {
+		if u.IsDead() || u.IsCarried() {
+			continue
+		}
+		dx := u.Position.X - m.Position.X
+		dz := u.Position.Z - m.Position.Z
+		if dx*dx+dz*dz > m.Config.AimAssistRange*m.Config.AimAssistRange {
+			continue
+		}
+
+		angle := float32(math.Atan2(float64(dx), float64(dz)))
+		off := float32(math.Abs(float64(normalizeAngle(angle - m.Rotation))))
+		if off <= bestAngle {
+			best = u
+			bestAngle = off
+		}
+	}
+	return best
+}
+
+// IsCarrying returns true if the mech is carrying any units
 func (m *Mech) IsCarrying() bool {
-	return m.CarriedUnit != nil
+	return len(m.CarriedUnits) > 0
 }
 
-// PickupUnit picks up a unit (called externally when pickup is valid)
-func (m *Mech) PickupUnit(u *unit.Unit) bool {
-	if !m.CanPickup() || u == nil {
-		return false
+// CarriedWeight returns the weight of what the mech is carrying
+func (m *Mech) CarriedWeight() float32 {
+	var weight float32
+	for _, u := range m.CarriedUnits {
+		weight += u.Config.Weight
 	}
-	if u.Team != m.Team {
-		return false // Can only pick up friendly units
+	return weight
+}
+
+// loadFactor returns the share of jet speed left with the current load
+func (m *Mech) loadFactor() float32 {
+	if m.Config.MaxCarryWeight <= 0 {
+		return 1
 	}
+	load := m.CarriedWeight() / m.Config.MaxCarryWeight
+	if load > 1 {
+		load = 1
+	}
+	return 1 - m.Config.CarrySlowdown*load
+}
 
-	m.CarriedUnit = u
-	u.PickUp()
-	return true
+// CanPickupUnit returns why the mech can't pick up a unit, or nil if it can
+func (m *Mech) CanPickupUnit(u *unit.Unit) error {
+	switch {
+	case len(m.CarriedUnits) >= m.Config.MaxCarryCount:
+		return ErrCarryFull
+	case !m.CanPickup():
+		return ErrCannotCarry
+	case u == nil:
+		return ErrNoUnit
+	case u.IsCarried():
+		return ErrUnitCarried // Possibly by another mech
+	case u.IsDead():
+		return ErrUnitDead
+	case u.IsSpawning():
+		return ErrUnitSpawning
+	case u.Team != m.Team:
+		return ErrUnitNotFriend // Can only pick up friendly units
+	case m.CarriedWeight()+u.Config.Weight > m.Config.MaxCarryWeight:
+		return ErrTooHeavy
+	}
+	return nil
 }
 
-// DropUnit drops the carried unit at the mech's current position
-// Returns the dropped unit (or nil if not carrying)
-func (m *Mech) DropUnit() *unit.Unit {
-	if !m.CanDrop() {
-		return nil
+// PickupUnit adds a unit to the load, failing with the reason it isn't
+// eligible. Whatever the unit was doing is paused until it's dropped with a
+// new order.
+func (m *Mech) PickupUnit(u *unit.Unit) error {
+	if err := m.CanPickupUnit(u); err != nil {
+		return err
 	}
 
-	u := m.CarriedUnit
-	m.CarriedUnit = nil
+	m.CarriedUnits = append(m.CarriedUnits, u)
+	u.PickUp()
+	m.updateCarried()
+	return nil
+}
 
+// DropUnit drops the carried units around the mech's current position
+// Returns the dropped units (nil if not carrying)
+func (m *Mech) DropUnit() []*unit.Unit {
 	// Drop position is below the mech (on the ground)
 	dropPos := rl.Vector3{
 		X: m.Position.X,
 		Y: 0,
 		Z: m.Position.Z,
 	}
+	return m.DropUnitAt(dropPos)
+}
+
+// DropUnitAt drops the carried units around a ground position, spread out
+// so they don't land on each other, each with the selected order
+// Returns the dropped units (nil if not carrying)
+func (m *Mech) DropUnitAt(dropPos rl.Vector3) []*unit.Unit {
+	if !m.CanDrop() {
+		return nil
+	}
+
+	dropped := m.CarriedUnits
+	m.CarriedUnits = nil
+
+	for i, pos := range DropSpread(dropPos, len(dropped)) {
+		u := dropped[i]
+		u.Drop(pos, m.SelectedOrder)
+		if m.SelectedOrder == unit.OrderDefendPosition {
+			u.SetGuardFacing(m.Rotation) // Guard the way we were flying
+		}
+	}
+	return dropped
+}
 
-	u.Drop(dropPos, m.SelectedOrder)
-	return u
+// DropSpread returns where n dropped units land around a point: a single
+// unit right on it, more evenly around a ring of radius dropSpread
+func DropSpread(center rl.Vector3, n int) []rl.Vector3 {
+	if n == 1 {
+		return []rl.Vector3{center}
+	}
+	spots := make([]rl.Vector3, n)
+	for i := range spots {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		spots[i] = rl.Vector3{
+			X: center.X + dropSpread*float32(math.Sin(angle)),
+			Y: center.Y,
+			Z: center.Z + dropSpread*float32(math.Cos(angle)),
+		}
+	}
+	return spots
+}
+
+// DropTarget returns where a drop would land given the terrain below and
+// the units already on the ground. If the spots under the mech can't hold
+// the carried units or are crowded, the target is moved to the nearest free
+// tile, searching wider if needed, and relocated is true. If no tile in
+// reach fits, ok is false and the units must stay aboard.
+func (m *Mech) DropTarget(tm *tilemap.TileMap, units []*unit.Unit) (target rl.Vector3, relocated, ok bool) {
+	below := rl.Vector3{X: m.Position.X, Y: 0, Z: m.Position.Z}
+
+	fits := func(x, z float32, _ tilemap.TerrainType) bool {
+		for _, spot := range DropSpread(rl.Vector3{X: x, Z: z}, len(m.CarriedUnits)) {
+			if !m.canLandOn(tm.GetTerrainAt(spot.X, spot.Z)) || isCrowded(spot.X, spot.Z, units) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for radius := dropSearchRadius; radius <= dropSearchRadius*2; radius += dropSearchRadius {
+		if x, z, found := tm.NearestFree(below.X, below.Z, radius, fits); found {
+			target = rl.Vector3{X: x, Y: 0, Z: z}
+			return target, x != below.X || z != below.Z, true
+		}
+	}
+	return below, true, false
+}
+
+// canLandOn returns true if every carried unit can be set down on a terrain
+func (m *Mech) canLandOn(terrain tilemap.TerrainType) bool {
+	if terrain.IsPassable() {
+		return true
+	}
+	// Boats are happy to be dropped straight into water
+	if terrain != tilemap.TerrainWater || !m.IsCarrying() {
+		return false
+	}
+	for _, u := range m.CarriedUnits {
+		if !u.Config.CanTraverseWater {
+			return false
+		}
+	}
+	return true
+}
+
+// isCrowded returns true if a grounded unit stands within dropClearance of
+// a point
+func isCrowded(x, z float32, units []*unit.Unit) bool {
+	for _, u := range units {
+		if u.IsDead() || u.IsCarried() {
+			continue
+		}
+		dx := u.Position.X - x
+		dz := u.Position.Z - z
+		if dx*dx+dz*dz < dropClearance*dropClearance {
+			return true
+		}
+	}
+	return false
 }
 
 // CycleOrderNext cycles to the next order type
@@ -498,3 +875,13 @@ func lerpAngle(a, b, t float32) float32 {
 
 	return a + diff*t
 }
+
+func normalizeAngle(a float32) float32 {
+	for a > math.Pi {
+		a -= 2 * math.Pi
+	}
+	for a < -math.Pi {
+		a += 2 * math.Pi
+	}
+	return a
+}
@@ -0,0 +1,161 @@
+package mech
+
+import (
+	"errors"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// flyFor holds the stick forward for a number of seconds and returns the
+// jet's speed at the end
+func flyFor(m *Mech, seconds float32) float32 {
+	m.InputMove = rl.Vector2{X: 1}
+	for t := float32(0); t < seconds; t += 0.1 {
+		m.Update(0.1)
+	}
+	return m.HorizontalSpeed()
+}
+
+func TestOverweightUnitRefused(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{}, cfg)
+	infantry := unit.New(1, unit.TypeInfantry, unit.TeamPlayer, rl.Vector3{})
+	tank := unit.New(2, unit.TypeTank, unit.TeamPlayer, rl.Vector3{})
+
+	if err := m.PickupUnit(infantry); err != nil {
+		t.Fatalf("picking up infantry: %v", err)
+	}
+	if err := m.PickupUnit(tank); !errors.Is(err, ErrTooHeavy) {
+		t.Fatalf("tank on top of infantry: got %v, want ErrTooHeavy", err)
+	}
+	if tank.IsCarried() || len(m.CarriedUnits) != 1 {
+		t.Error("refused tank was picked up anyway")
+	}
+
+	// Alone, the tank is within the limit
+	m.DropUnit()
+	if err := m.PickupUnit(tank); err != nil {
+		t.Errorf("picking up a lone tank: %v", err)
+	}
+}
+
+func TestCarryingSlowsJet(t *testing.T) {
+	cfg := DefaultConfig()
+	empty := New(rl.Vector3{}, cfg)
+	loaded := New(rl.Vector3{}, cfg)
+	tank := unit.New(1, unit.TypeTank, unit.TeamPlayer, rl.Vector3{})
+	if err := loaded.PickupUnit(tank); err != nil {
+		t.Fatalf("picking up a tank: %v", err)
+	}
+
+	free, slowed := flyFor(empty, 2), flyFor(loaded, 2)
+	want := cfg.JetSpeed * (1 - cfg.CarrySlowdown*tank.Config.Weight/cfg.MaxCarryWeight)
+	if slowed >= free || slowed > want+0.01 {
+		t.Errorf("loaded jet flies at %v, empty at %v, want at most %v loaded", slowed, free, want)
+	}
+}
+
+func TestCarryUpToCap(t *testing.T) {
+	cfg := DefaultConfig()
+	m := New(rl.Vector3{X: 5, Y: cfg.FlightHeight, Z: 5}, cfg)
+	m.SelectedOrder = unit.OrderDefendPosition
+
+	for i := 0; i < cfg.MaxCarryCount; i++ {
+		if err := m.PickupUnit(unit.New(uint32(i+1), unit.TypeInfantry, unit.TeamPlayer, rl.Vector3{})); err != nil {
+			t.Fatalf("pickup %d of %d: %v", i+1, cfg.MaxCarryCount, err)
+		}
+	}
+	extra := unit.New(99, unit.TypeInfantry, unit.TeamPlayer, rl.Vector3{})
+	if err := m.PickupUnit(extra); !errors.Is(err, ErrCarryFull) {
+		t.Fatalf("pickup past the cap: got %v, want ErrCarryFull", err)
+	}
+	if extra.IsCarried() || len(m.CarriedUnits) != cfg.MaxCarryCount {
+		t.Fatal("unit past the cap was picked up anyway")
+	}
+
+	// Everyone lands apart from each other, around the spot below, with
+	// the selected order
+	dropped := m.DropUnit()
+	if len(dropped) != cfg.MaxCarryCount || m.IsCarrying() {
+		t.Fatalf("dropped %d of %d units", len(dropped), cfg.MaxCarryCount)
+	}
+	for i, u := range dropped {
+		if u.IsCarried() || u.Order != unit.OrderDefendPosition {
+			t.Errorf("unit %d still carried or not given the selected order", u.ID)
+		}
+		if d := u.DistanceToPoint(rl.Vector3{X: 5, Z: 5}); d > dropSpread+0.01 {
+			t.Errorf("unit %d landed %v from the drop point", u.ID, d)
+		}
+		for _, other := range dropped[i+1:] {
+			if d := u.DistanceTo(other); d < dropClearance {
+				t.Errorf("units %d and %d landed %v apart", u.ID, other.ID, d)
+			}
+		}
+	}
+}
+
+// carrying returns a jet over a point holding n infantry
+func carrying(n int, x, z float32) *Mech {
+	m := New(rl.Vector3{X: x, Y: DefaultConfig().FlightHeight, Z: z}, DefaultConfig())
+	for i := 0; i < n; i++ {
+		m.PickupUnit(unit.New(uint32(100+i), unit.TypeInfantry, unit.TeamPlayer, rl.Vector3{}))
+	}
+	return m
+}
+
+func TestDropAvoidsTerrainAndUnits(t *testing.T) {
+	tm := tilemap.NewTileMap(30, 30)
+	tm.FillRect(13, 13, 17, 17, tilemap.TerrainMountain)
+	tm.FillRect(10, 13, 12, 17, tilemap.TerrainWater)
+	ground := []*unit.Unit{
+		unit.New(1, unit.TypeInfantry, unit.TeamPlayer, rl.Vector3{X: 15.5, Z: 11.5}),
+		unit.New(2, unit.TypeTank, unit.TeamPlayer, rl.Vector3{X: 15.5, Z: 19.5}),
+	}
+	m := carrying(3, 15.5, 15.5)
+
+	target, relocated, ok := m.DropTarget(tm, ground)
+	if !ok || !relocated {
+		t.Fatalf("drop over a mountain: ok %v, relocated %v, want moved somewhere free", ok, relocated)
+	}
+	dropped := m.DropUnitAt(target)
+	for i, u := range dropped {
+		if terrain := tm.GetTerrainAt(u.Position.X, u.Position.Z); !terrain.IsPassable() {
+			t.Errorf("unit %d landed on %v", u.ID, terrain)
+		}
+		for _, other := range append(ground, dropped[i+1:]...) {
+			if d := u.DistanceTo(other); d < dropClearance {
+				t.Errorf("unit %d landed %v from unit %d", u.ID, d, other.ID)
+			}
+		}
+	}
+}
+
+func TestNowhereToDrop(t *testing.T) {
+	tm := tilemap.NewTileMap(30, 30)
+	tm.FillRect(0, 0, 29, 29, tilemap.TerrainWater)
+	m := carrying(2, 15.5, 15.5)
+
+	if _, _, ok := m.DropTarget(tm, nil); ok {
+		t.Fatal("found somewhere to drop infantry in the middle of a lake")
+	}
+}
+
+func TestLethalDamageLeavesMechAtZero(t *testing.T) {
+	m := New(rl.NewVector3(0, 0, 0), DefaultConfig())
+	for i := 0; i < 10000 && !m.IsDead(); i++ {
+		m.TakeDamage(0.7)
+	}
+	if m.Health != 0 || !m.IsDead() {
+		t.Errorf("after lethal chip damage: %v health, dead %v", m.Health, m.IsDead())
+	}
+
+	m = New(rl.NewVector3(0, 0, 0), DefaultConfig())
+	m.TakeDamage(m.Health + 0.4)
+	if m.Health != 0 || !m.IsDead() {
+		t.Errorf("after lethal hit: %v health, dead %v", m.Health, m.IsDead())
+	}
+}
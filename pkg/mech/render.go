@@ -5,18 +5,34 @@ import (
 	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+	"github.com/chazu/herzog-drei/pkg/unit"
 )
 
 // Renderer handles mech and projectile rendering
-type Renderer struct{}
+type Renderer struct {
+	Graphics graphics.Settings
+}
 
 // NewRenderer creates a new mech renderer
 func NewRenderer() *Renderer {
-	return &Renderer{}
+	return &Renderer{Graphics: graphics.DefaultSettings()}
 }
 
+// Mech shadow footprints
+const (
+	jetShadowRadius   = 0.8
+	robotShadowRadius = 0.5
+)
+
 // Draw renders the mech using placeholder geometry
 func (r *Renderer) Draw(m *Mech) {
+	if !m.IsDead() {
+		r.drawShadow(m)
+	}
+
 	// Draw based on mode and transformation state
 	if m.State == StateTransforming {
 		r.drawTransforming(m)
@@ -26,18 +42,27 @@ func (r *Renderer) Draw(m *Mech) {
 		r.drawRobotMode(m)
 	}
 
-	// Draw projectiles
-	r.drawProjectiles(m)
+	// Draw cables to a carried unit
+	if m.IsCarrying() {
+		r.drawCarryCables(m)
+	}
+
+	// Draw active scan area
+	if m.IsScanning() {
+		r.drawScan(m)
+	}
 }
 
 func (r *Renderer) drawJetMode(m *Mech) {
 	pos := m.Position
 	rot := m.Rotation * 180.0 / math.Pi // Convert to degrees
+	bank := graphics.Degrees(graphics.BankAngle(m.TurnRate, m.HorizontalSpeed(), graphics.MaxBank))
 
-	// Jet body (elongated box)
+	// Jet body (elongated box), rolled into turns
 	rl.PushMatrix()
 	rl.Translatef(pos.X, pos.Y, pos.Z)
 	rl.Rotatef(rot, 0, 1, 0)
+	rl.Rotatef(bank, 0, 0, 1)
 
 	// Main fuselage
 	rl.DrawCube(rl.NewVector3(0, 0, 0), 0.4, 0.3, 1.2, rl.Blue)
@@ -55,9 +80,6 @@ func (r *Renderer) drawJetMode(m *Mech) {
 	rl.DrawCube(rl.NewVector3(0, 0.2, 0.3), 0.25, 0.15, 0.3, rl.SkyBlue)
 
 	rl.PopMatrix()
-
-	// Draw shadow on ground
-	r.drawShadow(pos)
 }
 
 func (r *Renderer) drawRobotMode(m *Mech) {
@@ -135,55 +157,86 @@ func (r *Renderer) drawTransforming(m *Mech) {
 	rl.DrawCubeWires(rl.NewVector3(0, height/2, 0), width, height, length, rl.DarkBlue)
 
 	rl.PopMatrix()
-
-	// Draw shadow
-	r.drawShadow(pos)
 }
 
-func (r *Renderer) drawShadow(pos rl.Vector3) {
-	// Simple circular shadow on ground
-	shadowY := float32(0.01) // Slightly above ground to avoid z-fighting
-	shadowSize := float32(0.8)
-
-	rl.DrawCylinder(
-		rl.NewVector3(pos.X, shadowY, pos.Z),
-		shadowSize, shadowSize, 0.01,
-		16,
-		rl.Color{R: 0, G: 0, B: 0, A: 64},
-	)
+// drawShadow casts the mech's shadow, shrinking and sliding away as it
+// climbs
+func (r *Renderer) drawShadow(m *Mech) {
+	if !r.Graphics.Shadows {
+		return
+	}
+	radius := float32(robotShadowRadius)
+	if m.Mode == ModeJet {
+		radius = jetShadowRadius
+	}
+	graphics.DrawShadow(graphics.ShadowFor(m.Position, radius))
 }
 
-func (r *Renderer) drawProjectiles(m *Mech) {
-	for _, p := range m.Projectiles {
-		if !p.Alive {
-			continue
+func (r *Renderer) drawCarryCables(m *Mech) {
+	top := m.Position
+	for _, u := range m.CarriedUnits {
+		bottom := rl.Vector3{X: u.Position.X, Y: u.Position.Y + 0.3, Z: u.Position.Z}
+
+		// A pair of cables hanging from either side of the fuselage
+		for _, side := range []float32{-0.15, 0.15} {
+			offX := float32(math.Cos(float64(m.Rotation))) * side
+			offZ := -float32(math.Sin(float64(m.Rotation))) * side
+			rl.DrawLine3D(
+				rl.Vector3{X: top.X + offX, Y: top.Y, Z: top.Z + offZ},
+				rl.Vector3{X: bottom.X + offX, Y: bottom.Y, Z: bottom.Z + offZ},
+				rl.DarkGray,
+			)
 		}
+	}
+}
 
-		// Different colors based on damage (jet vs robot)
-		var color rl.Color
-		if p.Damage <= 15 {
-			color = rl.Yellow // Jet mode projectiles
-		} else {
-			color = rl.Orange // Robot mode projectiles
-		}
+// DrawDropPreview draws a reticle on the ground where a drop would land.
+// The reticle is yellow when the spot below is impassable or crowded and
+// the drop would be moved to the nearest free tile, and red under the mech
+// when there's nowhere near to drop at all.
+func (r *Renderer) DrawDropPreview(m *Mech, tm *tilemap.TileMap, units []*unit.Unit) {
+	if !m.IsCarrying() || m.Mode != ModeJet {
+		return
+	}
 
-		// Draw projectile as a small sphere
-		rl.DrawSphere(p.Position, 0.1, color)
-
-		// Draw a small trail
-		trailLen := float32(0.3)
-		speed := float32(math.Sqrt(float64(p.Velocity.X*p.Velocity.X + p.Velocity.Z*p.Velocity.Z)))
-		if speed > 0 {
-			trailEnd := rl.Vector3{
-				X: p.Position.X - p.Velocity.X/speed*trailLen,
-				Y: p.Position.Y,
-				Z: p.Position.Z - p.Velocity.Z/speed*trailLen,
-			}
-			rl.DrawLine3D(p.Position, trailEnd, color)
-		}
+	target, relocated, ok := m.DropTarget(tm, units)
+	color := rl.Green
+	switch {
+	case !ok:
+		color = rl.Red
+		relocated = false // Nothing to point to
+	case relocated:
+		color = rl.Yellow
+	}
+
+	groundY := tm.GetHeightAt(target.X, target.Z) + 0.05
+	if groundY < 0.05 {
+		groundY = 0.05
+	}
+	center := rl.Vector3{X: target.X, Y: groundY, Z: target.Z}
+	axis := rl.Vector3{X: 1, Y: 0, Z: 0}
+
+	rl.DrawCircle3D(center, 0.6, axis, 90, color)
+	rl.DrawCircle3D(center, 0.3, axis, 90, color)
+
+	// Show where the drop was pushed from
+	if relocated {
+		below := rl.Vector3{X: m.Position.X, Y: groundY, Z: m.Position.Z}
+		rl.DrawLine3D(below, center, color)
 	}
 }
 
+func (r *Renderer) drawScan(m *Mech) {
+	// Pulse the ring outward over the scan duration
+	t := 1.0 - m.ScanTimer/m.Config.ScanDuration
+	alpha := uint8(200 * (1.0 - t))
+	center := rl.Vector3{X: m.ScanCenter.X, Y: 0.1, Z: m.ScanCenter.Z}
+	axis := rl.Vector3{X: 1, Y: 0, Z: 0}
+
+	rl.DrawCircle3D(center, m.Config.ScanRadius, axis, 90, rl.Color{R: 0, G: 255, B: 180, A: 200})
+	rl.DrawCircle3D(center, m.Config.ScanRadius*t, axis, 90, rl.Color{R: 0, G: 255, B: 180, A: alpha})
+}
+
 // DrawUI renders mech-related UI elements
 func (r *Renderer) DrawUI(m *Mech, screenWidth, screenHeight int) {
 	// Health bar
@@ -216,6 +269,18 @@ func (r *Renderer) DrawUI(m *Mech, screenWidth, screenHeight int) {
 	healthText := fmt.Sprintf("HP: %.0f/%.0f", m.Health, m.MaxHealth)
 	rl.DrawText(healthText, int32(barX), int32(barY-20), 15, rl.White)
 
+	// Ammo counter, right of the health text
+	ammoX := int32(barX + barWidth - 90)
+	if m.IsReloading() {
+		rl.DrawText(fmt.Sprintf("RELOAD %.1f", m.ReloadTimer), ammoX, int32(barY-20), 15, rl.Orange)
+	} else if mag := m.Config.MagSize(m.Mode); mag > 0 {
+		ammoColor := rl.White
+		if m.CurrentAmmo*4 <= mag {
+			ammoColor = rl.Yellow // Last quarter
+		}
+		rl.DrawText(fmt.Sprintf("AMMO %d/%d", m.CurrentAmmo, mag), ammoX, int32(barY-20), 15, ammoColor)
+	}
+
 	// Mode indicator
 	var modeText string
 	var modeColor rl.Color
@@ -234,8 +299,38 @@ func (r *Renderer) DrawUI(m *Mech, screenWidth, screenHeight int) {
 
 	rl.DrawText(modeText, int32(barX), int32(barY-40), 20, modeColor)
 
+	// Energy bar, thin strip under the health bar
+	energyY := int32(barY + barHeight + 2)
+	rl.DrawRectangle(int32(barX), energyY, int32(barWidth), 6, rl.DarkGray)
+	if m.MaxEnergy > 0 {
+		rl.DrawRectangle(int32(barX), energyY, int32(barWidth*m.Energy/m.MaxEnergy), 6, rl.SkyBlue)
+	}
+
+	// Boost meter under that, greyed out while recharging from empty
+	boostY := energyY + 8
+	rl.DrawRectangle(int32(barX), boostY, int32(barWidth), 6, rl.DarkGray)
+	if m.Config.MaxBoostEnergy > 0 {
+		boostColor := rl.Orange
+		if !m.CanBoost() {
+			boostColor = rl.Gray
+		}
+		rl.DrawRectangle(int32(barX), boostY, int32(barWidth*m.BoostEnergy/m.Config.MaxBoostEnergy), 6, boostColor)
+	}
+
+	// Scan ability status
+	scanX := int32(barX + barWidth + 10)
+	if m.IsScanning() {
+		rl.DrawText(fmt.Sprintf("SCANNING %.1f", m.ScanTimer), scanX, int32(barY), 15, rl.Green)
+	} else if m.CanScan() {
+		rl.DrawText("SCAN READY (V)", scanX, int32(barY), 15, rl.Green)
+	} else if m.ScanCooldownTimer > 0 {
+		rl.DrawText(fmt.Sprintf("SCAN %.0fs", m.ScanCooldownTimer), scanX, int32(barY), 15, rl.Gray)
+	} else {
+		rl.DrawText("SCAN: LOW ENERGY", scanX, int32(barY), 15, rl.Gray)
+	}
+
 	// Controls hint
-	rl.DrawText("WASD: Move | SPACE: Shoot | T: Transform", 10, int32(screenHeight)-20, 15, rl.Gray)
+	rl.DrawText("WASD: Move | SPACE: Shoot | CTRL: Boost | T: Transform", 10, int32(screenHeight)-20, 15, rl.Gray)
 }
 
 func lerp(a, b, t float32) float32 {
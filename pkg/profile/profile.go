@@ -0,0 +1,98 @@
+package profile
+
+import (
+	"time"
+)
+
+// Names of the timed phases
+const (
+	SectionUnits       = "units"
+	SectionAI          = "ai"
+	SectionCombat      = "combat"
+	SectionPathfinding = "pathfinding"
+	SectionBases       = "bases"
+	SectionRender      = "render"
+)
+
+// Timing is the time spent in a section over one frame
+type Timing struct {
+	Name string
+	Ms   float64
+}
+
+// Profiler accumulates time spent in named sections each frame.
+// A nil or disabled profiler costs nothing beyond the Enabled check.
+type Profiler struct {
+	Enabled bool
+
+	current map[string]time.Duration
+	last    []Timing // Completed previous frame, in first-seen order
+	order   []string // Section names in the order they were first timed
+}
+
+// New creates a disabled profiler
+func New() *Profiler {
+	return &Profiler{current: make(map[string]time.Duration)}
+}
+
+// noop is returned by Start when timing is off
+func noop() {}
+
+// Start begins timing a section and returns the function that stops it,
+// typically deferred: defer p.Start(profile.SectionAI)()
+func (p *Profiler) Start(name string) func() {
+	if p == nil || !p.Enabled {
+		return noop
+	}
+	start := time.Now()
+	return func() {
+		p.Add(name, time.Since(start))
+	}
+}
+
+// Add records time spent in a section this frame. Sections timed more
+// than once in a frame add up.
+func (p *Profiler) Add(name string, d time.Duration) {
+	if p == nil || !p.Enabled {
+		return
+	}
+	if _, seen := p.current[name]; !seen {
+		p.order = append(p.order, name)
+	}
+	p.current[name] += d
+}
+
+// BeginFrame finishes the previous frame, making its timings available
+// from Frame, and starts accumulating a new one
+func (p *Profiler) BeginFrame() {
+	if p == nil {
+		return
+	}
+	p.last = p.last[:0]
+	if !p.Enabled {
+		return
+	}
+	for _, name := range p.order {
+		p.last = append(p.last, Timing{Name: name, Ms: float64(p.current[name]) / float64(time.Millisecond)})
+		p.current[name] = 0
+	}
+}
+
+// Frame returns the section timings of the last completed frame
+func (p *Profiler) Frame() []Timing {
+	if p == nil {
+		return nil
+	}
+	return p.last
+}
+
+// Ms returns milliseconds spent in a section during the last completed
+// frame, 0 if it wasn't timed
+func (p *Profiler) Ms(name string) float64 {
+	for _, t := range p.Frame() {
+		if t.Name == name {
+			return t.Ms
+		}
+	}
+	return 0
+}
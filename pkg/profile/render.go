@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// frameBudgetMs is a full bar, one frame at 60 FPS
+const frameBudgetMs = 1000.0 / 60.0
+
+// Draw renders the last frame's timings as a small bar graph with its top
+// left corner at x, y. Draws nothing while the profiler is disabled.
+func Draw(p *Profiler, x, y int32) {
+	if p == nil || !p.Enabled {
+		return
+	}
+
+	const (
+		rowHeight = 14
+		labelW    = 80
+		barW      = 120
+	)
+	timings := p.Frame()
+	rl.DrawRectangle(x-4, y-4, labelW+barW+70, int32(len(timings))*rowHeight+8, rl.Fade(rl.Black, 0.6))
+
+	for i, t := range timings {
+		rowY := y + int32(i)*rowHeight
+		rl.DrawText(t.Name, x, rowY, 10, rl.White)
+
+		// Bars fill up to one frame's budget, red once a phase eats half of it
+		frac := float32(t.Ms / frameBudgetMs)
+		if frac > 1 {
+			frac = 1
+		}
+		color := rl.Green
+		if t.Ms > frameBudgetMs/2 {
+			color = rl.Red
+		} else if t.Ms > frameBudgetMs/4 {
+			color = rl.Yellow
+		}
+		rl.DrawRectangle(x+labelW, rowY+1, int32(barW*frac), rowHeight-4, color)
+		rl.DrawRectangleLines(x+labelW, rowY+1, barW, rowHeight-4, rl.Gray)
+		rl.DrawText(fmt.Sprintf("%.2fms", t.Ms), x+labelW+barW+6, rowY, 10, rl.White)
+	}
+}
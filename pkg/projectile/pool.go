@@ -0,0 +1,113 @@
+package projectile
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// Impact is where a projectile that hit nothing came down
+type Impact struct {
+	Position rl.Vector3 // On the ground
+	Team     unit.Team  // Side that fired it
+}
+
+// Pool owns every projectile in flight, whoever fired it. Spent projectiles
+// are compacted out but their storage is kept and reused by later shots.
+type Pool struct {
+	projectiles []Projectile
+	impacts     []Impact // Projectiles that expired during the last Update
+}
+
+// NewPool creates an empty pool with room for capacity projectiles before
+// it has to grow
+func NewPool(capacity int) *Pool {
+	return &Pool{projectiles: make([]Projectile, 0, capacity)}
+}
+
+// Fire adds a projectile to the pool, live from its current position
+func (p *Pool) Fire(proj Projectile) {
+	proj.Alive = true
+	proj.Previous = proj.Position
+
+	// Reuse the hit list of the spent projectile whose slot this takes
+	n := len(p.projectiles)
+	if n < cap(p.projectiles) {
+		proj.hitIDs = p.projectiles[:n+1][n].hitIDs[:0]
+	} else {
+		proj.hitIDs = nil
+	}
+	p.projectiles = append(p.projectiles, proj)
+}
+
+// Update moves every projectile and retires those that expire or reach the
+// ground, recording where they came down as impacts. Projectiles spent by
+// hits since the last Update are dropped too.
+func (p *Pool) Update(dt float32) {
+	p.impacts = p.impacts[:0]
+	if dt <= 0 {
+		return
+	}
+
+	for i := range p.projectiles {
+		proj := &p.projectiles[i]
+		if !proj.Alive {
+			continue
+		}
+
+		proj.Previous = proj.Position
+		proj.Position.X += proj.Velocity.X * dt
+		proj.Position.Y += proj.Velocity.Y * dt
+		proj.Position.Z += proj.Velocity.Z * dt
+
+		proj.LifeTime += dt
+		if proj.LifeTime >= proj.MaxLife || proj.Position.Y <= 0 {
+			// Misses come down where they are instead of vanishing mid-air
+			proj.Alive = false
+			p.impacts = append(p.impacts, Impact{
+				Position: rl.Vector3{X: proj.Position.X, Y: 0, Z: proj.Position.Z},
+				Team:     proj.Team,
+			})
+		}
+	}
+
+	p.compact()
+}
+
+// compact moves live projectiles to the front, swapping spent ones behind
+// them so their storage can be reused
+func (p *Pool) compact() {
+	alive := 0
+	for i := range p.projectiles {
+		if p.projectiles[i].Alive {
+			p.projectiles[alive], p.projectiles[i] = p.projectiles[i], p.projectiles[alive]
+			alive++
+		}
+	}
+	p.projectiles = p.projectiles[:alive]
+}
+
+// Active returns the projectiles in flight. Index into it to update one in
+// place, for example to register a hit.
+func (p *Pool) Active() []Projectile {
+	return p.projectiles
+}
+
+// Impacts returns where projectiles came down during the last Update
+func (p *Pool) Impacts() []Impact {
+	return p.impacts
+}
+
+// Count returns how many projectiles are in flight
+func (p *Pool) Count() int {
+	return len(p.projectiles)
+}
+
+// Clear removes every projectile
+func (p *Pool) Clear() {
+	for i := range p.projectiles {
+		p.projectiles[i].Alive = false
+	}
+	p.projectiles = p.projectiles[:0]
+	p.impacts = p.impacts[:0]
+}
@@ -0,0 +1,107 @@
+package projectile
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// TrailKind is how a projectile's trail is drawn
+type TrailKind int
+
+const (
+	TrailTracer TrailKind = iota // Thin bright line
+	TrailSmoke                   // Fading puffs, for missiles
+)
+
+// Style describes how a projectile looks
+type Style struct {
+	Color       rl.Color
+	Size        float32 // Radius of the projectile head
+	TrailLength float32
+	Trail       TrailKind
+}
+
+// Weapon projectile styles
+var (
+	TracerStyle  = Style{Color: rl.Yellow, Size: 0.1, TrailLength: 0.3, Trail: TrailTracer}
+	CannonStyle  = Style{Color: rl.Orange, Size: 0.14, TrailLength: 0.5, Trail: TrailTracer}
+	MissileStyle = Style{Color: rl.White, Size: 0.12, TrailLength: 1.2, Trail: TrailSmoke}
+)
+
+// Projectile is a bullet or missile in flight. It belongs to the pool, not
+// to whatever fired it, so it flies on if the shooter dies.
+type Projectile struct {
+	Position rl.Vector3
+	Previous rl.Vector3 // Position before the last move, for swept hits
+	Velocity rl.Vector3
+	Damage   float32
+	Style    Style // Set from the firing weapon
+	Alive    bool
+	LifeTime float32
+	MaxLife  float32
+	Pierce   int // Targets it can still pass through after the next hit
+
+	// Who fired it
+	Team        unit.Team
+	ShooterID   uint32        // Unit ID, or unit.MechID for the mech
+	ShooterType unit.UnitType // Type of the unit that fired it, unused for the mech
+
+	hitIDs []uint32 // Targets already hit, so none is hit twice
+}
+
+// HasHit returns true if the projectile already hit a target
+func (p *Projectile) HasHit(id uint32) bool {
+	for _, hit := range p.hitIDs {
+		if hit == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterHit records a hit on a target, spending pierce if any is left.
+// Returns true if the projectile keeps flying.
+func (p *Projectile) RegisterHit(id uint32) bool {
+	p.hitIDs = append(p.hitIDs, id)
+	if p.Pierce > 0 {
+		p.Pierce--
+		return true
+	}
+	p.Alive = false
+	return false
+}
+
+// Sweeps returns true if the projectile passed within radius of a point
+// during its last move, so fast shots can't skip through small targets
+// between frames
+func (p *Projectile) Sweeps(center rl.Vector3, radius float32) bool {
+	seg := rl.Vector3Subtract(p.Position, p.Previous)
+	toCenter := rl.Vector3Subtract(center, p.Previous)
+
+	// Closest point on the segment to the center
+	t := float32(0)
+	if lenSq := dot(seg, seg); lenSq > 0 {
+		t = dot(toCenter, seg) / lenSq
+		if t < 0 {
+			t = 0
+		} else if t > 1 {
+			t = 1
+		}
+	}
+	closest := rl.Vector3Add(p.Previous, rl.Vector3Scale(seg, t))
+	return rl.Vector3Distance(closest, center) <= radius
+}
+
+// LifetimeForRange converts a weapon's range in world units to how long its
+// projectile lives at the given speed
+func LifetimeForRange(weaponRange, speed float32) float32 {
+	if speed <= 0 {
+		return 0
+	}
+	return weaponRange / speed
+}
+
+func dot(a, b rl.Vector3) float32 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
@@ -0,0 +1,86 @@
+package projectile
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// smokePuffs is how many puffs a missile trail asks for at full quality
+const smokePuffs = 5
+
+// Renderer draws projectiles in flight
+type Renderer struct {
+	Graphics graphics.Settings
+
+	// Visibility hides enemy projectiles the player can't see (nil = show all)
+	Visibility graphics.Visibility
+}
+
+// NewRenderer creates a new projectile renderer
+func NewRenderer() *Renderer {
+	return &Renderer{Graphics: graphics.DefaultSettings()}
+}
+
+// Draw renders every projectile in a pool
+func (r *Renderer) Draw(pool *Pool) {
+	for _, p := range pool.Active() {
+		if r.Shows(p) {
+			r.drawProjectile(p)
+		}
+	}
+}
+
+// Shows returns true if a projectile should be drawn: it's the player's or
+// somewhere the player can see
+func (r *Renderer) Shows(p Projectile) bool {
+	if !p.Alive {
+		return false
+	}
+	return p.Team == unit.TeamPlayer || graphics.IsVisible(r.Visibility, p.Position)
+}
+
+func (r *Renderer) drawProjectile(p Projectile) {
+	style := p.Style
+
+	// Draw projectile head
+	rl.DrawSphere(p.Position, style.Size, style.Color)
+
+	// Draw the trail behind it
+	speed := float32(math.Sqrt(float64(p.Velocity.X*p.Velocity.X + p.Velocity.Z*p.Velocity.Z)))
+	if speed <= 0 || style.TrailLength <= 0 {
+		return
+	}
+	dirX := -p.Velocity.X / speed
+	dirZ := -p.Velocity.Z / speed
+
+	switch style.Trail {
+	case TrailSmoke:
+		r.drawSmokeTrail(p.Position, dirX, dirZ, style)
+	default:
+		trailEnd := rl.Vector3{
+			X: p.Position.X + dirX*style.TrailLength,
+			Y: p.Position.Y,
+			Z: p.Position.Z + dirZ*style.TrailLength,
+		}
+		rl.DrawLine3D(p.Position, trailEnd, style.Color)
+	}
+}
+
+// drawSmokeTrail draws a missile's trail as puffs that fade and grow
+func (r *Renderer) drawSmokeTrail(pos rl.Vector3, dirX, dirZ float32, style Style) {
+	puffs := r.Graphics.ParticleCount(smokePuffs)
+	for i := 1; i <= puffs; i++ {
+		t := float32(i) / float32(puffs)
+		puffPos := rl.Vector3{
+			X: pos.X + dirX*style.TrailLength*t,
+			Y: pos.Y,
+			Z: pos.Z + dirZ*style.TrailLength*t,
+		}
+		alpha := uint8(180 * (1 - t))
+		rl.DrawSphere(puffPos, style.Size*(1+t), rl.Color{R: 160, G: 160, B: 160, A: alpha})
+	}
+}
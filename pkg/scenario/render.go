@@ -0,0 +1,23 @@
+package scenario
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DrawMessages draws active scenario messages centered near the top of the
+// screen, newest at the bottom
+func DrawMessages(r *Runner, screenWidth int) {
+	const fontSize = 22
+	y := int32(80)
+	for _, m := range r.Messages() {
+		// Fade out over the last second
+		alpha := uint8(255)
+		if m.Remaining < 1 {
+			alpha = uint8(255 * m.Remaining)
+		}
+
+		width := rl.MeasureText(m.Text, fontSize)
+		rl.DrawText(m.Text, int32(screenWidth)/2-width/2, y, fontSize, rl.Color{R: 255, G: 220, B: 80, A: alpha})
+		y += fontSize + 6
+	}
+}
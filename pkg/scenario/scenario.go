@@ -0,0 +1,168 @@
+package scenario
+
+import (
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// defaultMessageTime is how long ShowMessage text stays up
+const defaultMessageTime = 4.0
+
+// State is the part of the game triggers can inspect and act on
+type State struct {
+	Elapsed float32 // Seconds since the scenario started
+	Units   *unit.Manager
+	Bases   *base.Manager
+
+	runner *Runner
+}
+
+// ShowMessage displays text to the player for a number of seconds
+func (s *State) ShowMessage(text string, seconds float32) {
+	s.runner.messages = append(s.runner.messages, Message{Text: text, Remaining: seconds})
+}
+
+// Condition reports whether a trigger should fire
+type Condition func(s *State) bool
+
+// Action is what a trigger does when it fires
+type Action func(s *State)
+
+// Trigger runs an action when its condition becomes true
+type Trigger struct {
+	Name      string
+	Condition Condition
+	Action    Action
+	Repeat    bool // Fire each time the condition becomes true again, not just the first
+
+	fired   bool // Has fired at least once
+	wasTrue bool // Condition held on the previous tick
+}
+
+// Message is scenario text shown to the player
+type Message struct {
+	Text      string
+	Remaining float32 // Seconds left on screen
+}
+
+// Runner evaluates scenario triggers each tick
+type Runner struct {
+	triggers []*Trigger
+	messages []Message
+	elapsed  float32
+}
+
+// NewRunner creates a runner with no triggers
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Add registers a trigger
+func (r *Runner) Add(t Trigger) {
+	r.triggers = append(r.triggers, &t)
+}
+
+// Reset re-arms every trigger and clears messages for a new match
+func (r *Runner) Reset() {
+	for _, t := range r.triggers {
+		t.fired = false
+		t.wasTrue = false
+	}
+	r.messages = r.messages[:0]
+	r.elapsed = 0
+}
+
+// Update ages messages and fires any trigger whose condition has just
+// become true. Triggers fire on the rising edge, so a condition that stays
+// true doesn't fire again every tick; one-shot triggers never fire twice.
+func (r *Runner) Update(dt float32, units *unit.Manager, bases *base.Manager) {
+	if dt <= 0 {
+		return // Triggers and message timers hold while paused
+	}
+	r.elapsed += dt
+	r.updateMessages(dt)
+
+	state := &State{Elapsed: r.elapsed, Units: units, Bases: bases, runner: r}
+	for _, t := range r.triggers {
+		if t.fired && !t.Repeat {
+			continue
+		}
+
+		now := t.Condition(state)
+		if now && !t.wasTrue {
+			t.fired = true
+			t.Action(state)
+		}
+		t.wasTrue = now
+	}
+}
+
+// updateMessages ages messages and drops expired ones
+func (r *Runner) updateMessages(dt float32) {
+	active := r.messages[:0]
+	for _, m := range r.messages {
+		m.Remaining -= dt
+		if m.Remaining > 0 {
+			active = append(active, m)
+		}
+	}
+	r.messages = active
+}
+
+// Messages returns the messages currently on screen, oldest first
+func (r *Runner) Messages() []Message {
+	return r.messages
+}
+
+// Common conditions
+
+// OwnsOutposts is true while an owner holds at least n outposts
+func OwnsOutposts(owner base.Owner, n int) Condition {
+	return func(s *State) bool {
+		count := 0
+		for _, b := range s.Bases.GetBasesOwnedBy(owner) {
+			if b.Type == base.TypeOutpost {
+				count++
+			}
+		}
+		return count >= n
+	}
+}
+
+// HQHealthBelow is true while an owner's HQ is under a fraction of its
+// max health
+func HQHealthBelow(owner base.Owner, fraction float32) Condition {
+	return func(s *State) bool {
+		hq := s.Bases.GetHQ(owner)
+		return hq != nil && hq.Health < hq.MaxHealth*fraction
+	}
+}
+
+// After is true once the scenario has run for a number of seconds
+func After(seconds float32) Condition {
+	return func(s *State) bool {
+		return s.Elapsed >= seconds
+	}
+}
+
+// Common actions
+
+// ShowMessage shows text for a few seconds
+func ShowMessage(text string) Action {
+	return func(s *State) {
+		s.ShowMessage(text, defaultMessageTime)
+	}
+}
+
+// SpawnWave queues units at an owner's HQ
+func SpawnWave(owner base.Owner, unitType unit.UnitType, count int) Action {
+	return func(s *State) {
+		hq := s.Bases.GetHQ(owner)
+		if hq == nil {
+			return
+		}
+		for i := 0; i < count; i++ {
+			hq.QueueUnit(unitType)
+		}
+	}
+}
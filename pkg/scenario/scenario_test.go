@@ -0,0 +1,109 @@
+package scenario
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+	"github.com/chazu/herzog-drei/pkg/unit"
+)
+
+// switchTrigger returns a trigger whose condition follows a flag, and a
+// count of how often it fired
+func switchTrigger(repeat bool) (Trigger, *bool, *int) {
+	on, fired := new(bool), new(int)
+	return Trigger{
+		Name:      "switch",
+		Condition: func(*State) bool { return *on },
+		Action:    func(*State) { *fired++ },
+		Repeat:    repeat,
+	}, on, fired
+}
+
+func TestOneShotTriggerFiresOnce(t *testing.T) {
+	r := NewRunner()
+	trigger, on, fired := switchTrigger(false)
+	r.Add(trigger)
+
+	r.Update(1, nil, nil)
+	if *fired != 0 {
+		t.Fatal("fired before its condition held")
+	}
+
+	*on = true
+	for i := 0; i < 5; i++ {
+		r.Update(1, nil, nil)
+	}
+	if *fired != 1 {
+		t.Errorf("fired %d times while its condition held, want once", *fired)
+	}
+
+	// Turning false and true again doesn't fire a one-shot trigger
+	*on = false
+	r.Update(1, nil, nil)
+	*on = true
+	r.Update(1, nil, nil)
+	if *fired != 1 {
+		t.Errorf("one-shot trigger fired %d times", *fired)
+	}
+
+	// A new match re-arms it
+	r.Reset()
+	r.Update(1, nil, nil)
+	if *fired != 2 {
+		t.Errorf("fired %d times after a reset, want 2", *fired)
+	}
+}
+
+func TestRepeatTriggerFiresOnEachRise(t *testing.T) {
+	r := NewRunner()
+	trigger, on, fired := switchTrigger(true)
+	r.Add(trigger)
+
+	for rise := 1; rise <= 3; rise++ {
+		*on = true
+		r.Update(1, nil, nil)
+		r.Update(1, nil, nil)
+		*on = false
+		r.Update(1, nil, nil)
+		if *fired != rise {
+			t.Fatalf("fired %d times after %d rises", *fired, rise)
+		}
+	}
+
+	// Nothing fires while paused
+	*on = true
+	r.Update(0, nil, nil)
+	if *fired != 3 {
+		t.Errorf("fired while paused")
+	}
+}
+
+func TestOutpostsTriggerSpawnsWave(t *testing.T) {
+	bm := base.NewManager(base.DefaultConfig())
+	hq := bm.AddBase(base.TypeHQ, rl.NewVector3(0, 0, 0), base.OwnerPlayer2)
+	outposts := []*base.Base{
+		bm.AddBase(base.TypeOutpost, rl.NewVector3(10, 0, 0), base.OwnerNeutral),
+		bm.AddBase(base.TypeOutpost, rl.NewVector3(20, 0, 0), base.OwnerNeutral),
+	}
+
+	r := NewRunner()
+	r.Add(Trigger{
+		Name:      "wave",
+		Condition: OwnsOutposts(base.OwnerPlayer1, 2),
+		Action:    SpawnWave(base.OwnerPlayer2, unit.TypeTank, 3),
+	})
+
+	outposts[0].SetOwner(base.OwnerPlayer1)
+	r.Update(1, nil, bm)
+	if len(hq.SpawnQueue) != 0 {
+		t.Fatal("wave sent with one outpost taken")
+	}
+	outposts[1].SetOwner(base.OwnerPlayer1)
+	r.Update(1, nil, bm)
+	r.Update(1, nil, bm)
+	if len(hq.SpawnQueue) != 3 {
+		t.Errorf("%d units queued, want one wave of 3", len(hq.SpawnQueue))
+	}
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+)
+
+// MatchRecord is the saved result of one finished match
+type MatchRecord struct {
+	EndedAt  time.Time   `json:"ended_at"`
+	Winner   base.Owner  `json:"winner"`
+	Duration float64     `json:"duration_seconds"`
+	Player1  PlayerStats `json:"player1"`
+	Player2  PlayerStats `json:"player2"`
+}
+
+// NewRecord builds a record from a finished match's tracker
+func NewRecord(t *Tracker, winner base.Owner, endedAt time.Time) MatchRecord {
+	return MatchRecord{
+		EndedAt:  endedAt,
+		Winner:   winner,
+		Duration: t.Duration,
+		Player1:  t.Player1,
+		Player2:  t.Player2,
+	}
+}
+
+// Summary aggregates Player 1's record over many matches
+type Summary struct {
+	Matches int
+	Wins    int
+	Losses  int
+}
+
+// Summarize totals wins and losses from Player 1's point of view
+func Summarize(records []MatchRecord) Summary {
+	var s Summary
+	for _, r := range records {
+		s.Matches++
+		switch r.Winner {
+		case base.OwnerPlayer1:
+			s.Wins++
+		case base.OwnerPlayer2:
+			s.Losses++
+		}
+	}
+	return s
+}
+
+// LoadHistory reads all match records from a stats file. A missing file is
+// an empty history; a corrupt one is reported so callers can start fresh.
+func LoadHistory(path string) ([]MatchRecord, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []MatchRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// AppendRecord adds a record to the stats file and returns the full history.
+// If the existing file can't be read, the history starts fresh with this
+// record rather than failing the save.
+func AppendRecord(path string, record MatchRecord) ([]MatchRecord, error) {
+	records, err := LoadHistory(path)
+	if err != nil {
+		records = nil // Corrupt or unreadable, start over
+	}
+	records = append(records, record)
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return records, err
+	}
+	return records, os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+)
+
+func TestFinishedMatchAppendsRecord(t *testing.T) {
+	tr := NewTracker()
+	tr.Tick(90)
+	tr.Tick(0) // Paused
+	tr.UnitBuilt(base.OwnerPlayer1)
+	tr.UnitBuilt(base.OwnerPlayer1)
+	tr.UnitBuilt(base.OwnerPlayer2)
+	tr.UnitLost(base.OwnerPlayer2)
+	tr.BaseCaptured(base.OwnerPlayer1)
+	tr.UnitBuilt(base.OwnerNeutral) // Not counted for anyone
+
+	ended := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "stats.json")
+	if _, err := AppendRecord(path, NewRecord(tr, base.OwnerPlayer1, ended)); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MatchRecord{
+		EndedAt:  ended,
+		Winner:   base.OwnerPlayer1,
+		Duration: 90,
+		Player1:  PlayerStats{UnitsBuilt: 2, BasesCaptured: 1},
+		Player2:  PlayerStats{UnitsBuilt: 1, UnitsLost: 1},
+	}
+	if len(records) != 1 || !reflect.DeepEqual(records[0], want) {
+		t.Errorf("saved %+v, want [%+v]", records, want)
+	}
+}
+
+func TestSummaryTotalsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	winners := []base.Owner{base.OwnerPlayer1, base.OwnerPlayer2, base.OwnerPlayer1, base.OwnerPlayer1, base.OwnerPlayer2}
+	var history []MatchRecord
+	for _, w := range winners {
+		var err error
+		if history, err = AppendRecord(path, NewRecord(NewTracker(), w, time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Summary{Matches: 5, Wins: 3, Losses: 2}
+	if got := Summarize(loaded); got != want {
+		t.Errorf("summary from file %+v, want %+v", got, want)
+	}
+	if got := Summarize(history); got != want {
+		t.Errorf("summary from the last save %+v, want %+v", got, want)
+	}
+}
+
+func TestCorruptHistoryStartsFresh(t *testing.T) {
+	dir := t.TempDir()
+	if records, err := LoadHistory(filepath.Join(dir, "missing.json")); err != nil || len(records) != 0 {
+		t.Errorf("missing file loaded %v, %v, want an empty history", records, err)
+	}
+
+	path := filepath.Join(dir, "stats.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHistory(path); err == nil {
+		t.Error("corrupt file loaded without an error")
+	}
+	records, err := AppendRecord(path, NewRecord(NewTracker(), base.OwnerPlayer2, time.Now()))
+	if err != nil || len(records) != 1 {
+		t.Fatalf("appending to a corrupt file gave %d records, %v", len(records), err)
+	}
+	if loaded, err := LoadHistory(path); err != nil || len(loaded) != 1 {
+		t.Errorf("after starting fresh loaded %d records, %v", len(loaded), err)
+	}
+}
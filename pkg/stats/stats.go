@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"github.com/chazu/herzog-drei/pkg/base"
+)
+
+// PlayerStats counts what one player did during a match
+type PlayerStats struct {
+	UnitsBuilt    int `json:"units_built"`
+	UnitsLost     int `json:"units_lost"`
+	BasesCaptured int `json:"bases_captured"`
+}
+
+// Tracker accumulates statistics over a single match
+type Tracker struct {
+	Duration float64 // Seconds of simulated play
+	Player1  PlayerStats
+	Player2  PlayerStats
+}
+
+// NewTracker creates an empty match tracker
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Reset clears all counts for a new match
+func (t *Tracker) Reset() {
+	*t = Tracker{}
+}
+
+// Tick advances the match clock
+func (t *Tracker) Tick(dt float32) {
+	if dt <= 0 {
+		return
+	}
+	t.Duration += float64(dt)
+}
+
+// UnitBuilt records a unit produced by a player
+func (t *Tracker) UnitBuilt(owner base.Owner) {
+	if p := t.player(owner); p != nil {
+		p.UnitsBuilt++
+	}
+}
+
+// UnitLost records a unit a player lost
+func (t *Tracker) UnitLost(owner base.Owner) {
+	if p := t.player(owner); p != nil {
+		p.UnitsLost++
+	}
+}
+
+// BaseCaptured records a base a player took
+func (t *Tracker) BaseCaptured(owner base.Owner) {
+	if p := t.player(owner); p != nil {
+		p.BasesCaptured++
+	}
+}
+
+// player returns the stats for an owner (nil for neutral)
+func (t *Tracker) player(owner base.Owner) *PlayerStats {
+	switch owner {
+	case base.OwnerPlayer1:
+		return &t.Player1
+	case base.OwnerPlayer2:
+		return &t.Player2
+	default:
+		return nil
+	}
+}
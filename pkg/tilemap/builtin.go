@@ -0,0 +1,200 @@
+package tilemap
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Builtin is a named map that ships with the game, with its bases
+type Builtin struct {
+	Name   string
+	Map    *TileMap
+	Layout Layout
+}
+
+// seededMaps are builtins made by GenerateProcedural from a fixed seed, at
+// the standard size with the standard layout, the same every time. Each
+// seed was picked for a map that lives up to its name.
+var seededMaps = map[string]int64{
+	"plains":      41, // Little water or mountain
+	"river-delta": 57, // Mostly lakes
+	"badlands":    6,  // Mountain ranges
+}
+
+// Handcrafted builtins are text files, one character per tile:
+//
+//	.  ground      ~  water      ^  mountain   T  forest   =  road
+//	1  player 1 HQ               2  player 2 HQ
+//	O  neutral outpost           a  player 1 outpost       b  player 2 outpost
+//	$  resource point
+//
+// Base characters stand on ground.
+//
+//go:embed maps/*.txt
+var handcraftedMaps embed.FS
+
+// Standard builtin map size, matching the game's default map
+const (
+	BuiltinWidth  = 64
+	BuiltinHeight = 48
+)
+
+// BuiltinNames returns the names LoadBuiltin accepts, sorted
+func BuiltinNames() []string {
+	names := make([]string, 0, len(seededMaps))
+	for name := range seededMaps {
+		names = append(names, name)
+	}
+	entries, _ := handcraftedMaps.ReadDir("maps")
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadBuiltin returns a fresh copy of a named builtin map and its base
+// layout. Every builtin is checked with ValidateLayout before it is
+// returned.
+func LoadBuiltin(name string) (*Builtin, error) {
+	var b *Builtin
+	if seed, ok := seededMaps[name]; ok {
+		b = proceduralBuiltin(name, seed)
+	} else {
+		data, err := handcraftedMaps.ReadFile("maps/" + name + ".txt")
+		if err != nil {
+			return nil, fmt.Errorf("unknown builtin map: %s", name)
+		}
+		if b, err = parseMapText(name, string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ValidateLayout(b.Map, b.Layout); err != nil {
+		return nil, fmt.Errorf("builtin map %s: %w", name, err)
+	}
+	return b, nil
+}
+
+// parseMapText reads a handcrafted map
+func parseMapText(name, text string) (*Builtin, error) {
+	rows := strings.Split(strings.TrimSpace(text), "\n")
+	height := len(rows)
+	width := len(strings.TrimSpace(rows[0]))
+
+	tm := NewTileMap(width, height)
+	layout := Layout{}
+	for y, row := range rows {
+		row = strings.TrimSpace(row)
+		if len(row) != width {
+			return nil, fmt.Errorf("builtin map %s: row %d is %d tiles wide, want %d", name, y, len(row), width)
+		}
+		for x, c := range row {
+			if terrain, ok := glyphTerrain(c); ok {
+				tm.SetTerrain(x, y, terrain)
+				continue
+			}
+			switch c {
+			case '1', '2':
+				layout.Sites = append(layout.Sites, Site{Kind: SiteHQ, Side: int(c - '0'), X: x, Y: y})
+			case 'O':
+				layout.Sites = append(layout.Sites, Site{Kind: SiteOutpost, X: x, Y: y})
+			case 'a', 'b':
+				layout.Sites = append(layout.Sites, Site{Kind: SiteOutpost, Side: int(c-'a') + 1, X: x, Y: y})
+			case '$':
+				layout.Sites = append(layout.Sites, Site{Kind: SiteResource, X: x, Y: y})
+			default:
+				return nil, fmt.Errorf("builtin map %s: unknown tile %q at (%d, %d)", name, c, x, y)
+			}
+		}
+	}
+	return &Builtin{Name: name, Map: tm, Layout: layout}, nil
+}
+
+// StandardLayout returns the symmetric base layout the default match uses,
+// centered on a map of the given size: each HQ at one end with two outposts
+// in front, five neutral outposts across the middle, and a resource point
+// on each flank
+func StandardLayout(width, height int) Layout {
+	cx, cy := width/2, height/2
+	site := func(kind SiteKind, side, dx, dy int) Site {
+		return Site{Kind: kind, Side: side, X: cx + dx, Y: cy + dy}
+	}
+	return Layout{Sites: []Site{
+		site(SiteHQ, 1, 0, -15),
+		site(SiteHQ, 2, 0, 15),
+
+		site(SiteOutpost, 0, 0, 0),
+		site(SiteOutpost, 0, -10, -5),
+		site(SiteOutpost, 0, 10, -5),
+		site(SiteOutpost, 0, -10, 5),
+		site(SiteOutpost, 0, 10, 5),
+
+		site(SiteOutpost, 1, -8, -10),
+		site(SiteOutpost, 1, 8, -10),
+		site(SiteOutpost, 2, -8, 10),
+		site(SiteOutpost, 2, 8, 10),
+
+		site(SiteResource, 0, -18, 0),
+		site(SiteResource, 0, 18, 0),
+	}}
+}
+
+// siteClearance is how many tiles around each base are kept clear ground
+const siteClearance = 2
+
+// proceduralBuiltin builds a procedural map from a seed, then clears ground
+// around each base and lays roads from player 1's HQ to every other base.
+// Roads bridge water and cut passes through mountains, so every base is
+// reachable whatever the seed.
+func proceduralBuiltin(name string, seed int64) *Builtin {
+	tm := GenerateProcedural(BuiltinWidth, BuiltinHeight, seed)
+	layout := StandardLayout(BuiltinWidth, BuiltinHeight)
+
+	tm.ClearSites(layout)
+
+	hq, _ := layout.HQ(1)
+	for _, s := range layout.Sites {
+		tm.layRoad(hq.X, hq.Y, s.X, s.Y)
+	}
+
+	return &Builtin{Name: name, Map: tm, Layout: layout}
+}
+
+// ClearSites turns the ground around each base in a layout to plain
+// ground, so no base sits in water or on a mountain
+func (tm *TileMap) ClearSites(layout Layout) {
+	for _, s := range layout.Sites {
+		tm.FillRect(s.X-siteClearance, s.Y-siteClearance, s.X+siteClearance, s.Y+siteClearance, TerrainGround)
+	}
+}
+
+// layRoad runs a road from one tile to another, along the row of the start
+// then up the column of the end. Tiles that are already passable apart
+// from plain ground are kept, so roads don't flatten forests.
+func (tm *TileMap) layRoad(x1, y1, x2, y2 int) {
+	pave := func(x, y int) {
+		t := tm.GetTile(x, y)
+		if t != nil && (t.Terrain == TerrainGround || !t.Terrain.IsPassable()) {
+			t.Terrain = TerrainRoad
+		}
+	}
+
+	step := 1
+	if x2 < x1 {
+		step = -1
+	}
+	for x := x1; x != x2; x += step {
+		pave(x, y1)
+	}
+
+	step = 1
+	if y2 < y1 {
+		step = -1
+	}
+	for y := y1; y != y2+step; y += step {
+		pave(x2, y)
+	}
+}
@@ -0,0 +1,37 @@
+package tilemap
+
+import "testing"
+
+func TestEveryBuiltinLoads(t *testing.T) {
+	names := BuiltinNames()
+	if len(names) < len(seededMaps)+2 {
+		t.Fatalf("only %d builtins: %v", len(names), names)
+	}
+	for _, name := range names {
+		b, err := LoadBuiltin(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if b.Name != name {
+			t.Errorf("%s: loaded as %q", name, b.Name)
+		}
+		if b.Map.Width != BuiltinWidth || b.Map.Height != BuiltinHeight || len(b.Map.Tiles) != BuiltinHeight {
+			t.Errorf("%s: %dx%d, want %dx%d", name, b.Map.Width, b.Map.Height, BuiltinWidth, BuiltinHeight)
+		}
+		if err := ValidateLayout(b.Map, b.Layout); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		hq1, _ := b.Layout.HQ(1)
+		hq2, _ := b.Layout.HQ(2)
+		if !b.Map.Connected(hq1.X, hq1.Y, hq2.X, hq2.Y) {
+			t.Errorf("%s: no path between the HQs", name)
+		}
+	}
+}
+
+func TestUnknownBuiltin(t *testing.T) {
+	if _, err := LoadBuiltin("nowhere"); err == nil {
+		t.Error("loaded a builtin that doesn't exist")
+	}
+}
@@ -6,6 +6,23 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// CameraPreset is a selectable viewing angle and distance
+type CameraPreset struct {
+	Name   string
+	Offset rl.Vector3 // Offset from the target at zoom 1
+}
+
+// CameraPresets are the views cycled through with NextPreset
+var CameraPresets = []CameraPreset{
+	{Name: "Angled", Offset: rl.Vector3{X: 0, Y: 15, Z: 10}},
+	{Name: "Tactical", Offset: rl.Vector3{X: 0, Y: 22, Z: 1}}, // Nearly straight down, a little Z keeps Up usable
+	{Name: "Cinematic", Offset: rl.Vector3{X: 0, Y: 5, Z: 12}},
+}
+
+// visibleFarDistance bounds how far past the camera the visible range
+// reaches for screen corners that look above the horizon
+const visibleFarDistance = 60.0
+
 // GameCamera provides a camera that follows a target with smooth scrolling
 type GameCamera struct {
 	Camera       rl.Camera3D
@@ -16,17 +33,23 @@ type GameCamera struct {
 	ZoomLevel    float32     // Zoom multiplier
 	MinZoom      float32
 	MaxZoom      float32
+
+	// View preset, Offset eases toward it
+	Preset          int
+	PresetBlendRate float32 // Fraction of the way to the preset offset covered per update
 }
 
 // NewGameCamera creates a new camera configured for Herzog Drei-style viewing
 func NewGameCamera() *GameCamera {
 	gc := &GameCamera{
 		Target:      rl.NewVector3(0, 0, 0),
-		Offset:      rl.NewVector3(0, 15, 10), // High above, slightly behind
+		Offset:      CameraPresets[0].Offset, // High above, slightly behind
 		SmoothSpeed: 0.1,
 		ZoomLevel:   1.0,
 		MinZoom:     0.5,
 		MaxZoom:     2.0,
+
+		PresetBlendRate: 0.08,
 	}
 
 	gc.Camera = rl.Camera3D{
@@ -45,56 +68,123 @@ func (gc *GameCamera) SetTarget(pos rl.Vector3) {
 	gc.Target = pos
 }
 
+// Yaw returns the camera's heading on the ground plane, in the same
+// convention as unit rotation (0 looks toward +Z)
+func (gc *GameCamera) Yaw() float32 {
+	forward := rl.Vector3Subtract(gc.Camera.Target, gc.Camera.Position)
+	return float32(math.Atan2(float64(forward.X), float64(forward.Z)))
+}
+
 // SetBounds sets the world bounds to constrain camera movement
 func (gc *GameCamera) SetBounds(bounds rl.BoundingBox) {
 	gc.Bounds = &bounds
 }
 
+// SetPreset switches to a view preset, easing over from the current view
+func (gc *GameCamera) SetPreset(i int) {
+	if i < 0 || i >= len(CameraPresets) {
+		return
+	}
+	gc.Preset = i
+}
+
+// NextPreset cycles to the next view preset
+func (gc *GameCamera) NextPreset() {
+	gc.SetPreset((gc.Preset + 1) % len(CameraPresets))
+}
+
+// PresetName returns the current view preset's name
+func (gc *GameCamera) PresetName() string {
+	return CameraPresets[gc.Preset].Name
+}
+
 // Update smoothly moves the camera toward its target
 func (gc *GameCamera) Update() {
-	// Calculate desired camera position
-	scaledOffset := rl.Vector3Scale(gc.Offset, gc.ZoomLevel)
-	desiredPos := rl.Vector3Add(gc.Target, scaledOffset)
+	// Ease the viewing angle toward the selected preset
+	gc.Offset = rl.Vector3Lerp(gc.Offset, CameraPresets[gc.Preset].Offset, gc.PresetBlendRate)
 
-	// Apply bounds constraints if set
+	// Keep the view inside the map bounds, if set
 	if gc.Bounds != nil {
-		desiredPos = gc.constrainToBounds(desiredPos)
+		gc.Target = gc.constrainToBounds(gc.Target, gc.aspect())
 	}
 
+	// Calculate desired camera position
+	scaledOffset := rl.Vector3Scale(gc.Offset, gc.ZoomLevel)
+	desiredPos := rl.Vector3Add(gc.Target, scaledOffset)
+
 	// Smooth interpolation toward desired position
 	gc.Camera.Position = rl.Vector3Lerp(gc.Camera.Position, desiredPos, gc.SmoothSpeed)
 	gc.Camera.Target = rl.Vector3Lerp(gc.Camera.Target, gc.Target, gc.SmoothSpeed)
 }
 
-// constrainToBounds keeps the camera view within map bounds
-func (gc *GameCamera) constrainToBounds(pos rl.Vector3) rl.Vector3 {
+// constrainToBounds returns the look-at target moved so the ground the
+// camera sees at the current zoom and angle stays within the map bounds,
+// reaching right up to an edge but not past it. Along an axis where the
+// view is wider than the map, the view is centered on the map instead.
+func (gc *GameCamera) constrainToBounds(target rl.Vector3, aspect float32) rl.Vector3 {
 	if gc.Bounds == nil {
-		return pos
+		return target
 	}
 
-	// Keep target within bounds (camera can extend past to see edges)
-	result := pos
-
-	minX := gc.Bounds.Min.X
-	maxX := gc.Bounds.Max.X
-	minZ := gc.Bounds.Min.Z
-	maxZ := gc.Bounds.Max.Z
+	minX, maxX, minZ, maxZ := gc.viewFootprint(aspect)
+	target.X = clampView(target.X, gc.Bounds.Min.X-minX, gc.Bounds.Max.X-maxX)
+	target.Z = clampView(target.Z, gc.Bounds.Min.Z-minZ, gc.Bounds.Max.Z-maxZ)
+	return target
+}
 
-	// Constrain the look-at target
-	if gc.Target.X < minX {
-		gc.Target.X = minX
+// clampView clamps v to lo..hi, or centers it when the range is empty
+// because the view is wider than the bounds
+func clampView(v, lo, hi float32) float32 {
+	if lo > hi {
+		return (lo + hi) / 2
 	}
-	if gc.Target.X > maxX {
-		gc.Target.X = maxX
+	if v < lo {
+		return lo
 	}
-	if gc.Target.Z < minZ {
-		gc.Target.Z = minZ
+	if v > hi {
+		return hi
 	}
-	if gc.Target.Z > maxZ {
-		gc.Target.Z = maxZ
+	return v
+}
+
+// viewFootprint returns how far the visible ground reaches from the look-at
+// target along each world axis, for the camera at its zoomed preset offset
+// (not wherever smoothing has it this frame) with a screen aspect ratio.
+// Screen corners looking out past the horizon don't count, so low views are
+// held no tighter than the look-at target on their far side.
+func (gc *GameCamera) viewFootprint(aspect float32) (minX, maxX, minZ, maxZ float32) {
+	eye := rl.Vector3Scale(gc.Offset, gc.ZoomLevel)
+	forward := rl.Vector3Normalize(rl.Vector3Scale(eye, -1))
+	right := rl.Vector3Normalize(rl.Vector3CrossProduct(forward, gc.Camera.Up))
+	up := rl.Vector3CrossProduct(right, forward)
+
+	halfH := float32(math.Tan(float64(gc.Camera.Fovy) * math.Pi / 360))
+	halfW := halfH * aspect
+
+	for _, corner := range [4][2]float32{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		dir := rl.Vector3Add(forward, rl.Vector3Add(
+			rl.Vector3Scale(right, corner[0]*halfW),
+			rl.Vector3Scale(up, corner[1]*halfH),
+		))
+		hit, ok := groundHit(eye, dir)
+		if !ok {
+			continue
+		}
+		minX = float32(math.Min(float64(minX), float64(hit.X)))
+		maxX = float32(math.Max(float64(maxX), float64(hit.X)))
+		minZ = float32(math.Min(float64(minZ), float64(hit.Z)))
+		maxZ = float32(math.Max(float64(maxZ), float64(hit.Z)))
 	}
+	return minX, maxX, minZ, maxZ
+}
 
-	return result
+// aspect returns the screen's width to height ratio
+func (gc *GameCamera) aspect() float32 {
+	h := rl.GetScreenHeight()
+	if h <= 0 {
+		return 1
+	}
+	return float32(rl.GetScreenWidth()) / float32(h)
 }
 
 // Zoom adjusts the camera zoom level
@@ -115,6 +205,11 @@ func (gc *GameCamera) HandleInput() {
 	if wheel != 0 {
 		gc.Zoom(-wheel * 0.1)
 	}
+
+	// Cycle view presets
+	if rl.IsKeyPressed(rl.KeyC) {
+		gc.NextPreset()
+	}
 }
 
 // Begin3D starts 3D rendering mode with this camera
@@ -149,6 +244,46 @@ func (gc *GameCamera) ScreenToWorld(screenPos rl.Vector2, groundY float32) rl.Ve
 	}
 }
 
+// visibleGroundPoint returns where a screen position looks at the ground.
+// Low views can put screen corners above the horizon; those reach out to
+// visibleFarDistance along the ray's heading instead of hitting the ground.
+func (gc *GameCamera) visibleGroundPoint(screenPos rl.Vector2) rl.Vector3 {
+	ray := rl.GetScreenToWorldRay(screenPos, gc.Camera)
+	return groundPointAlong(ray.Position, ray.Direction)
+}
+
+// groundPointAlong intersects a ray with the ground, falling back to a far
+// point along its heading when it never comes down within range
+func groundPointAlong(origin, dir rl.Vector3) rl.Vector3 {
+	if hit, ok := groundHit(origin, dir); ok {
+		return hit
+	}
+
+	// Above the horizon or too far out, go the far distance along the heading
+	flat := float32(math.Hypot(float64(dir.X), float64(dir.Z)))
+	if flat < 0.0001 {
+		return rl.Vector3{X: origin.X, Z: origin.Z}
+	}
+	return rl.Vector3{
+		X: origin.X + dir.X/flat*visibleFarDistance,
+		Z: origin.Z + dir.Z/flat*visibleFarDistance,
+	}
+}
+
+// groundHit intersects a ray with the ground, false if it looks above the
+// horizon or comes down further out than visibleFarDistance
+func groundHit(origin, dir rl.Vector3) (rl.Vector3, bool) {
+	if dir.Y >= -0.0001 {
+		return rl.Vector3{}, false
+	}
+	t := -origin.Y / dir.Y
+	hit := rl.Vector3{X: origin.X + dir.X*t, Z: origin.Z + dir.Z*t}
+	if float32(math.Hypot(float64(hit.X-origin.X), float64(hit.Z-origin.Z))) > visibleFarDistance {
+		return rl.Vector3{}, false
+	}
+	return hit, true
+}
+
 // GetVisibleTileRange returns the range of tiles currently visible
 func (gc *GameCamera) GetVisibleTileRange(tm *TileMap) (minX, minY, maxX, maxY int) {
 	// Get corners of visible area at ground level
@@ -166,7 +301,7 @@ func (gc *GameCamera) GetVisibleTileRange(tm *TileMap) (minX, minY, maxX, maxY i
 	maxX, maxY = 0, 0
 
 	for _, corner := range corners {
-		worldPos := gc.ScreenToWorld(corner, 0)
+		worldPos := gc.visibleGroundPoint(corner)
 		tileX, tileY := tm.WorldToTile(worldPos.X, worldPos.Z)
 
 		if tileX < minX {
@@ -0,0 +1,200 @@
+package tilemap
+
+import (
+	"math"
+
+	"github.com/chazu/herzog-drei/pkg/coords"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FogMemory controls what a team remembers of ground it can no longer see
+type FogMemory int
+
+const (
+	FogMemoryFull  FogMemory = iota // Explored ground stays revealed as terrain
+	FogMemoryNone                   // Ground goes back to black once vision leaves
+	FogMemoryDecay                  // Explored ground fades back to black over MemoryTime
+)
+
+// String returns the display name of a memory mode
+func (m FogMemory) String() string {
+	switch m {
+	case FogMemoryFull:
+		return "Full"
+	case FogMemoryNone:
+		return "None"
+	case FogMemoryDecay:
+		return "Decaying"
+	default:
+		return "Unknown"
+	}
+}
+
+// FogState is how much a team knows about a tile
+type FogState int
+
+const (
+	FogUnseen   FogState = iota // Never seen, or forgotten
+	FogExplored                 // Seen before and remembered, but out of sight now
+	FogVisible                  // In sight this frame
+)
+
+// DefaultFogMemoryTime is how many seconds decaying memory lasts
+const DefaultFogMemoryTime = 30.0
+
+// exploredShade is how bright remembered ground is drawn next to ground in
+// sight
+const exploredShade = 0.45
+
+// FogOfWar tracks which tiles one team can see and which it remembers
+type FogOfWar struct {
+	Memory     FogMemory
+	MemoryTime float32 // Seconds explored ground is remembered with FogMemoryDecay
+
+	grid     coords.Grid
+	visible  []bool
+	explored []bool
+	unseen   []float32 // Seconds since each explored tile was last visible
+}
+
+// NewFogOfWar creates fog covering a tile map, with nothing seen yet
+func NewFogOfWar(tm *TileMap, memory FogMemory) *FogOfWar {
+	grid := tm.Grid()
+	n := grid.Width * grid.Height
+	return &FogOfWar{
+		Memory:     memory,
+		MemoryTime: DefaultFogMemoryTime,
+		grid:       grid,
+		visible:    make([]bool, n),
+		explored:   make([]bool, n),
+		unseen:     make([]float32, n),
+	}
+}
+
+// Reset covers the whole map again for a new match
+func (f *FogOfWar) Reset() {
+	for i := range f.visible {
+		f.visible[i] = false
+		f.explored[i] = false
+		f.unseen[i] = 0
+	}
+}
+
+// BeginFrame ages what was seen by dt and clears current vision. Call it
+// once a frame before that frame's Reveal calls.
+func (f *FogOfWar) BeginFrame(dt float32) {
+	for i := range f.visible {
+		if f.visible[i] {
+			f.unseen[i] = 0
+		} else if f.explored[i] {
+			f.unseen[i] += dt
+		}
+		f.visible[i] = false
+	}
+}
+
+// Reveal marks every tile whose center lies within radius of a world
+// position as visible and explored
+func (f *FogOfWar) Reveal(worldX, worldZ, radius float32) {
+	if radius <= 0 {
+		return
+	}
+	minX, minY := f.grid.WorldToCell(worldX-radius, worldZ-radius)
+	maxX, maxY := f.grid.WorldToCell(worldX+radius, worldZ+radius)
+	r2 := radius * radius
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			if !f.grid.InBounds(x, y) {
+				continue
+			}
+			cx, cz := f.grid.CellToWorld(x, y)
+			dx, dz := cx-worldX, cz-worldZ
+			if dx*dx+dz*dz > r2 {
+				continue
+			}
+			i := f.grid.Index(x, y)
+			f.visible[i] = true
+			f.explored[i] = true
+			f.unseen[i] = 0
+		}
+	}
+}
+
+// IsVisible returns true if a tile is in sight this frame
+func (f *FogOfWar) IsVisible(x, y int) bool {
+	if !f.grid.InBounds(x, y) {
+		return false
+	}
+	return f.visible[f.grid.Index(x, y)]
+}
+
+// IsExplored returns true if a tile is in sight or still remembered
+func (f *FogOfWar) IsExplored(x, y int) bool {
+	return f.Remembered(x, y) > 0
+}
+
+// State returns how much the team knows about a tile
+func (f *FogOfWar) State(x, y int) FogState {
+	switch {
+	case f.IsVisible(x, y):
+		return FogVisible
+	case f.IsExplored(x, y):
+		return FogExplored
+	default:
+		return FogUnseen
+	}
+}
+
+// Remembered returns how clearly a tile is known, 1 for one in sight or
+// fully remembered down to 0 for one never seen or forgotten. The main view
+// and the minimap both shade explored ground by it so they always agree.
+func (f *FogOfWar) Remembered(x, y int) float32 {
+	if !f.grid.InBounds(x, y) {
+		return 0
+	}
+	i := f.grid.Index(x, y)
+	if f.visible[i] {
+		return 1
+	}
+	if !f.explored[i] {
+		return 0
+	}
+
+	switch f.Memory {
+	case FogMemoryNone:
+		return 0
+	case FogMemoryDecay:
+		if f.MemoryTime <= 0 {
+			return 0
+		}
+		return float32(math.Max(0, float64(1-f.unseen[i]/f.MemoryTime)))
+	default:
+		return 1
+	}
+}
+
+// IsVisibleAt returns true if the tile under a world position is in sight,
+// so the fog can gate what renderers show
+func (f *FogOfWar) IsVisibleAt(pos rl.Vector3) bool {
+	return f.IsVisible(f.grid.WorldToCell(pos.X, pos.Z))
+}
+
+// IsExploredAt returns true if the tile under a world position is in sight
+// or still remembered
+func (f *FogOfWar) IsExploredAt(pos rl.Vector3) bool {
+	return f.IsExplored(f.grid.WorldToCell(pos.X, pos.Z))
+}
+
+// Shade returns a tile's color as the team knows it: unchanged in sight,
+// dimmed while remembered, and false if the tile is hidden. A nil fog shows
+// everything.
+func (f *FogOfWar) Shade(c rl.Color, x, y int) (rl.Color, bool) {
+	if f == nil || f.IsVisible(x, y) {
+		return c, true
+	}
+	k := f.Remembered(x, y) * exploredShade
+	if k <= 0 {
+		return c, false
+	}
+	return rl.NewColor(uint8(float32(c.R)*k), uint8(float32(c.G)*k), uint8(float32(c.B)*k), c.A), true
+}
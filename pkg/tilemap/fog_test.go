@@ -0,0 +1,61 @@
+package tilemap
+
+import "testing"
+
+func TestRevealMarksTilesInRadius(t *testing.T) {
+	tm := NewTileMap(20, 20)
+	fog := NewFogOfWar(tm, FogMemoryFull)
+
+	// Reveal around the center of tile (10, 10)
+	const radius = 3.2
+	fog.BeginFrame(0)
+	fog.Reveal(10.5, 10.5, radius)
+
+	for y := 0; y < tm.Height; y++ {
+		for x := 0; x < tm.Width; x++ {
+			dx, dy := float32(x-10), float32(y-10)
+			want := dx*dx+dy*dy <= radius*radius
+			if fog.IsVisible(x, y) != want {
+				t.Errorf("tile (%d, %d) visible = %v, want %v", x, y, !want, want)
+			}
+			if want && fog.State(x, y) != FogVisible {
+				t.Errorf("tile (%d, %d) state %v, want visible", x, y, fog.State(x, y))
+			}
+			if !want && fog.State(x, y) != FogUnseen {
+				t.Errorf("tile (%d, %d) state %v, want unseen", x, y, fog.State(x, y))
+			}
+		}
+	}
+}
+
+func TestExploredOutlastsSight(t *testing.T) {
+	tm := NewTileMap(30, 10)
+	fog := NewFogOfWar(tm, FogMemoryFull)
+
+	// A viewer walks from one end of the map to the other
+	for x := float32(2.5); x < 28; x++ {
+		fog.BeginFrame(0.1)
+		fog.Reveal(x, 5.5, 1.5)
+	}
+
+	if fog.State(2, 5) != FogExplored {
+		t.Errorf("tile left behind is %v, want explored", fog.State(2, 5))
+	}
+	if fog.IsVisible(2, 5) {
+		t.Error("tile left behind is still visible")
+	}
+	if fog.State(27, 5) != FogVisible {
+		t.Errorf("tile under the viewer is %v, want visible", fog.State(27, 5))
+	}
+	if fog.State(2, 0) != FogUnseen {
+		t.Errorf("tile never in range is %v, want unseen", fog.State(2, 0))
+	}
+
+	// Still explored long after
+	for i := 0; i < 600; i++ {
+		fog.BeginFrame(0.1)
+	}
+	if fog.State(2, 5) != FogExplored {
+		t.Errorf("after a minute the tile is %v, want explored", fog.State(2, 5))
+	}
+}
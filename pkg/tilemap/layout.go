@@ -0,0 +1,127 @@
+package tilemap
+
+import "fmt"
+
+// SiteKind is the kind of base a map places at a site
+type SiteKind int
+
+const (
+	SiteHQ SiteKind = iota
+	SiteOutpost
+	SiteResource
+)
+
+// siteKindNames are how site kinds are written in map files
+var siteKindNames = map[SiteKind]string{
+	SiteHQ:       "hq",
+	SiteOutpost:  "outpost",
+	SiteResource: "resource",
+}
+
+// String returns the site kind as written in map files
+func (k SiteKind) String() string {
+	if name, ok := siteKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// parseSiteKind returns the site kind a map file name stands for
+func parseSiteKind(name string) (SiteKind, bool) {
+	for k, n := range siteKindNames {
+		if n == name {
+			return k, true
+		}
+	}
+	return SiteOutpost, false
+}
+
+// Site is a base placement on a map, in tile coordinates
+type Site struct {
+	Kind SiteKind
+	Side int // 0 for neutral, 1 or 2 for the player that starts with it
+	X, Y int
+}
+
+// Layout is where a map's bases go
+type Layout struct {
+	Sites []Site
+}
+
+// HQ returns a player's HQ site
+func (l Layout) HQ(side int) (Site, bool) {
+	for _, s := range l.Sites {
+		if s.Kind == SiteHQ && s.Side == side {
+			return s, true
+		}
+	}
+	return Site{}, false
+}
+
+// ValidateHQs checks that both players have exactly one HQ
+func (l Layout) ValidateHQs() error {
+	for side := 1; side <= 2; side++ {
+		count := 0
+		for _, s := range l.Sites {
+			if s.Kind == SiteHQ && s.Side == side {
+				count++
+			}
+		}
+		if count != 1 {
+			return fmt.Errorf("player %d has %d HQs, want 1", side, count)
+		}
+	}
+	return nil
+}
+
+// ValidateLayout checks that both players have exactly one HQ and that
+// every site sits on passable ground reachable from player 1's HQ, so no
+// base is stranded and the HQs can reach each other
+func ValidateLayout(tm *TileMap, layout Layout) error {
+	if err := layout.ValidateHQs(); err != nil {
+		return err
+	}
+
+	for _, s := range layout.Sites {
+		if !tm.InBounds(s.X, s.Y) {
+			return fmt.Errorf("site at (%d, %d) is off the map", s.X, s.Y)
+		}
+		if !tm.Tiles[s.Y][s.X].Terrain.IsPassable() {
+			return fmt.Errorf("site at (%d, %d) is on impassable terrain", s.X, s.Y)
+		}
+	}
+
+	hq, _ := layout.HQ(1)
+	reached := tm.reachableFrom(hq.X, hq.Y)
+	for _, s := range layout.Sites {
+		if !reached[s.Y*tm.Width+s.X] {
+			return fmt.Errorf("site at (%d, %d) can't be reached from player 1's HQ", s.X, s.Y)
+		}
+	}
+	return nil
+}
+
+// reachableFrom flood fills passable tiles from a start tile, moving only
+// between edge neighbours. Returns a row-major mask of the tiles reached.
+func (tm *TileMap) reachableFrom(x, y int) []bool {
+	reached := make([]bool, tm.Width*tm.Height)
+	if !tm.InBounds(x, y) || !tm.Tiles[y][x].Terrain.IsPassable() {
+		return reached
+	}
+
+	reached[y*tm.Width+x] = true
+	stack := [][2]int{{x, y}}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			nx, ny := cur[0]+d[0], cur[1]+d[1]
+			if !tm.InBounds(nx, ny) || reached[ny*tm.Width+nx] || !tm.Tiles[ny][nx].Terrain.IsPassable() {
+				continue
+			}
+			reached[ny*tm.Width+nx] = true
+			stack = append(stack, [2]int{nx, ny})
+		}
+	}
+	return reached
+}
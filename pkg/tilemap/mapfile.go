@@ -0,0 +1,185 @@
+package tilemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MapFileVersion is the map file format SaveToFile writes and LoadFromFile
+// reads
+const MapFileVersion = 1
+
+// MaxMapSize is the largest width or height a map file may have
+const MaxMapSize = 1024
+
+// MapData is a map with the bases it places
+type MapData struct {
+	Map    *TileMap
+	Layout Layout
+}
+
+// mapFile is a map as saved to disk. Terrain is one string per row, one
+// character per tile, using the same legend as the builtin maps.
+type mapFile struct {
+	Version  int        `json:"version"`
+	Width    int        `json:"width"`
+	Height   int        `json:"height"`
+	TileSize float32    `json:"tile_size"`
+	Terrain  []string   `json:"terrain"`
+	Bases    []siteFile `json:"bases,omitempty"`
+}
+
+// siteFile is a base placement as saved to disk, in tile coordinates
+type siteFile struct {
+	Kind string `json:"kind"` // "hq", "outpost", or "resource"
+	Side int    `json:"side"` // 0 for neutral, 1 or 2 for the player that starts with it
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+// terrainGlyphs are the characters each terrain type is written as
+var terrainGlyphs = map[TerrainType]rune{
+	TerrainGround:   '.',
+	TerrainWater:    '~',
+	TerrainMountain: '^',
+	TerrainForest:   'T',
+	TerrainRoad:     '=',
+}
+
+// glyphTerrain returns the terrain a map character stands for
+func glyphTerrain(c rune) (TerrainType, bool) {
+	for t, g := range terrainGlyphs {
+		if g == c {
+			return t, true
+		}
+	}
+	return TerrainGround, false
+}
+
+// SaveToFile writes a map to a file that LoadFromFile reads back exactly
+func SaveToFile(tm *TileMap, path string) error {
+	return SaveMap(&MapData{Map: tm}, path)
+}
+
+// SaveMap writes a map and its bases to a file that LoadMap reads back
+// exactly
+func SaveMap(data *MapData, path string) error {
+	tm := data.Map
+	f := mapFile{
+		Version:  MapFileVersion,
+		Width:    tm.Width,
+		Height:   tm.Height,
+		TileSize: tm.TileSize,
+		Terrain:  make([]string, tm.Height),
+	}
+
+	var row strings.Builder
+	for y := 0; y < tm.Height; y++ {
+		row.Reset()
+		for x := 0; x < tm.Width; x++ {
+			g, ok := terrainGlyphs[tm.Tiles[y][x].Terrain]
+			if !ok {
+				return fmt.Errorf("save map %s: unknown terrain %d at (%d, %d)", path, tm.Tiles[y][x].Terrain, x, y)
+			}
+			row.WriteRune(g)
+		}
+		f.Terrain[y] = row.String()
+	}
+	for _, s := range data.Layout.Sites {
+		f.Bases = append(f.Bases, siteFile{Kind: s.Kind.String(), Side: s.Side, X: s.X, Y: s.Y})
+	}
+
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save map %s: %w", path, err)
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+// LoadFromFile reads the terrain of a map saved with SaveToFile or SaveMap.
+// A file from another version of the format, or with dimensions that don't
+// match its terrain, is refused with an error saying what's wrong.
+func LoadFromFile(path string) (*TileMap, error) {
+	data, err := LoadMap(path)
+	if err != nil {
+		return nil, err
+	}
+	return data.Map, nil
+}
+
+// LoadMap reads a map and its bases saved with SaveMap. Bases must be of a
+// known kind and on the map, whether each player has an HQ is left to
+// whoever places them.
+func LoadMap(path string) (*MapData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f mapFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("load map %s: %w", path, err)
+	}
+	tm, err := f.tileMap()
+	if err != nil {
+		return nil, fmt.Errorf("load map %s: %w", path, err)
+	}
+	layout, err := f.layout(tm)
+	if err != nil {
+		return nil, fmt.Errorf("load map %s: %w", path, err)
+	}
+	return &MapData{Map: tm, Layout: layout}, nil
+}
+
+// layout checks a loaded map file's bases against its map
+func (f *mapFile) layout(tm *TileMap) (Layout, error) {
+	var layout Layout
+	for i, b := range f.Bases {
+		kind, ok := parseSiteKind(b.Kind)
+		if !ok {
+			return Layout{}, fmt.Errorf("base %d has unknown kind %q", i, b.Kind)
+		}
+		if b.Side < 0 || b.Side > 2 {
+			return Layout{}, fmt.Errorf("base %d has side %d, want 0, 1, or 2", i, b.Side)
+		}
+		if !tm.InBounds(b.X, b.Y) {
+			return Layout{}, fmt.Errorf("base %d at (%d, %d) is off the map", i, b.X, b.Y)
+		}
+		layout.Sites = append(layout.Sites, Site{Kind: kind, Side: b.Side, X: b.X, Y: b.Y})
+	}
+	return layout, nil
+}
+
+// tileMap checks a loaded map file and builds the map it describes
+func (f *mapFile) tileMap() (*TileMap, error) {
+	if f.Version != MapFileVersion {
+		return nil, fmt.Errorf("unsupported version %d, want %d", f.Version, MapFileVersion)
+	}
+	if f.Width <= 0 || f.Height <= 0 || f.Width > MaxMapSize || f.Height > MaxMapSize {
+		return nil, fmt.Errorf("bad dimensions %dx%d, each must be 1 to %d", f.Width, f.Height, MaxMapSize)
+	}
+	if f.TileSize <= 0 {
+		return nil, fmt.Errorf("bad tile size %v", f.TileSize)
+	}
+	if len(f.Terrain) != f.Height {
+		return nil, fmt.Errorf("%d terrain rows, want %d", len(f.Terrain), f.Height)
+	}
+
+	tm := NewTileMap(f.Width, f.Height)
+	tm.TileSize = f.TileSize
+	for y, row := range f.Terrain {
+		if n := len([]rune(row)); n != f.Width {
+			return nil, fmt.Errorf("row %d is %d tiles wide, want %d", y, n, f.Width)
+		}
+		for x, c := range []rune(row) {
+			terrain, ok := glyphTerrain(c)
+			if !ok {
+				return nil, fmt.Errorf("unknown tile %q at (%d, %d)", c, x, y)
+			}
+			tm.Tiles[y][x].Terrain = terrain
+		}
+	}
+	return tm, nil
+}
@@ -0,0 +1,64 @@
+package tilemap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMapFileRoundTrip(t *testing.T) {
+	tm := GenerateTestMap(64, 48)
+	tm.TileSize = 2
+	path := filepath.Join(t.TempDir(), "test.map")
+	if err := SaveToFile(tm, path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Width != tm.Width || got.Height != tm.Height || got.TileSize != tm.TileSize {
+		t.Fatalf("loaded %dx%d with tile size %v, want %dx%d with %v",
+			got.Width, got.Height, got.TileSize, tm.Width, tm.Height, tm.TileSize)
+	}
+	for y := 0; y < tm.Height; y++ {
+		for x := 0; x < tm.Width; x++ {
+			if got.Tiles[y][x].Terrain != tm.Tiles[y][x].Terrain {
+				t.Errorf("tile (%d, %d) is %v, want %v", x, y, got.Tiles[y][x].Terrain, tm.Tiles[y][x].Terrain)
+			}
+		}
+	}
+}
+
+func TestMapFileRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"unknown version", `{"version": 99, "width": 2, "height": 1, "tile_size": 1, "terrain": [".."]}`},
+		{"zero width", `{"version": 1, "width": 0, "height": 1, "tile_size": 1, "terrain": [""]}`},
+		{"negative height", `{"version": 1, "width": 2, "height": -1, "tile_size": 1, "terrain": []}`},
+		{"too large", `{"version": 1, "width": 100000, "height": 1, "tile_size": 1, "terrain": ["."]}`},
+		{"rows don't match height", `{"version": 1, "width": 2, "height": 3, "tile_size": 1, "terrain": [".."]}`},
+		{"row doesn't match width", `{"version": 1, "width": 3, "height": 1, "tile_size": 1, "terrain": [".."]}`},
+		{"zero tile size", `{"version": 1, "width": 2, "height": 1, "tile_size": 0, "terrain": [".."]}`},
+		{"unknown tile", `{"version": 1, "width": 2, "height": 1, "tile_size": 1, "terrain": [".?"]}`},
+		{"not json", `version 1`},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, "bad.map")
+		if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if tm, err := LoadFromFile(path); err == nil {
+			t.Errorf("%s: loaded a %dx%d map, want an error", tt.name, tm.Width, tm.Height)
+		}
+	}
+
+	if _, err := LoadFromFile(filepath.Join(dir, "missing.map")); err == nil {
+		t.Error("missing file: want an error")
+	}
+}
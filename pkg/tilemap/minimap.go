@@ -1,6 +1,8 @@
 package tilemap
 
 import (
+	"math"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -12,6 +14,16 @@ type Minimap struct {
 	BorderWidth   int32
 	ShowViewport  bool    // Draw rectangle showing current camera view
 	Alpha         uint8   // Transparency (0-255)
+
+	// RotateWithCamera turns the minimap so "up" matches the camera's view
+	// direction; false keeps it fixed north-up
+	RotateWithCamera bool
+
+	// Fog hides ground the player hasn't seen and dims what they only
+	// remember, matching the main view (nil = whole map shown)
+	Fog *FogOfWar
+
+	angle float32 // Rotation used for the last Render, in radians
 }
 
 // NewMinimap creates a new minimap with default settings
@@ -42,6 +54,11 @@ func (mm *Minimap) SetSize(width, height int32) {
 
 // Render draws the minimap
 func (mm *Minimap) Render(tm *TileMap, camera *GameCamera) {
+	mm.angle = 0
+	if mm.RotateWithCamera && camera != nil {
+		mm.angle = MinimapRotation(camera.Yaw())
+	}
+
 	// Draw background
 	bgColor := rl.NewColor(20, 20, 20, mm.Alpha)
 	rl.DrawRectangle(mm.X-mm.BorderWidth, mm.Y-mm.BorderWidth,
@@ -52,14 +69,33 @@ func (mm *Minimap) Render(tm *TileMap, camera *GameCamera) {
 	scaleX := float32(mm.Width) / float32(tm.Width)
 	scaleY := float32(mm.Height) / float32(tm.Height)
 
+	// Rotated corners poke outside the frame, so clip to it
+	rl.BeginScissorMode(mm.X, mm.Y, mm.Width, mm.Height)
+	defer rl.EndScissorMode()
+
 	// Draw terrain tiles
 	for y := 0; y < tm.Height; y++ {
 		for x := 0; x < tm.Width; x++ {
 			tile := tm.Tiles[y][x]
 			info := GetTerrainInfo(tile.Terrain)
 
-			// Apply alpha to terrain color
-			color := rl.NewColor(info.Color.R, info.Color.G, info.Color.B, mm.Alpha)
+			// Apply alpha to terrain color, unseen ground left as background
+			color, seen := mm.Fog.Shade(rl.NewColor(info.Color.R, info.Color.G, info.Color.B, mm.Alpha), x, y)
+			if !seen {
+				continue
+			}
+
+			if mm.angle != 0 {
+				// Rotate each tile about its own center
+				center := mm.TransformPoint(rl.Vector2{
+					X: float32(mm.X) + (float32(x)+0.5)*scaleX,
+					Y: float32(mm.Y) + (float32(y)+0.5)*scaleY,
+				})
+				rec := rl.Rectangle{X: center.X, Y: center.Y, Width: scaleX + 1, Height: scaleY + 1}
+				origin := rl.Vector2{X: rec.Width / 2, Y: rec.Height / 2}
+				rl.DrawRectanglePro(rec, origin, mm.angle*rl.Rad2deg, color)
+				continue
+			}
 
 			pixelX := mm.X + int32(float32(x)*scaleX)
 			pixelY := mm.Y + int32(float32(y)*scaleY)
@@ -76,6 +112,70 @@ func (mm *Minimap) Render(tm *TileMap, camera *GameCamera) {
 	}
 }
 
+// Contains returns true if a screen position lies over the minimap
+func (mm *Minimap) Contains(screenPos rl.Vector2) bool {
+	return screenPos.X >= float32(mm.X) && screenPos.X < float32(mm.X+mm.Width) &&
+		screenPos.Y >= float32(mm.Y) && screenPos.Y < float32(mm.Y+mm.Height)
+}
+
+// ScreenToWorld converts a screen position over the minimap to world X/Z
+// Returns false if the position is outside the minimap
+func (mm *Minimap) ScreenToWorld(tm *TileMap, screenPos rl.Vector2) (float32, float32, bool) {
+	if !mm.Contains(screenPos) {
+		return 0, 0, false
+	}
+
+	// Undo the rotation, then the world-to-pixel mapping used when drawing
+	p := rotateAround(screenPos, mm.center(), -mm.angle)
+	worldX, worldZ := tm.Grid().ScreenToWorld(p, mm.rect())
+	return worldX, worldZ, true
+}
+
+// rect returns the minimap's screen area
+func (mm *Minimap) rect() rl.Rectangle {
+	return rl.Rectangle{X: float32(mm.X), Y: float32(mm.Y), Width: float32(mm.Width), Height: float32(mm.Height)}
+}
+
+// MinimapRotation returns the angle in radians the minimap turns by so a
+// camera with the given yaw looks toward the top of the minimap
+func MinimapRotation(yaw float32) float32 {
+	// Unrotated, world +X is minimap right and world +Z is minimap down
+	viewAngle := math.Atan2(math.Cos(float64(yaw)), math.Sin(float64(yaw)))
+	return float32(-math.Pi/2 - viewAngle)
+}
+
+// TransformPoint rotates an unrotated minimap pixel position about the
+// minimap's center by the current rotation
+func (mm *Minimap) TransformPoint(p rl.Vector2) rl.Vector2 {
+	if mm.angle == 0 {
+		return p
+	}
+	return rotateAround(p, mm.center(), mm.angle)
+}
+
+// center returns the minimap's center in screen pixels
+func (mm *Minimap) center() rl.Vector2 {
+	return rl.Vector2{
+		X: float32(mm.X) + float32(mm.Width)/2,
+		Y: float32(mm.Y) + float32(mm.Height)/2,
+	}
+}
+
+// rotateAround rotates p about center by angle radians
+func rotateAround(p, center rl.Vector2, angle float32) rl.Vector2 {
+	if angle == 0 {
+		return p
+	}
+	sin := float32(math.Sin(float64(angle)))
+	cos := float32(math.Cos(float64(angle)))
+	dx := p.X - center.X
+	dy := p.Y - center.Y
+	return rl.Vector2{
+		X: center.X + dx*cos - dy*sin,
+		Y: center.Y + dx*sin + dy*cos,
+	}
+}
+
 // drawViewport draws a rectangle showing the current camera view on the minimap
 func (mm *Minimap) drawViewport(tm *TileMap, camera *GameCamera, scaleX, scaleY float32) {
 	minX, minY, maxX, maxY := camera.GetVisibleTileRange(tm)
@@ -87,7 +187,24 @@ func (mm *Minimap) drawViewport(tm *TileMap, camera *GameCamera, scaleX, scaleY
 
 	// Draw viewport rectangle outline
 	viewportColor := rl.NewColor(255, 255, 255, 200)
-	rl.DrawRectangleLines(vpX, vpY, vpW, vpH, viewportColor)
+	if mm.angle == 0 {
+		rl.DrawRectangleLines(vpX, vpY, vpW, vpH, viewportColor)
+		return
+	}
+
+	// Rotated, the outline is drawn corner to corner
+	corners := [4]rl.Vector2{
+		{X: float32(vpX), Y: float32(vpY)},
+		{X: float32(vpX + vpW), Y: float32(vpY)},
+		{X: float32(vpX + vpW), Y: float32(vpY + vpH)},
+		{X: float32(vpX), Y: float32(vpY + vpH)},
+	}
+	for i := range corners {
+		corners[i] = mm.TransformPoint(corners[i])
+	}
+	for i := range corners {
+		rl.DrawLineV(corners[i], corners[(i+1)%len(corners)], viewportColor)
+	}
 }
 
 // RenderWithMarkers draws the minimap with additional markers (units, bases, etc.)
@@ -95,56 +212,87 @@ func (mm *Minimap) RenderWithMarkers(tm *TileMap, camera *GameCamera, markers []
 	// First render the base minimap
 	mm.Render(tm, camera)
 
-	// Calculate scale factors
-	scaleX := float32(mm.Width) / float32(tm.Width)
-	scaleY := float32(mm.Height) / float32(tm.Height)
+	grid, rect := tm.Grid(), mm.rect()
 
-	// Draw markers
+	// Draw markers, clipped like the terrain
+	rl.BeginScissorMode(mm.X, mm.Y, mm.Width, mm.Height)
+	defer rl.EndScissorMode()
 	for _, marker := range markers {
-		tileX, tileY := tm.WorldToTile(marker.WorldX, marker.WorldZ)
-
-		pixelX := mm.X + int32(float32(tileX)*scaleX)
-		pixelY := mm.Y + int32(float32(tileY)*scaleY)
+		pos := mm.TransformPoint(grid.WorldToScreen(marker.WorldX, marker.WorldZ, rect))
+		pixelX := int32(pos.X)
+		pixelY := int32(pos.Y)
+		size := mm.MarkerSize(marker.Type)
+		half := int32(size / 2)
 
 		switch marker.Type {
 		case MarkerUnit:
-			rl.DrawCircle(pixelX, pixelY, 3, marker.Color)
+			rl.DrawCircle(pixelX, pixelY, size/2, marker.Color)
+		case MarkerSupport:
+			// Hollow, so support units read as less of a threat
+			rl.DrawRectangleLines(pixelX-half, pixelY-half, int32(size), int32(size), marker.Color)
 		case MarkerBase:
-			rl.DrawRectangle(pixelX-3, pixelY-3, 6, 6, marker.Color)
+			rl.DrawRectangle(pixelX-half, pixelY-half, int32(size), int32(size), marker.Color)
+		case MarkerHQ:
+			rl.DrawRectangle(pixelX-half, pixelY-half, int32(size), int32(size), marker.Color)
+			rl.DrawRectangleLines(pixelX-half-1, pixelY-half-1, int32(size)+2, int32(size)+2, rl.White)
 		case MarkerObjective:
 			// Draw a diamond shape
 			rl.DrawTriangle(
-				rl.NewVector2(float32(pixelX), float32(pixelY-4)),
-				rl.NewVector2(float32(pixelX-4), float32(pixelY)),
-				rl.NewVector2(float32(pixelX+4), float32(pixelY)),
+				rl.NewVector2(float32(pixelX), float32(pixelY-half)),
+				rl.NewVector2(float32(pixelX-half), float32(pixelY)),
+				rl.NewVector2(float32(pixelX+half), float32(pixelY)),
 				marker.Color,
 			)
 			rl.DrawTriangle(
-				rl.NewVector2(float32(pixelX-4), float32(pixelY)),
-				rl.NewVector2(float32(pixelX), float32(pixelY+4)),
-				rl.NewVector2(float32(pixelX+4), float32(pixelY)),
+				rl.NewVector2(float32(pixelX-half), float32(pixelY)),
+				rl.NewVector2(float32(pixelX), float32(pixelY+half)),
+				rl.NewVector2(float32(pixelX+half), float32(pixelY)),
 				marker.Color,
 			)
 		case MarkerPlayer:
-			// Draw player indicator (triangle pointing up)
-			rl.DrawTriangle(
-				rl.NewVector2(float32(pixelX), float32(pixelY-5)),
-				rl.NewVector2(float32(pixelX-4), float32(pixelY+3)),
-				rl.NewVector2(float32(pixelX+4), float32(pixelY+3)),
-				marker.Color,
-			)
+			// Draw player indicator (triangle pointing up), outlined so it
+			// stands out over other blips
+			top := rl.NewVector2(float32(pixelX), float32(pixelY)-size*0.6)
+			left := rl.NewVector2(float32(pixelX)-size/2, float32(pixelY)+size*0.4)
+			right := rl.NewVector2(float32(pixelX)+size/2, float32(pixelY)+size*0.4)
+			rl.DrawTriangle(top, left, right, marker.Color)
+			rl.DrawTriangleLines(top, left, right, rl.Black)
 		}
 	}
 }
 
+// markerSizes are blip sizes in pixels on a 200 pixel wide minimap
+var markerSizes = map[MarkerType]float32{
+	MarkerUnit:      5,
+	MarkerSupport:   5,
+	MarkerBase:      6,
+	MarkerHQ:        9,
+	MarkerObjective: 8,
+	MarkerPlayer:    10,
+}
+
+// minMarkerSize keeps blips legible on small minimaps
+const minMarkerSize = 3
+
+// MarkerSize returns a blip's size in pixels, scaled with the minimap
+func (mm *Minimap) MarkerSize(t MarkerType) float32 {
+	size := markerSizes[t] * float32(mm.Width) / 200
+	if size < minMarkerSize {
+		return minMarkerSize
+	}
+	return size
+}
+
 // MarkerType defines different types of minimap markers
 type MarkerType int
 
 const (
-	MarkerUnit MarkerType = iota
+	MarkerUnit MarkerType = iota // Combat unit
 	MarkerBase
 	MarkerObjective
 	MarkerPlayer
+	MarkerSupport // Non-combat unit
+	MarkerHQ
 )
 
 // MinimapMarker represents an icon on the minimap
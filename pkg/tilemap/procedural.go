@@ -0,0 +1,148 @@
+package tilemap
+
+import (
+	"math/rand"
+)
+
+// Procedural terrain thresholds. Elevation and moisture are noise in [0, 1):
+// low ground floods, high ground is mountain, and damp ground in between
+// grows forest.
+const (
+	waterLevel    = 0.36
+	mountainLevel = 0.66
+	forestLevel   = 0.64
+
+	noiseScale  = 12 // Tiles between noise lattice points at the coarsest octave
+	noiseOctave = 3
+	townCount   = 4 // Points the road network links
+	cornerClear = 2 // Tiles around each corner kept clear ground
+)
+
+// GenerateProcedural builds a map from value noise: lakes where the land is
+// low, mountain ranges where it's high, and forest clumps on damp ground,
+// with a road network linking a few points across it. The same seed always
+// builds the same map. Opposite corners are always connected by passable
+// ground, roads being cut through wherever they wouldn't be.
+func GenerateProcedural(width, height int, seed int64) *TileMap {
+	rng := rand.New(rand.NewSource(seed))
+	tm := NewTileMap(width, height)
+
+	elevation := newValueNoise(rng, width, height)
+	moisture := newValueNoise(rng, width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			e, m := elevation.at(x, y), moisture.at(x, y)
+			switch {
+			case e < waterLevel:
+				tm.Tiles[y][x].Terrain = TerrainWater
+			case e > mountainLevel:
+				tm.Tiles[y][x].Terrain = TerrainMountain
+			case m > forestLevel:
+				tm.Tiles[y][x].Terrain = TerrainForest
+			}
+		}
+	}
+
+	// Roads link a chain of towns from one side of the map to the other
+	towns := make([][2]int, townCount)
+	for i := range towns {
+		towns[i] = [2]int{
+			(i*width + rng.Intn(width)) / townCount,
+			rng.Intn(height),
+		}
+	}
+	for i := 1; i < len(towns); i++ {
+		tm.layRoad(towns[i-1][0], towns[i-1][1], towns[i][0], towns[i][1])
+	}
+
+	// Corners are clear, and a road joins any pair the terrain cuts off
+	w, h := width-1, height-1
+	for _, c := range [4][2]int{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		tm.FillRect(c[0]-cornerClear, c[1]-cornerClear, c[0]+cornerClear, c[1]+cornerClear, TerrainGround)
+	}
+	if !tm.Connected(0, 0, w, h) {
+		tm.layRoad(0, 0, w, h)
+	}
+	if !tm.Connected(w, 0, 0, h) {
+		tm.layRoad(w, 0, 0, h)
+	}
+
+	return tm
+}
+
+// Connected returns true if passable ground links two tiles, moving only
+// between edge neighbours
+func (tm *TileMap) Connected(x1, y1, x2, y2 int) bool {
+	if !tm.InBounds(x2, y2) {
+		return false
+	}
+	return tm.reachableFrom(x1, y1)[y2*tm.Width+x2]
+}
+
+// valueNoise is fractal value noise over a map: random values on coarse
+// lattices, smoothly interpolated and summed over a few octaves
+type valueNoise struct {
+	octaves []noiseLattice
+}
+
+// noiseLattice is one octave of value noise
+type noiseLattice struct {
+	spacing       int
+	width, height int // Lattice points
+	values        []float32
+	weight        float32
+}
+
+// newValueNoise draws noise covering a width by height map from rng
+func newValueNoise(rng *rand.Rand, width, height int) *valueNoise {
+	n := &valueNoise{}
+	spacing, weight, total := noiseScale, float32(1), float32(0)
+	for i := 0; i < noiseOctave && spacing >= 1; i++ {
+		l := noiseLattice{
+			spacing: spacing,
+			width:   width/spacing + 2,
+			height:  height/spacing + 2,
+			weight:  weight,
+		}
+		l.values = make([]float32, l.width*l.height)
+		for j := range l.values {
+			l.values[j] = rng.Float32()
+		}
+		n.octaves = append(n.octaves, l)
+		total += weight
+		spacing /= 2
+		weight /= 2
+	}
+
+	// Normalize so the octaves sum to [0, 1)
+	for i := range n.octaves {
+		n.octaves[i].weight /= total
+	}
+	return n
+}
+
+// at returns the noise at a tile, in [0, 1)
+func (n *valueNoise) at(x, y int) float32 {
+	var sum float32
+	for _, l := range n.octaves {
+		sum += l.at(x, y) * l.weight
+	}
+	return sum
+}
+
+// at interpolates the lattice at a tile
+func (l *noiseLattice) at(x, y int) float32 {
+	cx, cy := x/l.spacing, y/l.spacing
+	tx := smoothstep(float32(x%l.spacing) / float32(l.spacing))
+	ty := smoothstep(float32(y%l.spacing) / float32(l.spacing))
+
+	v := func(i, j int) float32 { return l.values[j*l.width+i] }
+	top := v(cx, cy) + (v(cx+1, cy)-v(cx, cy))*tx
+	bottom := v(cx, cy+1) + (v(cx+1, cy+1)-v(cx, cy+1))*tx
+	return top + (bottom-top)*ty
+}
+
+// smoothstep eases t in [0, 1] so lattice cells blend without creases
+func smoothstep(t float32) float32 {
+	return t * t * (3 - 2*t)
+}
@@ -0,0 +1,43 @@
+package tilemap
+
+import "testing"
+
+func TestProceduralSameSeedSameMap(t *testing.T) {
+	a := GenerateProcedural(64, 48, 99)
+	b := GenerateProcedural(64, 48, 99)
+	for y := range a.Tiles {
+		for x := range a.Tiles[y] {
+			if a.Tiles[y][x].Terrain != b.Tiles[y][x].Terrain {
+				t.Fatalf("tile (%d, %d) differs between runs with the same seed", x, y)
+			}
+		}
+	}
+}
+
+func TestProceduralCornersConnected(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		tm := GenerateProcedural(64, 48, seed)
+		w, h := tm.Width-1, tm.Height-1
+		if !tm.Connected(0, 0, w, h) || !tm.Connected(w, 0, 0, h) {
+			t.Errorf("seed %d: opposite corners aren't connected", seed)
+		}
+	}
+}
+
+func TestSeededBuiltinsLoad(t *testing.T) {
+	for name := range seededMaps {
+		b, err := LoadBuiltin(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		again, _ := LoadBuiltin(name)
+		for y := range b.Map.Tiles {
+			for x := range b.Map.Tiles[y] {
+				if b.Map.Tiles[y][x].Terrain != again.Map.Tiles[y][x].Terrain {
+					t.Fatalf("%s: tile (%d, %d) differs between loads", name, x, y)
+				}
+			}
+		}
+	}
+}
@@ -23,6 +23,10 @@ type TerrainInfo struct {
 	Flyable    bool    // Can air units fly over this?
 	SpeedMod   float32 // Movement speed modifier (1.0 = normal)
 	DefenseMod float32 // Defense bonus modifier (1.0 = normal)
+
+	// Fraction of their usual range at which enemies spot a unit standing
+	// here (1.0 = normal, lower = concealed)
+	DetectionMod float32
 }
 
 // TerrainRegistry maps terrain types to their info
@@ -36,6 +40,8 @@ var TerrainRegistry = map[TerrainType]TerrainInfo{
 		Flyable:    true,
 		SpeedMod:   1.0,
 		DefenseMod: 1.0,
+
+		DetectionMod: 1.0,
 	},
 	TerrainWater: {
 		Type:       TerrainWater,
@@ -46,6 +52,8 @@ var TerrainRegistry = map[TerrainType]TerrainInfo{
 		Flyable:    true,
 		SpeedMod:   0.0,
 		DefenseMod: 0.0,
+
+		DetectionMod: 1.0,
 	},
 	TerrainMountain: {
 		Type:       TerrainMountain,
@@ -56,6 +64,8 @@ var TerrainRegistry = map[TerrainType]TerrainInfo{
 		Flyable:    false,
 		SpeedMod:   0.0,
 		DefenseMod: 0.0,
+
+		DetectionMod: 1.0,
 	},
 	TerrainForest: {
 		Type:       TerrainForest,
@@ -66,6 +76,8 @@ var TerrainRegistry = map[TerrainType]TerrainInfo{
 		Flyable:    true,
 		SpeedMod:   0.6,
 		DefenseMod: 1.3,
+
+		DetectionMod: 0.5,
 	},
 	TerrainRoad: {
 		Type:       TerrainRoad,
@@ -76,6 +88,8 @@ var TerrainRegistry = map[TerrainType]TerrainInfo{
 		Flyable:    true,
 		SpeedMod:   1.5,
 		DefenseMod: 0.8,
+
+		DetectionMod: 1.0,
 	},
 }
 
@@ -92,6 +106,11 @@ func (t TerrainType) IsPassable() bool {
 	return GetTerrainInfo(t).Passable
 }
 
+// DetectionMod returns how visible units standing on a terrain type are
+func (t TerrainType) DetectionMod() float32 {
+	return GetTerrainInfo(t).DetectionMod
+}
+
 // IsFlyable checks if a terrain type can be flown over
 func (t TerrainType) IsFlyable() bool {
 	return GetTerrainInfo(t).Flyable
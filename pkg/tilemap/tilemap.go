@@ -2,6 +2,8 @@ package tilemap
 
 import (
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/coords"
 )
 
 const (
@@ -61,18 +63,20 @@ func (tm *TileMap) SetTerrain(x, y int, terrain TerrainType) {
 	}
 }
 
+// Grid returns the map's tile grid, its first tile's corner at the world
+// origin
+func (tm *TileMap) Grid() coords.Grid {
+	return coords.NewGrid(tm.Width, tm.Height, tm.TileSize)
+}
+
 // WorldToTile converts world coordinates to tile coordinates
 func (tm *TileMap) WorldToTile(worldX, worldZ float32) (int, int) {
-	tileX := int(worldX / tm.TileSize)
-	tileY := int(worldZ / tm.TileSize)
-	return tileX, tileY
+	return tm.Grid().WorldToCell(worldX, worldZ)
 }
 
 // TileToWorld converts tile coordinates to world coordinates (center of tile)
 func (tm *TileMap) TileToWorld(tileX, tileY int) (float32, float32) {
-	worldX := (float32(tileX) + 0.5) * tm.TileSize
-	worldZ := (float32(tileY) + 0.5) * tm.TileSize
-	return worldX, worldZ
+	return tm.Grid().CellToWorld(tileX, tileY)
 }
 
 // GetTerrainAt returns the terrain type at world coordinates
@@ -103,6 +107,69 @@ func (tm *TileMap) IsFlyableAt(worldX, worldZ float32) bool {
 	return terrain.IsFlyable()
 }
 
+// DetectionModAt returns how visible a unit standing at the given world
+// position is, 1 in the open and less in cover
+func (tm *TileMap) DetectionModAt(worldX, worldZ float32) float32 {
+	return tm.GetTerrainAt(worldX, worldZ).DetectionMod()
+}
+
+// NearestPassable returns the center of the closest passable tile to a world
+// position, searching outward up to maxRadius tiles.
+// Returns false if no passable tile was found.
+func (tm *TileMap) NearestPassable(worldX, worldZ float32, maxRadius int) (float32, float32, bool) {
+	return tm.NearestFree(worldX, worldZ, maxRadius, func(_, _ float32, terrain TerrainType) bool {
+		return terrain.IsPassable()
+	})
+}
+
+// NearestFree returns the closest spot to a world position that fits accepts.
+// The exact position is kept if it fits, otherwise tile centers are tried in
+// rings outward up to maxRadius tiles.
+// Returns false (and the original position) if nothing fits.
+func (tm *TileMap) NearestFree(worldX, worldZ float32, maxRadius int, fits func(x, z float32, terrain TerrainType) bool) (float32, float32, bool) {
+	cx, cy := tm.WorldToTile(worldX, worldZ)
+
+	// Already standing on good ground, keep the exact position
+	if tile := tm.GetTile(cx, cy); tile != nil && fits(worldX, worldZ, tile.Terrain) {
+		return worldX, worldZ, true
+	}
+
+	for r := 0; r <= maxRadius; r++ {
+		bestDist := float32(-1)
+		var bestX, bestZ float32
+
+		// Check every tile on the ring at distance r
+		for y := cy - r; y <= cy+r; y++ {
+			for x := cx - r; x <= cx+r; x++ {
+				if x != cx-r && x != cx+r && y != cy-r && y != cy+r {
+					continue // Interior tiles were covered by smaller rings
+				}
+				tile := tm.GetTile(x, y)
+				if tile == nil {
+					continue
+				}
+				tx, tz := tm.TileToWorld(x, y)
+				if !fits(tx, tz, tile.Terrain) {
+					continue
+				}
+				dx := tx - worldX
+				dz := tz - worldZ
+				dist := dx*dx + dz*dz
+				if bestDist < 0 || dist < bestDist {
+					bestDist = dist
+					bestX, bestZ = tx, tz
+				}
+			}
+		}
+
+		if bestDist >= 0 {
+			return bestX, bestZ, true
+		}
+	}
+
+	return worldX, worldZ, false
+}
+
 // GetWorldBounds returns the world-space bounds of the map
 func (tm *TileMap) GetWorldBounds() rl.BoundingBox {
 	return rl.BoundingBox{
@@ -113,6 +180,16 @@ func (tm *TileMap) GetWorldBounds() rl.BoundingBox {
 
 // Render draws the tile map in 3D
 func (tm *TileMap) Render() {
+	tm.RenderWithFog(nil)
+}
+
+// unseenColor covers ground a team has never seen
+var unseenColor = rl.NewColor(8, 8, 10, 255)
+
+// RenderWithFog draws the tile map as a team knows it: ground in sight as
+// usual, remembered ground dimmed, and unseen ground as a flat dark slab
+// with nothing on it. A nil fog draws everything.
+func (tm *TileMap) RenderWithFog(fog *FogOfWar) {
 	for y := 0; y < tm.Height; y++ {
 		for x := 0; x < tm.Width; x++ {
 			tile := tm.Tiles[y][x]
@@ -120,6 +197,15 @@ func (tm *TileMap) Render() {
 
 			worldX, worldZ := tm.TileToWorld(x, y)
 
+			if _, seen := fog.Shade(info.Color, x, y); !seen {
+				rl.DrawCube(rl.NewVector3(worldX, 0.05, worldZ), tm.TileSize, 0.1, tm.TileSize, unseenColor)
+				continue
+			}
+			shade := func(c rl.Color) rl.Color {
+				c, _ = fog.Shade(c, x, y)
+				return c
+			}
+
 			// Draw tile as a cube with appropriate height
 			tileHeight := info.Height
 			if tileHeight < 0.1 {
@@ -129,25 +215,25 @@ func (tm *TileMap) Render() {
 			pos := rl.NewVector3(worldX, info.Height/2, worldZ)
 			size := rl.NewVector3(tm.TileSize*0.98, tileHeight, tm.TileSize*0.98)
 
-			rl.DrawCubeV(pos, size, info.Color)
+			rl.DrawCubeV(pos, size, shade(info.Color))
 
 			// Draw water with transparency effect
 			if tile.Terrain == TerrainWater {
 				waterColor := rl.NewColor(64, 164, 223, 180)
-				rl.DrawCubeV(pos, size, waterColor)
+				rl.DrawCubeV(pos, size, shade(waterColor))
 			}
 
 			// Draw mountain peaks
 			if tile.Terrain == TerrainMountain {
 				peakPos := rl.NewVector3(worldX, info.Height, worldZ)
-				rl.DrawCube(peakPos, tm.TileSize*0.4, 0.5, tm.TileSize*0.4, rl.DarkGray)
+				rl.DrawCube(peakPos, tm.TileSize*0.4, 0.5, tm.TileSize*0.4, shade(rl.DarkGray))
 			}
 
 			// Draw trees for forest
 			if tile.Terrain == TerrainForest {
 				treePos := rl.NewVector3(worldX, info.Height+0.3, worldZ)
-				rl.DrawCube(treePos, 0.2, 0.6, 0.2, rl.Brown)
-				rl.DrawSphere(rl.NewVector3(worldX, info.Height+0.7, worldZ), 0.3, rl.DarkGreen)
+				rl.DrawCube(treePos, 0.2, 0.6, 0.2, shade(rl.Brown))
+				rl.DrawSphere(rl.NewVector3(worldX, info.Height+0.7, worldZ), 0.3, shade(rl.DarkGreen))
 			}
 		}
 	}
@@ -180,10 +266,10 @@ func GenerateTestMap(width, height int) *TileMap {
 	tm.FillRect(5, 5, 8, 8, TerrainForest)
 	tm.FillRect(width-10, height-10, width-6, height-6, TerrainForest)
 
-	// Add a road
+	// Add a road, fording the river so both banks connect
 	roadY := height / 2
 	for x := 0; x < width; x++ {
-		if tm.GetTile(x, roadY).Terrain == TerrainGround {
+		if t := tm.GetTile(x, roadY).Terrain; t == TerrainGround || t == TerrainWater {
 			tm.SetTerrain(x, roadY, TerrainRoad)
 		}
 	}
@@ -0,0 +1,66 @@
+package unit
+
+// Alliances groups teams that fight on the same side. Allied teams don't
+// target or damage each other, and can optionally share vision and credits.
+// A nil *Alliances treats every team as its own side.
+type Alliances struct {
+	group     map[Team]int // Teams with the same group are allied
+	nextGroup int
+
+	SharedVision  bool // Allies see what each other's units see
+	SharedCredits bool // Allies spend from one credit pool
+}
+
+// NewAlliances creates an alliance table where every team stands alone
+func NewAlliances() *Alliances {
+	return &Alliances{group: make(map[Team]int)}
+}
+
+// Ally puts two teams on the same side, merging their existing alliances
+func (a *Alliances) Ally(x, y Team) {
+	gx, gy := a.groupOf(x), a.groupOf(y)
+	for team, g := range a.group {
+		if g == gy {
+			a.group[team] = gx
+		}
+	}
+	a.group[x] = gx
+	a.group[y] = gx
+}
+
+// Clear dissolves all alliances
+func (a *Alliances) Clear() {
+	a.group = make(map[Team]int)
+}
+
+// AreAllied returns true if two teams are on the same side.
+// A team is always allied with itself.
+func (a *Alliances) AreAllied(x, y Team) bool {
+	if x == y {
+		return true
+	}
+	if a == nil {
+		return false
+	}
+	gx, okX := a.group[x]
+	gy, okY := a.group[y]
+	return okX && okY && gx == gy
+}
+
+// SharesVision returns true if viewer can see through other's units
+func (a *Alliances) SharesVision(viewer, other Team) bool {
+	if viewer == other {
+		return true
+	}
+	return a != nil && a.SharedVision && a.AreAllied(viewer, other)
+}
+
+// groupOf returns the team's group, giving it a fresh one if it has none
+func (a *Alliances) groupOf(t Team) int {
+	if g, ok := a.group[t]; ok {
+		return g
+	}
+	a.nextGroup++
+	a.group[t] = a.nextGroup
+	return a.nextGroup
+}
@@ -0,0 +1,51 @@
+package unit
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// EventType is what happened to a unit
+type EventType int
+
+const (
+	EventSpawned EventType = iota // Unit was created
+	EventDied                     // Dead unit was removed
+)
+
+// String returns the display name for an event type
+func (t EventType) String() string {
+	switch t {
+	case EventSpawned:
+		return "Spawned"
+	case EventDied:
+		return "Died"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes a unit spawning or dying
+type Event struct {
+	Type     EventType
+	UnitID   uint32
+	UnitType UnitType
+	Team     Team
+	Position rl.Vector3
+}
+
+// EventHandler receives unit events as they happen
+type EventHandler func(e Event)
+
+// Subscribe registers a handler called for every unit event. Handlers stay
+// registered across Reset.
+func (m *Manager) Subscribe(handler EventHandler) {
+	m.handlers = append(m.handlers, handler)
+}
+
+// emit sends an event about a unit to every handler
+func (m *Manager) emit(t EventType, u *Unit) {
+	e := Event{Type: t, UnitID: u.ID, UnitType: u.Config.Type, Team: u.Team, Position: u.Position}
+	for _, h := range m.handlers {
+		h(e)
+	}
+}
@@ -0,0 +1,35 @@
+package unit
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSpawnAndDeathEachFireOnce(t *testing.T) {
+	m := NewManager(10)
+	var events []Event
+	m.Subscribe(func(e Event) { events = append(events, e) })
+
+	pos := rl.NewVector3(3, 0, 4)
+	u := m.Spawn(TypeTank, TeamEnemy, pos)
+	want := Event{Type: EventSpawned, UnitID: u.ID, UnitType: TypeTank, Team: TeamEnemy, Position: pos}
+	if len(events) != 1 || events[0] != want {
+		t.Fatalf("after a spawn got %+v, want [%+v]", events, want)
+	}
+
+	u.SpawnTimer = 0
+	u.State = StateIdle
+	u.TakeDamage(u.MaxHealth * 10)
+	for i := 0; i < 120; i++ {
+		m.Update(1.0 / 60)
+	}
+	want.Type = EventDied
+	want.Position = u.Position
+	if len(events) != 2 || events[1] != want {
+		t.Fatalf("after the death got %+v, want a second event %+v", events, want)
+	}
+	if len(m.GetUnits()) != 0 {
+		t.Errorf("%d units left after the only one died", len(m.GetUnits()))
+	}
+}
@@ -0,0 +1,17 @@
+package unit
+
+import "math"
+
+// HitPoints rounds damage or healing to whole hit points so health stays a
+// whole number and a lethal hit leaves exactly zero. Any positive amount is
+// worth at least one point, so armor can soften a hit but never cancel it.
+func HitPoints(amount float32) float32 {
+	if amount <= 0 {
+		return 0
+	}
+	hp := float32(math.Round(float64(amount)))
+	if hp < 1 {
+		return 1
+	}
+	return hp
+}
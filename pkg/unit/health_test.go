@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestHitPointsRounding(t *testing.T) {
+	tests := []struct{ amount, want float32 }{
+		{-3, 0}, {0, 0}, {0.0001, 1}, {0.6, 1}, {1.4, 1}, {3.5, 4}, {10, 10},
+	}
+	for _, tt := range tests {
+		if got := HitPoints(tt.amount); got != tt.want {
+			t.Errorf("HitPoints(%v) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestLethalDamageThroughArmorLeavesZero(t *testing.T) {
+	u := New(1, TypeTank, TeamEnemy, rl.NewVector3(0, 0, 0))
+	if u.Config.Armor == 0 {
+		t.Fatal("tank has no armor to round through")
+	}
+
+	// Many small infantry hits, each softened by armor to a fraction
+	for i := 0; i < 1000 && !u.IsDead(); i++ {
+		u.TakeDamage(5)
+		if u.Health != float32(math.Round(float64(u.Health))) {
+			t.Fatalf("health %v after hit %d isn't whole", u.Health, i+1)
+		}
+	}
+	if u.Health != 0 || !u.IsDead() {
+		t.Errorf("after lethal damage: %v health, dead %v", u.Health, u.IsDead())
+	}
+
+	// One overkill hit lands on zero too
+	u = New(2, TypeTank, TeamEnemy, rl.NewVector3(0, 0, 0))
+	u.TakeDamage(u.MaxHealth * 5)
+	if u.Health != 0 || !u.IsDead() {
+		t.Errorf("after overkill: %v health, dead %v", u.Health, u.IsDead())
+	}
+}
@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"fmt"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
+)
+
+// InspectStat is one extra labelled value shown when inspecting something
+type InspectStat struct {
+	Label string
+	Value string
+}
+
+// InspectInfo is what the UI shows about a unit or base, already formatted
+// for display so the UI needn't reach into the entity itself
+type InspectInfo struct {
+	Name      string
+	Side      string // Team or owner
+	Health    float32
+	MaxHealth float32
+	Order     string // Current order or stance, empty if none
+	Stats     []InspectStat
+
+	// An enemy out of sight, only its name and side are known
+	Redacted bool
+}
+
+// Redact returns the subset of the info the player knows about an enemy
+// they can't see
+func (i InspectInfo) Redact() InspectInfo {
+	return InspectInfo{Name: i.Name, Side: i.Side, Redacted: true}
+}
+
+// Lines returns the info as lines of display text
+func (i InspectInfo) Lines() []string {
+	lines := []string{fmt.Sprintf("%s (%s)", i.Name, i.Side)}
+	if i.Redacted {
+		return append(lines, "HP: ?")
+	}
+	lines = append(lines, fmt.Sprintf("HP: %.0f/%.0f", i.Health, i.MaxHealth))
+	if i.Order != "" {
+		lines = append(lines, i.Order)
+	}
+	for _, s := range i.Stats {
+		lines = append(lines, s.Label+": "+s.Value)
+	}
+	return lines
+}
+
+// Inspect returns the unit's display info
+func (u *Unit) Inspect() InspectInfo {
+	info := InspectInfo{
+		Name:      u.Config.Type.String(),
+		Side:      u.Team.String(),
+		Health:    u.Health,
+		MaxHealth: u.MaxHealth,
+		Stats: []InspectStat{
+			{Label: "Rank", Value: u.Rank().String()},
+			{Label: "Kills", Value: fmt.Sprint(u.Kills)},
+			{Label: "Damage", Value: fmt.Sprintf("%.0f", u.Config.AttackDamage)},
+			{Label: "Range", Value: fmt.Sprintf("%.1f", u.Config.AttackRange)},
+			{Label: "Armor", Value: fmt.Sprintf("%.0f%%", u.Config.Armor*100)},
+		},
+	}
+	if u.Order != OrderNone {
+		info.Order = "Order: " + u.GetOrderName()
+	}
+	return info
+}
+
+// InspectAs returns the unit's display info as a team sees it: enemies
+// that team can't see are redacted
+func (u *Unit) InspectAs(viewer Team, vis graphics.Visibility) InspectInfo {
+	info := u.Inspect()
+	if u.Team != viewer && !graphics.IsVisible(vis, u.Position) {
+		return info.Redact()
+	}
+	return info
+}
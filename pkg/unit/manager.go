@@ -1,28 +1,121 @@
 package unit
 
 import (
+	"math"
+	"math/rand"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/profile"
 )
 
-// Manager handles unit spawning, updates, and cleanup
+// Manager handles unit spawning, updates, and cleanup.
+//
+// Units are kept in ascending ID order: spawns append with increasing IDs
+// and cleanup preserves order. Every query walks that order, so results and
+// nearest-unit tie breaks are the same across runs and machines.
 type Manager struct {
 	units    []*Unit
 	nextID   uint32
 	maxUnits int
 
+	// Simulation randomness, reproducible from the seed
+	seed int64
+	rng  *rand.Rand
+
 	// Pathfinder reference (set externally)
 	Pathfinder *Pathfinder
+
+	// Queued path searches, at most PathBudget run each frame (0 = no limit)
+	PathBudget   int
+	pathRequests []pathRequest
+
+	// Queued spawns, at most SpawnBudget enter the world each frame (0 = no
+	// limit)
+	SpawnBudget     int
+	spawnRequests   []SpawnRequest
+	spawnsThisFrame int
+
+	// Frame timing for the update phases (set externally, nil = off)
+	Profiler *profile.Profiler
+
+	// Room each unit keeps to itself, overlapping units are pushed apart
+	// (0 = units may stack)
+	UnitRadius float32
+
+	// Units bucketed by position for neighbour queries
+	grid *SpatialGrid
+
+	// Alliance table consulted for targeting (set externally, nil = no alliances)
+	Alliances *Alliances
+
+	// How visible a unit at a world position is, 1 in the open and less in
+	// cover (set externally, nil = open everywhere)
+	DetectionAt func(x, z float32) float32
+
+	// Per-team health handicaps applied at spawn
+	healthMultipliers map[Team]float32
+
+	// Projectiles fired during the last Update
+	shots []Shot
+
+	// Subscribers to spawn and death events
+	handlers []EventHandler
+
+	// Player mech, guardable with MechID (set externally each frame)
+	mechPosition rl.Vector3
+	mechAlive    bool
+
+	// Army summaries computed since the last Update
+	summaries map[Team]ArmySummary
+}
+
+// pathRequest is a unit waiting for a path search
+type pathRequest struct {
+	unit   *Unit
+	goal   rl.Vector3
+	player bool // Issued by the player, served before AI requests
 }
 
+// DefaultPathBudget is how many path searches run per frame by default
+const DefaultPathBudget = 8
+
+// DefaultSpawnBudget is how many units spawn per frame by default
+const DefaultSpawnBudget = 4
+
+// DefaultSeed seeds simulation randomness until Seed is called
+const DefaultSeed = 1
+
 // NewManager creates a new unit manager
 func NewManager(maxUnits int) *Manager {
 	return &Manager{
-		units:    make([]*Unit, 0, maxUnits),
-		nextID:   1,
-		maxUnits: maxUnits,
+		units:       make([]*Unit, 0, maxUnits),
+		nextID:      1,
+		maxUnits:    maxUnits,
+		PathBudget:  DefaultPathBudget,
+		SpawnBudget: DefaultSpawnBudget,
+		UnitRadius:  DefaultUnitRadius,
+		grid:        NewSpatialGrid(DefaultGridCellSize),
+		seed:        DefaultSeed,
+		rng:         rand.New(rand.NewSource(DefaultSeed)),
+
+		healthMultipliers: make(map[Team]float32),
+		summaries:         make(map[Team]ArmySummary),
 	}
 }
 
+// Seed restarts simulation randomness (patrol wandering and the like) from
+// a seed. Two matches seeded the same play out the same given the same input.
+func (m *Manager) Seed(seed int64) {
+	m.seed = seed
+	m.rng.Seed(seed)
+}
+
+// SetHealthMultiplier sets the health handicap for units spawned on a team
+func (m *Manager) SetHealthMultiplier(team Team, mult float32) {
+	m.healthMultipliers[team] = mult
+}
+
 // Spawn creates a new unit at the given position
 func (m *Manager) Spawn(unitType UnitType, team Team, pos rl.Vector3) *Unit {
 	if len(m.units) >= m.maxUnits {
@@ -30,8 +123,16 @@ func (m *Manager) Spawn(unitType UnitType, team Team, pos rl.Vector3) *Unit {
 	}
 
 	u := New(m.nextID, unitType, team, pos)
+	u.alliances = m.Alliances
+	u.rng = m.rng
+	u.BeginSpawn(SpawnDuration)
 	m.nextID++
+	if mult, ok := m.healthMultipliers[team]; ok {
+		u.ScaleHealth(mult)
+	}
 	m.units = append(m.units, u)
+	m.grid.Insert(u)
+	m.emit(EventSpawned, u)
 	return u
 }
 
@@ -40,40 +141,137 @@ func (m *Manager) SpawnWithObjective(unitType UnitType, team Team, pos, objectiv
 	u := m.Spawn(unitType, team, pos)
 	if u != nil {
 		u.SetObjective(objective)
-		// Try to pathfind if available
-		if m.Pathfinder != nil {
-			path := m.Pathfinder.FindPath(
-				rl.Vector2{X: pos.X, Y: pos.Z},
-				rl.Vector2{X: objective.X, Y: objective.Z},
-			)
-			if path != nil {
-				u.SetPath(path)
-			}
-		}
+		m.RequestPath(u, objective)
 	}
 	return u
 }
 
+// SetMech records where the player's mech is so units can guard it
+func (m *Manager) SetMech(pos rl.Vector3, alive bool) {
+	m.mechPosition = pos
+	m.mechAlive = alive
+}
+
 // Update updates all units
 func (m *Manager) Update(dt float32) {
+	clear(m.summaries)
+	m.shots = m.shots[:0]
+
+	// Paused: spawns, paths, targeting, and cooldowns all wait
+	if dt <= 0 {
+		return
+	}
+
+	// Bring in this frame's share of queued spawns
+	m.ProcessSpawnRequests()
+
+	stop := m.Profiler.Start(profile.SectionUnits)
+	// Move guard stations along with their wards
+	m.updateGuards()
+
 	for _, u := range m.units {
 		u.Update(dt)
 	}
+	m.grid.Rebuild(m.units)
+	if m.separate() {
+		m.grid.Rebuild(m.units)
+	}
+	stop()
+
+	// Repath units that got nudged loose, then run this frame's share of
+	// path searches
+	m.repathStuck()
+	m.ProcessPathRequests(m.PathBudget)
 
 	// Run AI for all units
+	stop = m.Profiler.Start(profile.SectionAI)
 	m.updateAI(dt)
+	stop()
 
 	// Run combat for all units
+	stop = m.Profiler.Start(profile.SectionCombat)
 	m.updateCombat(dt)
+	stop()
+
+	// Supply units patch up whoever's nearby
+	m.updateSupply(dt)
+
+	// Hand what was fired to the combat system
+	m.collectShots()
 
 	// Cleanup dead units
 	m.cleanup()
 }
 
+// repathStuck recomputes paths for units flagged as stuck
+func (m *Manager) repathStuck() {
+	for _, u := range m.units {
+		if !u.NeedsRepath {
+			continue
+		}
+		u.NeedsRepath = false
+		if u.HasObjective {
+			m.RequestPath(u, u.Objective)
+		}
+	}
+}
+
+// updateGuards keeps guards following their wards. A guard whose ward has
+// died holds its current spot instead.
+func (m *Manager) updateGuards() {
+	for _, u := range m.units {
+		if u.Order != OrderGuard || u.IsDead() || u.IsCarried() || u.IsSpawning() {
+			continue
+		}
+		wardPos, ok := m.wardPosition(u.GuardTargetID)
+		if !ok {
+			u.SetOrder(OrderDefendPosition, u.Position)
+			continue
+		}
+		u.followWard(wardPos)
+	}
+}
+
+// wardPosition returns where a guarded unit or the mech is, false if it's dead
+func (m *Manager) wardPosition(id uint32) (rl.Vector3, bool) {
+	if id == MechID {
+		return m.mechPosition, m.mechAlive
+	}
+	ward := m.GetUnitByID(id)
+	if ward == nil || ward.IsDead() {
+		return rl.Vector3{}, false
+	}
+	return ward.Position, true
+}
+
+// nearestThreat returns the enemy closest to a guard's ward that the guard
+// can attack, nil if none is within the threat radius
+func (m *Manager) nearestThreat(guard *Unit) *Unit {
+	var nearest *Unit
+	nearestDist := float32(guardThreatRadius)
+	for _, other := range m.QueryRadius(guard.wardPosition, guardThreatRadius) {
+		if !guard.CanAttack(other) {
+			continue
+		}
+		if dist := other.DistanceToPoint(guard.wardPosition); dist <= nearestDist {
+			nearest = other
+			nearestDist = dist
+		}
+	}
+	return nearest
+}
+
 // updateAI handles basic AI behaviors for all units
 func (m *Manager) updateAI(dt float32) {
+	var nearby []*Unit
 	for _, u := range m.units {
-		if u.IsDead() || u.IsCarried() {
+		if u.IsDead() || u.IsCarried() || u.IsSpawning() {
+			continue
+		}
+
+		// Chasing too far from an interrupted order abandons the fight
+		if u.pastLeash() {
+			u.giveUpChase()
 			continue
 		}
 
@@ -82,54 +280,91 @@ func (m *Manager) updateAI(dt float32) {
 			continue
 		}
 
-		// Find nearest enemy to attack
+		// Units on a plain move order don't stop to fight on the way
+		if u.ForceMove {
+			if u.HasObjective {
+				u.Target = nil
+				continue
+			}
+			u.ForceMove = false
+		}
+
+		// Guards only go after what threatens their ward
+		if u.Order == OrderGuard {
+			u.Target = m.nearestThreat(u)
+			continue
+		}
+
+		// Find nearest enemy to attack. Concealed enemies count as further
+		// away, so they're only spotted at a fraction of the usual range,
+		// and nothing further than aggro range is ever picked.
+		aggroRange := u.Config.AttackRange * 2
 		var nearest *Unit
 		nearestDist := float32(1000000)
 
-		for _, other := range m.units {
+		nearby = m.grid.QueryRadius(u.Position, aggroRange, nearby[:0])
+		for _, other := range nearby {
 			if other == u || other.IsDead() {
 				continue
 			}
-			if other.Team == u.Team {
-				continue
-			}
 			if !u.CanAttack(other) {
 				continue
 			}
 
-			dist := u.DistanceTo(other)
-			if dist < nearestDist {
+			dist := u.DistanceTo(other) / m.Detectability(other)
+			if closer(other, dist, nearest, nearestDist) {
 				nearest = other
 				nearestDist = dist
 			}
 		}
 
 		// Set target if enemy found within aggro range
-		aggroRange := u.Config.AttackRange * 2
+		// Orders are set aside while fighting and picked up again once
+		// nothing is left in reach
 		if nearest != nil && nearestDist <= aggroRange {
 			u.Target = nearest
+			u.interruptOrder()
 		} else {
 			u.Target = nil
+			u.resumeOrder()
 		}
 	}
 }
 
+// minDetectability keeps the deepest cover from hiding a unit outright
+const minDetectability = 0.1
+
+// Detectability returns how easily enemies spot a unit, 1 in the open and
+// less in cover. A unit that has just fired gives itself away.
+func (m *Manager) Detectability(u *Unit) float32 {
+	if m.DetectionAt == nil || u.IsRevealed() {
+		return 1
+	}
+	if d := m.DetectionAt(u.Position.X, u.Position.Z); d > minDetectability {
+		return d
+	}
+	return minDetectability
+}
+
 // updateCombat handles unit attacking
 func (m *Manager) updateCombat(dt float32) {
 	for _, u := range m.units {
-		if u.IsDead() || u.Target == nil {
+		u.holdPosition = false
+		u.backingOff = false
+		if u.IsDead() || u.IsSpawning() || u.Target == nil {
 			continue
 		}
 
 		// Check if target is still valid
-		if u.Target.IsDead() {
+		if !u.Target.IsTargetable() {
 			u.Target = nil
 			u.State = StateIdle
 			continue
 		}
 
 		// Check if in range
-		if !u.IsInRange(u.Target) {
+		dist := u.DistanceTo(u.Target)
+		if dist > u.Config.AttackRange {
 			// Move toward target
 			if !u.HasObjective {
 				u.SetObjective(u.Target.Position)
@@ -137,25 +372,74 @@ func (m *Manager) updateCombat(dt float32) {
 			continue
 		}
 
+		// Stop at the engagement distance rather than closing to point
+		// blank, and back off a target that gets inside the standoff.
+		// Units under orders keep to their orders' movement.
+		if u.Order == OrderNone {
+			u.backingOff = dist < u.Config.StandoffDistance
+			u.holdPosition = dist <= u.EngageDistance()
+		}
+
+		// Aim where the target will be when the shot arrives
+		u.AimPoint = ComputeLeadPoint(u.Position, u.Target.Position, u.Target.Velocity, u.Config.ProjectileSpeed)
+		u.Rotation = lerpAngle(u.Rotation, u.angleTo(u.AimPoint), u.Config.TurnSpeed*dt)
+
 		// Attack if cooldown ready
 		if u.AttackCooldown <= 0 {
-			u.State = StateAttacking
-			u.Target.TakeDamage(u.Config.AttackDamage)
-			u.AttackCooldown = 1.0 / u.Config.AttackRate
+			u.attack(u.Target)
+		}
+	}
+}
+
+// supplyStopSpeed is how slow a supply unit must be going to hand out repairs
+const supplyStopSpeed = 0.1
+
+// updateSupply has each stopped supply unit heal damaged friendly units
+// within its radius. Healing builds up and is given in whole hit points,
+// every unit in reach getting the same. A supply unit never heals itself.
+func (m *Manager) updateSupply(dt float32) {
+	for _, s := range m.units {
+		if s.Config.SupplyRadius <= 0 || s.Config.SupplyHealRate <= 0 {
+			continue
+		}
+		if s.IsDead() || s.IsCarried() || s.IsSpawning() || s.Speed() > supplyStopSpeed {
+			s.supplyCharge = 0
+			continue
+		}
+
+		s.supplyCharge += s.Config.SupplyHealRate * dt
+		if s.supplyCharge < 1 {
+			continue
+		}
+		amount := float32(math.Floor(float64(s.supplyCharge)))
+		s.supplyCharge -= amount
+
+		for _, u := range m.units {
+			if u == s || u.IsDead() || u.IsCarried() || u.Health >= u.MaxHealth || !s.IsAlliedWith(u.Team) {
+				continue
+			}
+			if s.DistanceTo(u) <= s.Config.SupplyRadius {
+				u.Heal(amount)
+			}
 		}
 	}
 }
 
-// cleanup removes dead units from the manager
+// cleanup removes dead units from the manager. Death events fire here, on
+// removal, so each unit reports its death exactly once however long its
+// body lingers.
 func (m *Manager) cleanup() {
 	alive := m.units[:0]
 	for _, u := range m.units {
 		// Keep unit for a short time after death for death animation
 		if !u.IsDead() {
 			alive = append(alive, u)
+		} else {
+			m.emit(EventDied, u)
 		}
 	}
 	m.units = alive
+	m.grid.Rebuild(m.units)
 }
 
 // GetUnits returns all units (including dead ones pending cleanup)
@@ -185,6 +469,18 @@ func (m *Manager) GetUnitsByTeam(team Team) []*Unit {
 	return result
 }
 
+// GetHostileUnits returns targetable units not allied with a team
+func (m *Manager) GetHostileUnits(team Team) []*Unit {
+	result := make([]*Unit, 0)
+	for _, u := range m.units {
+		if !u.IsTargetable() || m.Alliances.AreAllied(u.Team, team) {
+			continue
+		}
+		result = append(result, u)
+	}
+	return result
+}
+
 // GetUnitByID returns a unit by its ID
 func (m *Manager) GetUnitByID(id uint32) *Unit {
 	for _, u := range m.units {
@@ -197,30 +493,51 @@ func (m *Manager) GetUnitByID(id uint32) *Unit {
 
 // GetUnitsInRadius returns all units within a radius of a point
 func (m *Manager) GetUnitsInRadius(center rl.Vector3, radius float32) []*Unit {
-	result := make([]*Unit, 0)
-	for _, u := range m.units {
-		if u.IsDead() {
-			continue
-		}
-		if u.DistanceToPoint(center) <= radius {
-			result = append(result, u)
+	result := m.QueryRadius(center, radius)
+	alive := result[:0]
+	for _, u := range result {
+		if !u.IsDead() {
+			alive = append(alive, u)
 		}
 	}
-	return result
+	return alive
 }
 
-// GetEnemiesInRadius returns enemy units within a radius
+// GetEnemiesInRadius returns targetable enemy units within a radius
 func (m *Manager) GetEnemiesInRadius(center rl.Vector3, radius float32, myTeam Team) []*Unit {
-	result := make([]*Unit, 0)
-	for _, u := range m.units {
-		if u.IsDead() || u.Team == myTeam {
+	result := m.QueryRadius(center, radius)
+	enemies := result[:0]
+	for _, u := range result {
+		if u.IsTargetable() && !m.Alliances.AreAllied(u.Team, myTeam) {
+			enemies = append(enemies, u)
+		}
+	}
+	return enemies
+}
+
+// GetNearestUnit returns the closest living unit within radius of a point
+func (m *Manager) GetNearestUnit(center rl.Vector3, radius float32) *Unit {
+	return m.GetNearestSeenUnit(center, radius, nil)
+}
+
+// GetNearestSeenUnit returns the closest living unit within radius of a
+// point that seen accepts, skipping units the player can't see (nil seen
+// accepts every unit)
+func (m *Manager) GetNearestSeenUnit(center rl.Vector3, radius float32, seen func(*Unit) bool) *Unit {
+	var nearest *Unit
+	nearestDist := radius
+
+	for _, u := range m.QueryRadius(center, radius) {
+		if u.IsDead() || (seen != nil && !seen(u)) {
 			continue
 		}
-		if u.DistanceToPoint(center) <= radius {
-			result = append(result, u)
+		dist := u.DistanceToPoint(center)
+		if dist <= nearestDist {
+			nearest = u
+			nearestDist = dist
 		}
 	}
-	return result
+	return nearest
 }
 
 // GetNearestPickupableUnit returns the nearest friendly unit that can be picked up
@@ -228,8 +545,8 @@ func (m *Manager) GetNearestPickupableUnit(center rl.Vector3, radius float32, te
 	var nearest *Unit
 	nearestDist := radius
 
-	for _, u := range m.units {
-		if u.IsDead() || u.IsCarried() || u.Team != team {
+	for _, u := range m.QueryRadius(center, radius) {
+		if !u.CanBePickedUp(team) {
 			continue
 		}
 		dist := u.DistanceToPoint(center)
@@ -241,6 +558,189 @@ func (m *Manager) GetNearestPickupableUnit(center rl.Vector3, radius float32, te
 	return nearest
 }
 
+// SpawningNear returns true if a unit is still emerging within radius of a
+// point, so the next unit produced there waits its turn
+func (m *Manager) SpawningNear(pos rl.Vector3, radius float32) bool {
+	for _, u := range m.units {
+		if u.IsSpawning() && u.DistanceToPoint(pos) <= radius {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectInRadius replaces the selection with a team's units near a point
+// Returns the number of units selected
+func (m *Manager) SelectInRadius(center rl.Vector3, radius float32, team Team) int {
+	m.ClearSelection()
+
+	count := 0
+	for _, u := range m.units {
+		if u.IsDead() || u.IsCarried() || u.IsSpawning() || u.Team != team {
+			continue
+		}
+		if u.DistanceToPoint(center) <= radius {
+			u.Selected = true
+			count++
+		}
+	}
+	return count
+}
+
+// ClearSelection deselects all units
+func (m *Manager) ClearSelection() {
+	clear(m.summaries)
+	for _, u := range m.units {
+		u.Selected = false
+	}
+}
+
+// GetSelected returns the living selected units
+func (m *Manager) GetSelected() []*Unit {
+	result := make([]*Unit, 0)
+	for _, u := range m.units {
+		if u.Selected && !u.IsDead() {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// OrderSelected gives an order to every selected unit able to follow it,
+// anchored at each unit's current position. Returns how many units took it.
+func (m *Manager) OrderSelected(order Order) int {
+	count := 0
+	for _, u := range m.GetSelected() {
+		if u.IsCarried() || !u.CanFollowOrder(order) {
+			continue
+		}
+		u.SetOrder(order, u.Position)
+		count++
+	}
+	return count
+}
+
+// MoveSelected sends every selected unit to a point along pathfound
+// routes, clearing their orders. An attack-move engages enemies met on the
+// way; a plain move ignores them until the units arrive. Carried units stay
+// aboard. Returns how many units were sent.
+func (m *Manager) MoveSelected(goal rl.Vector3, attack bool) int {
+	selected := make([]*Unit, 0)
+	for _, u := range m.GetSelected() {
+		if u.IsCarried() {
+			continue
+		}
+		selected = append(selected, u)
+		u.interrupted = false
+		u.Order = OrderNone
+		u.ForceMove = !attack
+		if !attack {
+			u.Target = nil
+		}
+	}
+	m.MoveGroup(selected, goal)
+	return len(selected)
+}
+
+// GuardSelected orders every selected unit able to fight to escort a ward,
+// a unit ID or MechID. Returns how many units took the order.
+func (m *Manager) GuardSelected(wardID uint32) int {
+	wardPos, ok := m.wardPosition(wardID)
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, u := range m.GetSelected() {
+		if u.ID == wardID || u.IsCarried() || !u.CanFollowOrder(OrderGuard) {
+			continue
+		}
+		u.Guard(wardID, wardPos)
+		count++
+	}
+	return count
+}
+
+// GarrisonSelected sends selected units able to garrison into the base at
+// a position. Returns how many were sent.
+func (m *Manager) GarrisonSelected(basePos rl.Vector3) int {
+	count := 0
+	for _, u := range m.GetSelected() {
+		if u.IsCarried() || !u.CanFollowOrder(OrderGarrison) {
+			continue
+		}
+		u.SetOrder(OrderGarrison, basePos)
+		m.RequestPlayerPath(u, basePos)
+		count++
+	}
+	return count
+}
+
+// Detach takes a living unit out of the world without killing it, as when
+// it shelters inside a base. Nothing keeps targeting it, radius queries
+// stop finding it, and no death event fires. Returns the unit, nil if there's none with that ID.
+func (m *Manager) Detach(id uint32) *Unit {
+	var detached *Unit
+	remaining := m.units[:0]
+	for _, u := range m.units {
+		if u.ID == id && !u.IsDead() {
+			detached = u
+			continue
+		}
+		remaining = append(remaining, u)
+	}
+	m.units = remaining
+	if detached == nil {
+		return nil
+	}
+	m.grid.Remove(detached)
+
+	for _, u := range m.units {
+		if u.Target == detached {
+			u.Target = nil
+		}
+	}
+	requests := m.pathRequests[:0]
+	for _, req := range m.pathRequests {
+		if req.unit != detached {
+			requests = append(requests, req)
+		}
+	}
+	m.pathRequests = requests
+
+	detached.Selected = false
+	detached.Target = nil
+	return detached
+}
+
+// Attach puts a detached unit back into the world at a position, idle and
+// without orders. Returns false if the unit cap is reached.
+func (m *Manager) Attach(u *Unit, pos rl.Vector3) bool {
+	if len(m.units) >= m.maxUnits {
+		return false
+	}
+	u.Position = pos
+	u.Velocity = rl.Vector3{}
+	u.State = StateIdle
+	u.Order = OrderNone
+	u.interrupted = false
+	u.ForceMove = false
+	u.ClearObjective()
+	m.units = append(m.units, u)
+	m.grid.Insert(u)
+	return true
+}
+
+// SetTeam moves a unit to another side and has every unit that was
+// shooting at it and is now its friend drop it as a target
+func (m *Manager) SetTeam(u *Unit, team Team) {
+	u.SetTeam(team)
+	for _, other := range m.units {
+		if other.Target == u && !other.CanAttack(u) {
+			other.Target = nil
+		}
+	}
+}
+
 // Count returns the total number of units
 func (m *Manager) Count() int {
 	return len(m.units)
@@ -260,6 +760,19 @@ func (m *Manager) CountByTeam(team Team) int {
 // Clear removes all units
 func (m *Manager) Clear() {
 	m.units = m.units[:0]
+	m.grid.Clear()
+	clear(m.summaries)
+}
+
+// Reset removes all units and restarts ID assignment for a new match
+func (m *Manager) Reset() {
+	m.Clear()
+	m.nextID = 1
+	m.shots = m.shots[:0]
+	m.pathRequests = m.pathRequests[:0]
+	m.spawnRequests = m.spawnRequests[:0]
+	m.spawnsThisFrame = 0
+	m.rng.Seed(m.seed)
 }
 
 // SetPathfinderForUnit calculates and sets a path for a specific unit
@@ -271,8 +784,179 @@ func (m *Manager) SetPathfinderForUnit(u *Unit, goal rl.Vector3) {
 	path := m.Pathfinder.FindPath(
 		rl.Vector2{X: u.Position.X, Y: u.Position.Z},
 		rl.Vector2{X: goal.X, Y: goal.Z},
+		u.Config.CanTraverseWater,
 	)
 	if path != nil {
 		u.SetPath(path)
 	}
 }
+
+// RequestPath queues a path search for a unit. Until it's served the unit
+// keeps its old path or heads straight for its objective. A newer request
+// for the same unit replaces the older one.
+func (m *Manager) RequestPath(u *Unit, goal rl.Vector3) {
+	m.enqueuePath(u, goal, false)
+}
+
+// RequestPlayerPath queues a path search for a player command, served
+// ahead of every AI request
+func (m *Manager) RequestPlayerPath(u *Unit, goal rl.Vector3) {
+	m.enqueuePath(u, goal, true)
+}
+
+func (m *Manager) enqueuePath(u *Unit, goal rl.Vector3, player bool) {
+	if m.Pathfinder == nil {
+		return
+	}
+	for i := range m.pathRequests {
+		req := &m.pathRequests[i]
+		if req.unit == u {
+			req.goal = goal
+			req.player = req.player || player
+			return
+		}
+	}
+	m.pathRequests = append(m.pathRequests, pathRequest{unit: u, goal: goal, player: player})
+}
+
+// ProcessPathRequests runs up to maxPerFrame queued path searches, player
+// requests first, then AI requests oldest first (maxPerFrame <= 0 runs them
+// all). Returns how many searches ran.
+func (m *Manager) ProcessPathRequests(maxPerFrame int) int {
+	if len(m.pathRequests) == 0 {
+		return 0
+	}
+
+	// Player requests move to the front, keeping arrival order within each group
+	ordered := make([]pathRequest, 0, len(m.pathRequests))
+	for _, req := range m.pathRequests {
+		if req.player {
+			ordered = append(ordered, req)
+		}
+	}
+	for _, req := range m.pathRequests {
+		if !req.player {
+			ordered = append(ordered, req)
+		}
+	}
+
+	done := 0
+	served := 0
+	for _, req := range ordered {
+		if maxPerFrame > 0 && done >= maxPerFrame {
+			break
+		}
+		served++
+		// Dead or carried units and cancelled moves don't need a path
+		if req.unit.IsDead() || req.unit.IsCarried() || !req.unit.HasObjective {
+			continue
+		}
+		m.SetPathfinderForUnit(req.unit, req.goal)
+		done++
+	}
+	m.pathRequests = append(m.pathRequests[:0], ordered[served:]...)
+	return done
+}
+
+// PendingPathRequests returns how many path searches are waiting
+func (m *Manager) PendingPathRequests() int {
+	return len(m.pathRequests)
+}
+
+// SpawnRequest is a unit waiting to enter the world
+type SpawnRequest struct {
+	Type     UnitType
+	Team     Team
+	Position rl.Vector3
+
+	// Called with the unit once it spawns (optional)
+	OnSpawn func(*Unit)
+}
+
+// QueueSpawn spawns a unit now if this frame's spawn budget allows,
+// otherwise queues it for a later frame. Returns true if it spawned now.
+// A unit that can't spawn because the cap is reached is dropped.
+func (m *Manager) QueueSpawn(req SpawnRequest) bool {
+	if len(m.spawnRequests) == 0 && m.spawnBudgetLeft() {
+		m.spawnNow(req)
+		return true
+	}
+	m.spawnRequests = append(m.spawnRequests, req)
+	return false
+}
+
+// ProcessSpawnRequests starts a new frame's spawn budget and spawns queued
+// units oldest first until it runs out. Returns how many were spawned.
+func (m *Manager) ProcessSpawnRequests() int {
+	m.spawnsThisFrame = 0
+	served := 0
+	for served < len(m.spawnRequests) && m.spawnBudgetLeft() {
+		m.spawnNow(m.spawnRequests[served])
+		served++
+	}
+	m.spawnRequests = append(m.spawnRequests[:0], m.spawnRequests[served:]...)
+	return served
+}
+
+// PendingSpawns returns how many queued units are waiting to spawn
+func (m *Manager) PendingSpawns() int {
+	return len(m.spawnRequests)
+}
+
+func (m *Manager) spawnBudgetLeft() bool {
+	return m.SpawnBudget <= 0 || m.spawnsThisFrame < m.SpawnBudget
+}
+
+func (m *Manager) spawnNow(req SpawnRequest) {
+	m.spawnsThisFrame++
+	u := m.Spawn(req.Type, req.Team, req.Position)
+	if u != nil && req.OnSpawn != nil {
+		req.OnSpawn(u)
+	}
+}
+
+// MoveGroup sends a group of units toward a shared goal.
+// Each unit gets its own goal cell around the target so the group spreads
+// out on arrival instead of jittering over a single cell.
+func (m *Manager) MoveGroup(units []*Unit, goal rl.Vector3) {
+	if len(units) == 0 {
+		return
+	}
+
+	if m.Pathfinder == nil {
+		for _, u := range units {
+			u.SetObjective(goal)
+		}
+		return
+	}
+
+	canWater := true
+	for _, u := range units {
+		canWater = canWater && u.Config.CanTraverseWater
+	}
+	goals := m.Pathfinder.AllocateGoalCells(rl.Vector2{X: goal.X, Y: goal.Z}, len(units), canWater)
+	taken := make([]bool, len(goals))
+
+	for _, u := range units {
+		// Each unit claims the closest goal cell still available
+		best := -1
+		bestDist := float32(1e9)
+		for i, g := range goals {
+			if taken[i] {
+				continue
+			}
+			dx := g.X - u.Position.X
+			dz := g.Y - u.Position.Z
+			dist := dx*dx + dz*dz
+			if dist < bestDist {
+				best = i
+				bestDist = dist
+			}
+		}
+		taken[best] = true
+
+		target := rl.Vector3{X: goals[best].X, Y: goal.Y, Z: goals[best].Y}
+		u.SetObjective(target)
+		m.RequestPlayerPath(u, target) // Group moves are player commands
+	}
+}
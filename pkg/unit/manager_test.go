@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestNearestSeenUnitSkipsHidden(t *testing.T) {
+	m := NewManager(10)
+	hidden := m.Spawn(TypeTank, TeamEnemy, rl.NewVector3(0, 0, 0))
+	shown := m.Spawn(TypeTank, TeamEnemy, rl.NewVector3(1.5, 0, 0))
+
+	if got := m.GetNearestUnit(rl.NewVector3(0, 0, 0), 2); got != hidden {
+		t.Fatal("nearest unit isn't the closest")
+	}
+	seen := func(u *Unit) bool { return u != hidden }
+	if got := m.GetNearestSeenUnit(rl.NewVector3(0, 0, 0), 2, seen); got != shown {
+		t.Errorf("hovered %v, want the seen unit", got)
+	}
+}
+
+func TestSupplyHealsAlliesInRadius(t *testing.T) {
+	m := NewManager(10)
+	spawn := func(ut UnitType, team Team, x float32) *Unit {
+		u := m.Spawn(ut, team, rl.NewVector3(x, 0, 0))
+		u.SpawnTimer = 0
+		u.State = StateIdle
+		return u
+	}
+	truck := spawn(TypeSupply, TeamPlayer, 0)
+	radius := truck.Config.SupplyRadius
+	inside := spawn(TypeInfantry, TeamPlayer, radius-0.01)
+	outside := spawn(TypeInfantry, TeamPlayer, -(radius + 0.01))
+	enemy := spawn(TypeInfantry, TeamEnemy, 1)
+	full := spawn(TypeInfantry, TeamPlayer, 0.5)
+	for _, u := range []*Unit{truck, inside, outside, enemy} {
+		u.Health = u.MaxHealth / 2
+	}
+
+	m.updateSupply(1)
+
+	if inside.Health <= inside.MaxHealth/2 {
+		t.Error("ally just inside the radius wasn't healed")
+	}
+	if outside.Health != outside.MaxHealth/2 {
+		t.Error("ally just outside the radius was healed")
+	}
+	if enemy.Health != enemy.MaxHealth/2 {
+		t.Error("enemy next to the truck was healed")
+	}
+	if full.Health != full.MaxHealth {
+		t.Errorf("full-health ally ended at %.1f of %.1f", full.Health, full.MaxHealth)
+	}
+	if truck.Health != truck.MaxHealth/2 {
+		t.Error("supply truck healed itself")
+	}
+}
+
+func TestMovingSupplyDoesNotHeal(t *testing.T) {
+	m := NewManager(10)
+	truck := m.Spawn(TypeSupply, TeamPlayer, rl.NewVector3(0, 0, 0))
+	ally := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(1, 0, 0))
+	for _, u := range []*Unit{truck, ally} {
+		u.SpawnTimer = 0
+		u.State = StateIdle
+	}
+	ally.Health = ally.MaxHealth / 2
+	truck.Velocity = rl.Vector3{X: truck.Config.Speed}
+
+	m.updateSupply(1)
+
+	if ally.Health != ally.MaxHealth/2 {
+		t.Error("supply truck healed while driving")
+	}
+}
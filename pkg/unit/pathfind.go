@@ -5,23 +5,97 @@ import (
 	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/coords"
+	"github.com/chazu/herzog-drei/pkg/profile"
+	"github.com/chazu/herzog-drei/pkg/tilemap"
 )
 
 // Pathfinder implements A* pathfinding on a grid
 type Pathfinder struct {
 	width, height int
-	cellSize      float32
+	grid          coords.Grid // Laid out like the tile map's, cell (0, 0) at the origin
 	blocked       []bool // true if cell is blocked
+	water         []bool // true if cell is water, passable only to amphibious units
+
+	costGrid []float32 // Cost of entering each cell, 1 for open ground
+	minCost  float32   // Cheapest cell, keeping the heuristic admissible
+
+	// DiagonalMovement allows 8-directional paths; false gives grid-aligned
+	// cardinal-only paths
+	DiagonalMovement bool
+
+	// Profiler times path searches (nil = off)
+	Profiler *profile.Profiler
 }
 
-// NewPathfinder creates a new pathfinder for the given map size
+// NewPathfinder creates a new pathfinder for the given map size, its cells
+// lying on the tiles of a map that size
 func NewPathfinder(width, height int, cellSize float32) *Pathfinder {
 	return &Pathfinder{
 		width:    width,
 		height:   height,
-		cellSize: cellSize,
+		grid:     coords.NewGrid(width, height, cellSize),
 		blocked:  make([]bool, width*height),
+		water:    make([]bool, width*height),
+		costGrid: uniformCosts(width * height),
+		minCost:  1,
+
+		DiagonalMovement: true,
+	}
+}
+
+// uniformCosts returns n cells of open ground
+func uniformCosts(n int) []float32 {
+	costs := make([]float32, n)
+	for i := range costs {
+		costs[i] = 1
+	}
+	return costs
+}
+
+// SetCost sets what entering a cell costs relative to open ground. Costs
+// at or below zero are ignored.
+func (p *Pathfinder) SetCost(x, y int, cost float32) {
+	if x < 0 || x >= p.width || y < 0 || y >= p.height || cost <= 0 {
+		return
+	}
+	i := y*p.width + x
+	old := p.costGrid[i]
+	p.costGrid[i] = cost
+	if cost < p.minCost {
+		p.minCost = cost
+	} else if old == p.minCost {
+		p.updateMinCost()
+	}
+}
+
+// Cost returns what entering a cell costs relative to open ground
+func (p *Pathfinder) Cost(x, y int) float32 {
+	if x < 0 || x >= p.width || y < 0 || y >= p.height {
+		return 1
 	}
+	return p.costGrid[y*p.width+x]
+}
+
+// updateMinCost finds the cheapest cell again after costs change
+func (p *Pathfinder) updateMinCost() {
+	p.minCost = p.costGrid[0]
+	for _, c := range p.costGrid[1:] {
+		if c < p.minCost {
+			p.minCost = c
+		}
+	}
+}
+
+// terrainCost is what entering terrain costs, the inverse of its speed so
+// slow ground costs more and fast ground less. Terrain nothing drives
+// through at speed, like water, costs the same as open ground.
+func terrainCost(terrain tilemap.TerrainType) float32 {
+	if mod := tilemap.GetTerrainInfo(terrain).SpeedMod; mod > 0 {
+		return 1 / mod
+	}
+	return 1
 }
 
 // SetBlocked marks a cell as blocked or unblocked
@@ -39,36 +113,77 @@ func (p *Pathfinder) IsBlocked(x, y int) bool {
 	return p.blocked[y*p.width+x]
 }
 
+// SetWater marks a cell as water or dry ground
+func (p *Pathfinder) SetWater(x, y int, water bool) {
+	if x >= 0 && x < p.width && y >= 0 && y < p.height {
+		p.water[y*p.width+x] = water
+	}
+}
+
+// IsWater returns true if a cell is water
+func (p *Pathfinder) IsWater(x, y int) bool {
+	if x < 0 || x >= p.width || y < 0 || y >= p.height {
+		return false
+	}
+	return p.water[y*p.width+x]
+}
+
+// isImpassable returns true if a unit can't enter a cell. Water only stops
+// units that can't cross it.
+func (p *Pathfinder) isImpassable(x, y int, canWater bool) bool {
+	return p.IsBlocked(x, y) || (!canWater && p.water[y*p.width+x])
+}
+
+// SyncFromTileMap takes on the tile map's grid, one cell per tile, and
+// marks each cell blocked or water and sets its cost from its tile's
+// terrain. Anything off the map is blocked.
+func (p *Pathfinder) SyncFromTileMap(tm *tilemap.TileMap) {
+	if tm.Width != p.width || tm.Height != p.height {
+		p.width, p.height = tm.Width, tm.Height
+		p.blocked = make([]bool, p.width*p.height)
+		p.water = make([]bool, p.width*p.height)
+		p.costGrid = uniformCosts(p.width * p.height)
+	}
+	p.grid = tm.Grid()
+
+	for y, row := range tm.Tiles {
+		for x, tile := range row {
+			i := p.grid.Index(x, y)
+			p.water[i] = tile.Terrain == tilemap.TerrainWater
+			p.blocked[i] = !tile.Terrain.IsPassable() && !p.water[i]
+			p.costGrid[i] = terrainCost(tile.Terrain)
+		}
+	}
+	p.updateMinCost()
+}
+
+// Grid returns the pathfinder's cell grid
+func (p *Pathfinder) Grid() coords.Grid {
+	return p.grid
+}
+
 // WorldToGrid converts world coordinates to grid coordinates
 func (p *Pathfinder) WorldToGrid(pos rl.Vector2) (int, int) {
-	// Center the grid on the world origin
-	offsetX := float32(p.width) * p.cellSize / 2
-	offsetY := float32(p.height) * p.cellSize / 2
-
-	x := int((pos.X + offsetX) / p.cellSize)
-	y := int((pos.Y + offsetY) / p.cellSize)
-	return x, y
+	return p.grid.WorldToCell(pos.X, pos.Y)
 }
 
 // GridToWorld converts grid coordinates to world coordinates (center of cell)
 func (p *Pathfinder) GridToWorld(x, y int) rl.Vector2 {
-	offsetX := float32(p.width) * p.cellSize / 2
-	offsetY := float32(p.height) * p.cellSize / 2
-
-	return rl.Vector2{
-		X: float32(x)*p.cellSize + p.cellSize/2 - offsetX,
-		Y: float32(y)*p.cellSize + p.cellSize/2 - offsetY,
-	}
+	worldX, worldZ := p.grid.CellToWorld(x, y)
+	return rl.Vector2{X: worldX, Y: worldZ}
 }
 
-// FindPath finds a path from start to goal using A*
+// FindPath finds a path from start to goal using A*. canWater lets the
+// path cross water cells.
 // Returns nil if no path is found
-func (p *Pathfinder) FindPath(start, goal rl.Vector2) []rl.Vector2 {
+func (p *Pathfinder) FindPath(start, goal rl.Vector2, canWater bool) []rl.Vector2 {
+	defer p.Profiler.Start(profile.SectionPathfinding)()
+
 	startX, startY := p.WorldToGrid(start)
 	goalX, goalY := p.WorldToGrid(goal)
 
 	// If start or goal is blocked, return nil
-	if p.IsBlocked(startX, startY) || p.IsBlocked(goalX, goalY) {
+	if p.isImpassable(startX, startY, canWater) || p.isImpassable(goalX, goalY, canWater) {
 		return nil
 	}
 
@@ -84,7 +199,7 @@ func (p *Pathfinder) FindPath(start, goal rl.Vector2) []rl.Vector2 {
 	startNode := &pathNode{
 		x: startX, y: startY,
 		g: 0,
-		h: heuristic(startX, startY, goalX, goalY),
+		h: p.heuristic(startX, startY, goalX, goalY),
 	}
 	startNode.f = startNode.g + startNode.h
 	heap.Push(openSet, startNode)
@@ -93,11 +208,8 @@ func (p *Pathfinder) FindPath(start, goal rl.Vector2) []rl.Vector2 {
 	gScore := make(map[int]float32)
 	gScore[startY*p.width+startX] = 0
 
-	// Direction vectors for 8-directional movement
-	dirs := [][2]int{
-		{0, -1}, {0, 1}, {-1, 0}, {1, 0},   // Cardinal
-		{-1, -1}, {1, -1}, {-1, 1}, {1, 1}, // Diagonal
-	}
+	// Cardinal steps, then diagonals if allowed
+	dirs := p.directions()
 	costs := []float32{1, 1, 1, 1, 1.41, 1.41, 1.41, 1.41}
 
 	for openSet.Len() > 0 {
@@ -108,31 +220,23 @@ func (p *Pathfinder) FindPath(start, goal rl.Vector2) []rl.Vector2 {
 			return p.reconstructPath(cameFrom, current)
 		}
 
-		// Explore neighbors
+		// Explore neighbors, skipping blocked or out of bounds cells
 		for i, dir := range dirs {
-			nx, ny := current.x+dir[0], current.y+dir[1]
-
-			// Skip if blocked or out of bounds
-			if p.IsBlocked(nx, ny) {
+			if !p.canStep(current.x, current.y, i, dir, canWater) {
 				continue
 			}
+			nx, ny := current.x+dir[0], current.y+dir[1]
 
-			// For diagonal movement, check if both adjacent cells are free
-			if i >= 4 { // Diagonal
-				if p.IsBlocked(current.x+dir[0], current.y) || p.IsBlocked(current.x, current.y+dir[1]) {
-					continue
-				}
-			}
-
-			tentativeG := gScore[current.y*p.width+current.x] + costs[i]
+			// Steps cost more the slower the ground being entered
 			neighborKey := ny*p.width + nx
+			tentativeG := gScore[current.y*p.width+current.x] + costs[i]*p.costGrid[neighborKey]
 
 			existingG, exists := gScore[neighborKey]
 			if !exists || tentativeG < existingG {
 				neighbor := &pathNode{
 					x: nx, y: ny,
 					g: tentativeG,
-					h: heuristic(nx, ny, goalX, goalY),
+					h: p.heuristic(nx, ny, goalX, goalY),
 				}
 				neighbor.f = neighbor.g + neighbor.h
 
@@ -147,6 +251,195 @@ func (p *Pathfinder) FindPath(start, goal rl.Vector2) []rl.Vector2 {
 	return nil
 }
 
+// IsReachable reports whether any path connects start to goal, for checks
+// that don't need the route itself. It floods outward from start and stops
+// as soon as it meets the goal, skipping A*'s bookkeeping and the path
+// reconstruction. Moves follow the same rules as FindPath, so the answer
+// matches FindPath returning non-nil with the same canWater.
+func (p *Pathfinder) IsReachable(start, goal rl.Vector2, canWater bool) bool {
+	startX, startY := p.WorldToGrid(start)
+	goalX, goalY := p.WorldToGrid(goal)
+
+	if p.isImpassable(startX, startY, canWater) || p.isImpassable(goalX, goalY, canWater) {
+		return false
+	}
+	if startX == goalX && startY == goalY {
+		return true
+	}
+
+	dirs := p.directions()
+	visited := make([]bool, p.width*p.height)
+	visited[startY*p.width+startX] = true
+	queue := []int{startY*p.width + startX}
+
+	for len(queue) > 0 {
+		cx, cy := queue[0]%p.width, queue[0]/p.width
+		queue = queue[1:]
+
+		for i, dir := range dirs {
+			if !p.canStep(cx, cy, i, dir, canWater) {
+				continue
+			}
+
+			nx, ny := cx+dir[0], cy+dir[1]
+			key := ny*p.width + nx
+			if visited[key] {
+				continue
+			}
+			if nx == goalX && ny == goalY {
+				return true
+			}
+			visited[key] = true
+			queue = append(queue, key)
+		}
+	}
+	return false
+}
+
+// directions returns the steps a path may take from a cell, cardinal
+// first, diagonals too if DiagonalMovement is set
+func (p *Pathfinder) directions() [][2]int {
+	dirs := [][2]int{
+		{0, -1}, {0, 1}, {-1, 0}, {1, 0},   // Cardinal
+		{-1, -1}, {1, -1}, {-1, 1}, {1, 1}, // Diagonal
+	}
+	if !p.DiagonalMovement {
+		return dirs[:4]
+	}
+	return dirs
+}
+
+// canStep returns true if a unit can move from a cell along the i-th of
+// directions. Diagonal steps need both cells they cut past to be open.
+func (p *Pathfinder) canStep(x, y, i int, dir [2]int, canWater bool) bool {
+	if p.isImpassable(x+dir[0], y+dir[1], canWater) {
+		return false
+	}
+	return i < 4 || (!p.isImpassable(x+dir[0], y, canWater) && !p.isImpassable(x, y+dir[1], canWater))
+}
+
+// PathLength returns the total length of a path starting from start
+func PathLength(start rl.Vector2, path []rl.Vector2) float32 {
+	length := float32(0)
+	prev := start
+	for _, wp := range path {
+		dx := wp.X - prev.X
+		dy := wp.Y - prev.Y
+		length += float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		prev = wp
+	}
+	return length
+}
+
+// maxGoalSearchRadius limits how far (in cells) goal allocation spirals out
+const maxGoalSearchRadius = 10
+
+// AllocateGoalCells returns count goal positions spread around a target.
+// Cells are taken in a spiral outward from the target cell so each unit in a
+// group gets its own destination. Only cells a unit can walk to from the
+// target are used, so nobody is sent across water or a ridge; canWater is
+// whether every unit in the group can cross water. If there aren't enough
+// such cells nearby, the found cells are shared round-robin.
+func (p *Pathfinder) AllocateGoalCells(goal rl.Vector2, count int, canWater bool) []rl.Vector2 {
+	if count <= 0 {
+		return nil
+	}
+
+	goalX, goalY := p.WorldToGrid(goal)
+	reached := p.floodNear(goalX, goalY, maxGoalSearchRadius, canWater)
+	cells := make([]rl.Vector2, 0, count)
+
+	for r := 0; r <= maxGoalSearchRadius && len(cells) < count; r++ {
+		for _, c := range ringCells(goalX, goalY, r) {
+			if !reached[c] {
+				continue
+			}
+			cells = append(cells, p.GridToWorld(c[0], c[1]))
+			if len(cells) == count {
+				break
+			}
+		}
+	}
+
+	// Nothing passable nearby, everyone heads for the target itself
+	if len(cells) == 0 {
+		cells = append(cells, goal)
+	}
+
+	// Share cells when the neighborhood is too cramped
+	for i := len(cells); i < count; i++ {
+		cells = append(cells, cells[i%len(cells)])
+	}
+
+	return cells
+}
+
+// floodNear returns the cells within radius of (cx, cy) that connect to
+// it without leaving that square. If (cx, cy) can't be entered the flood
+// starts from the nearest cell that can.
+func (p *Pathfinder) floodNear(cx, cy, radius int, canWater bool) map[[2]int]bool {
+	reached := make(map[[2]int]bool)
+	var queue [][2]int
+	for r := 0; r <= radius && len(queue) == 0; r++ {
+		for _, c := range ringCells(cx, cy, r) {
+			if !p.isImpassable(c[0], c[1], canWater) {
+				queue = append(queue, c)
+				reached[c] = true
+				break
+			}
+		}
+	}
+
+	dirs := p.directions()
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for i, dir := range dirs {
+			n := [2]int{c[0] + dir[0], c[1] + dir[1]}
+			if reached[n] || abs(n[0]-cx) > radius || abs(n[1]-cy) > radius {
+				continue
+			}
+			if !p.canStep(c[0], c[1], i, dir, canWater) {
+				continue
+			}
+			reached[n] = true
+			queue = append(queue, n)
+		}
+	}
+	return reached
+}
+
+// ringCells returns the grid cells at exactly Chebyshev distance r from
+// (cx, cy), ordered with the cells closest to the center first
+func ringCells(cx, cy, r int) [][2]int {
+	if r == 0 {
+		return [][2]int{{cx, cy}}
+	}
+
+	ring := make([][2]int, 0, 8*r)
+	// Walk outward from the middle of each side so cardinal neighbors come
+	// before corners
+	for offset := 0; offset <= r; offset++ {
+		for _, sign := range []int{1, -1} {
+			d := offset * sign
+			if offset == 0 && sign == -1 {
+				continue
+			}
+			if offset == r && sign == -1 {
+				// Corners are shared by two sides, only add them once
+				continue
+			}
+			ring = append(ring,
+				[2]int{cx + d, cy - r}, // top
+				[2]int{cx + r, cy + d}, // right
+				[2]int{cx - d, cy + r}, // bottom
+				[2]int{cx - r, cy - d}, // left
+			)
+		}
+	}
+	return ring
+}
+
 // reconstructPath builds the path from the cameFrom map
 func (p *Pathfinder) reconstructPath(cameFrom map[int]*pathNode, current *pathNode) []rl.Vector2 {
 	path := []rl.Vector2{p.GridToWorld(current.x, current.y)}
@@ -207,12 +500,16 @@ func (p *Pathfinder) simplifyPath(path []rl.Vector2) []rl.Vector2 {
 }
 
 // heuristic calculates the estimated cost from (x,y) to (gx,gy)
-// Using octile distance for 8-directional movement
-func heuristic(x, y, gx, gy int) float32 {
+// Using octile distance for 8-directional movement, Manhattan for 4, all
+// over the cheapest ground so it never overestimates
+func (p *Pathfinder) heuristic(x, y, gx, gy int) float32 {
 	dx := abs(gx - x)
 	dy := abs(gy - y)
+	if !p.DiagonalMovement {
+		return float32(dx+dy) * p.minCost
+	}
 	// Octile distance
-	return float32(max(dx, dy)) + 0.41*float32(min(dx, dy))
+	return (float32(max(dx, dy)) + 0.41*float32(min(dx, dy))) * p.minCost
 }
 
 func abs(x int) int {
@@ -0,0 +1,247 @@
+package unit
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/tilemap"
+)
+
+// syncedPathfinder returns a pathfinder synced to a tile map
+func syncedPathfinder(tm *tilemap.TileMap) *Pathfinder {
+	p := NewPathfinder(tm.Width, tm.Height, tm.TileSize)
+	p.SyncFromTileMap(tm)
+	return p
+}
+
+// checkPathAvoids fails if any waypoint, or any cell stepped through
+// between waypoints, is terrain a ground unit can't enter
+func checkPathAvoids(t *testing.T, tm *tilemap.TileMap, start rl.Vector2, path []rl.Vector2) {
+	t.Helper()
+	prev := start
+	for _, wp := range path {
+		steps := int(PathLength(prev, []rl.Vector2{wp})*4) + 1
+		for s := 0; s <= steps; s++ {
+			f := float32(s) / float32(steps)
+			x := prev.X + (wp.X-prev.X)*f
+			z := prev.Y + (wp.Y-prev.Y)*f
+			tx, ty := tm.WorldToTile(x, z)
+			if !tm.InBounds(tx, ty) {
+				t.Fatalf("path leaves the map at (%v, %v)", x, z)
+			}
+			if terrain := tm.Tiles[ty][tx].Terrain; !terrain.IsPassable() {
+				t.Fatalf("path crosses %v at tile (%d, %d)", terrain, tx, ty)
+			}
+		}
+		prev = wp
+	}
+}
+
+func TestPathAroundWaterWall(t *testing.T) {
+	tm := tilemap.NewTileMap(20, 20)
+	for y := 0; y < 15; y++ {
+		tm.SetTerrain(10, y, tilemap.TerrainWater)
+	}
+	p := syncedPathfinder(tm)
+
+	start, goal := rl.Vector2{X: 5.5, Y: 5.5}, rl.Vector2{X: 15.5, Y: 5.5}
+	path := p.FindPath(start, goal, false)
+	if len(path) == 0 {
+		t.Fatal("no path around the wall")
+	}
+	checkPathAvoids(t, tm, start, path)
+}
+
+func TestPathsOnTestMap(t *testing.T) {
+	tm := tilemap.GenerateTestMap(64, 48)
+	p := syncedPathfinder(tm)
+
+	cases := []struct{ start, goal rl.Vector2 }{
+		{rl.Vector2{X: 10, Y: 40}, rl.Vector2{X: 30, Y: 40}},         // Across the river by the ford
+		{rl.Vector2{X: 32.5, Y: 24.5}, rl.Vector2{X: 5.5, Y: 2.5}},   // From the map center
+		{rl.Vector2{X: 40.5, Y: 13.5}, rl.Vector2{X: 47.5, Y: 13.5}}, // Past the mountains
+	}
+	for _, c := range cases {
+		path := p.FindPath(c.start, c.goal, false)
+		if len(path) == 0 {
+			t.Errorf("no path from %v to %v", c.start, c.goal)
+			continue
+		}
+		checkPathAvoids(t, tm, c.start, path)
+	}
+}
+
+func TestSyncBlocksOffMap(t *testing.T) {
+	tm := tilemap.GenerateTestMap(64, 48)
+	p := syncedPathfinder(tm)
+
+	if !p.IsBlocked(-1, 0) || !p.IsBlocked(0, 48) {
+		t.Error("cells off the map aren't blocked")
+	}
+	for y := tm.Height / 4; y <= tm.Height/4+3; y++ {
+		for x := tm.Width * 2 / 3; x <= tm.Width*2/3+3; x++ {
+			if !p.IsBlocked(x, y) {
+				t.Errorf("mountain tile (%d, %d) isn't blocked", x, y)
+			}
+		}
+	}
+}
+
+func TestBoatPathsAcrossWater(t *testing.T) {
+	tm := tilemap.NewTileMap(20, 20)
+	tm.FillRect(5, 0, 14, 19, tilemap.TerrainWater)
+	p := syncedPathfinder(tm)
+
+	start, goal := rl.Vector2{X: 2.5, Y: 10.5}, rl.Vector2{X: 10.5, Y: 10.5}
+	if p.FindPath(start, goal, false) != nil {
+		t.Error("ground unit found a path into the lake")
+	}
+	if !p.IsReachable(start, goal, true) {
+		t.Fatal("lake isn't reachable by water")
+	}
+	if p.FindPath(start, goal, true) == nil {
+		t.Error("boat found no path though IsReachable says there is one")
+	}
+}
+
+func TestGoalCellsStayOnTargetsBank(t *testing.T) {
+	tm := tilemap.NewTileMap(30, 30)
+	for y := 0; y < tm.Height; y++ {
+		tm.SetTerrain(15, y, tilemap.TerrainWater)
+	}
+	p := syncedPathfinder(tm)
+
+	// Plenty of room on the near bank, more units than it has cells nearby
+	goal := rl.Vector2{X: 13.5, Y: 15.5}
+	for _, c := range p.AllocateGoalCells(goal, 40, false) {
+		x, y := p.WorldToGrid(c)
+		if x >= 15 {
+			t.Fatalf("goal cell (%d, %d) is in or across the river", x, y)
+		}
+	}
+
+	// Boats may be sent onto the water
+	onWater := false
+	for _, c := range p.AllocateGoalCells(goal, 40, true) {
+		if x, _ := p.WorldToGrid(c); x == 15 {
+			onWater = true
+		}
+	}
+	if !onWater {
+		t.Error("no goal cells on the water for a group that can cross it")
+	}
+}
+
+func TestRoadDetourBeatsForest(t *testing.T) {
+	tm := tilemap.NewTileMap(30, 20)
+	tm.FillRect(5, 7, 24, 13, tilemap.TerrainForest)
+	for x := 0; x < tm.Width; x++ {
+		tm.SetTerrain(x, 4, tilemap.TerrainRoad)
+	}
+	p := syncedPathfinder(tm)
+
+	// Straight through the forest is shorter, along the road is cheaper
+	start, goal := rl.Vector2{X: 2.5, Y: 10.5}, rl.Vector2{X: 27.5, Y: 10.5}
+	path := p.FindPath(start, goal, false)
+	if len(path) == 0 {
+		t.Fatal("no path")
+	}
+
+	onRoad := 0
+	prev := start
+	for _, wp := range path {
+		steps := int(PathLength(prev, []rl.Vector2{wp})*4) + 1
+		for s := 0; s <= steps; s++ {
+			f := float32(s) / float32(steps)
+			tx, ty := tm.WorldToTile(prev.X+(wp.X-prev.X)*f, prev.Y+(wp.Y-prev.Y)*f)
+			switch tm.Tiles[ty][tx].Terrain {
+			case tilemap.TerrainForest:
+				t.Fatalf("path cuts through the forest at tile (%d, %d)", tx, ty)
+			case tilemap.TerrainRoad:
+				onRoad++
+			}
+		}
+		prev = wp
+	}
+	if onRoad == 0 {
+		t.Error("path never takes the road")
+	}
+}
+
+// reachabilityMaps are maps with rivers, lakes, and ranges to get around
+func reachabilityMaps() map[string]*tilemap.TileMap {
+	wall := tilemap.NewTileMap(30, 30)
+	wall.FillRect(14, 0, 15, 29, tilemap.TerrainWater)
+	wall.FillRect(0, 14, 13, 15, tilemap.TerrainMountain)
+	maps := map[string]*tilemap.TileMap{
+		"test":  tilemap.GenerateTestMap(64, 48),
+		"walls": wall,
+	}
+	for seed := int64(1); seed <= 3; seed++ {
+		maps[fmt.Sprint("procedural-", seed)] = tilemap.GenerateProcedural(64, 48, seed)
+	}
+	return maps
+}
+
+// randomPoint returns a point somewhere on a map
+func randomPoint(rng *rand.Rand, tm *tilemap.TileMap) rl.Vector2 {
+	return rl.Vector2{X: rng.Float32() * float32(tm.Width), Y: rng.Float32() * float32(tm.Height)}
+}
+
+func TestReachableMatchesFindPath(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for name, tm := range reachabilityMaps() {
+		p := syncedPathfinder(tm)
+		reached, cut := 0, 0
+		for i := 0; i < 100; i++ {
+			start, goal := randomPoint(rng, tm), randomPoint(rng, tm)
+			for _, canWater := range []bool{false, true} {
+				want := p.FindPath(start, goal, canWater) != nil
+				if got := p.IsReachable(start, goal, canWater); got != want {
+					t.Fatalf("%s: %v to %v (water %v): IsReachable %v, FindPath found a path %v", name, start, goal, canWater, got, want)
+				}
+				if want {
+					reached++
+				} else {
+					cut++
+				}
+			}
+		}
+		if reached == 0 || cut == 0 {
+			t.Errorf("%s: %d pairs reachable, %d not, want some of each", name, reached, cut)
+		}
+	}
+
+	p := syncedPathfinder(tilemap.NewTileMap(10, 10))
+	if at := (rl.Vector2{X: 3.2, Y: 3.7}); !p.IsReachable(at, rl.Vector2{X: 3.9, Y: 3.1}, false) {
+		t.Error("a cell isn't reachable from itself")
+	}
+}
+
+func BenchmarkReachability(b *testing.B) {
+	tm := tilemap.GenerateProcedural(64, 48, 1)
+	p := syncedPathfinder(tm)
+	rng := rand.New(rand.NewSource(1))
+	pairs := make([][2]rl.Vector2, 50)
+	for i := range pairs {
+		pairs[i] = [2]rl.Vector2{randomPoint(rng, tm), randomPoint(rng, tm)}
+	}
+
+	b.Run("FindPath", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, pair := range pairs {
+				p.FindPath(pair[0], pair[1], false)
+			}
+		}
+	})
+	b.Run("IsReachable", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, pair := range pairs {
+				p.IsReachable(pair[0], pair[1], false)
+			}
+		}
+	})
+}
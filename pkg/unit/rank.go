@@ -0,0 +1,60 @@
+package unit
+
+// Rank is a unit's veterancy, earned by destroying enemy units
+type Rank int
+
+const (
+	RankRecruit Rank = iota
+	RankVeteran
+	RankElite
+	RankHero
+)
+
+// rankKills is how many kills each rank takes
+var rankKills = [...]int{
+	RankRecruit: 0,
+	RankVeteran: 2,
+	RankElite:   5,
+	RankHero:    10,
+}
+
+// String returns the rank's display name
+func (r Rank) String() string {
+	switch r {
+	case RankVeteran:
+		return "Veteran"
+	case RankElite:
+		return "Elite"
+	case RankHero:
+		return "Hero"
+	default:
+		return "Recruit"
+	}
+}
+
+// Chevrons returns how many chevrons mark the rank, none for recruits
+func (r Rank) Chevrons() int {
+	if r < RankRecruit {
+		return 0
+	}
+	if r > RankHero {
+		return int(RankHero)
+	}
+	return int(r)
+}
+
+// RankForKills returns the rank a kill count has earned
+func RankForKills(kills int) Rank {
+	rank := RankRecruit
+	for r, needed := range rankKills {
+		if kills >= needed {
+			rank = Rank(r)
+		}
+	}
+	return rank
+}
+
+// Rank returns the unit's current veterancy
+func (u *Unit) Rank() Rank {
+	return RankForKills(u.Kills)
+}
@@ -5,14 +5,54 @@ import (
 	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
+)
+
+// HealthBarMode controls which units draw health bars
+type HealthBarMode int
+
+const (
+	HealthBarsRelevant HealthBarMode = iota // Only damaged, selected, or hovered units
+	HealthBarsAlways                        // Every living unit
 )
 
 // Renderer handles unit rendering
-type Renderer struct{}
+type Renderer struct {
+	ShowSelectedPaths bool          // Draw movement paths for the current selection
+	ShowOrderLines    bool          // Draw lines from selected units to what their orders aim at
+	HealthBars        HealthBarMode // Which units get health bars
+	Hovered           *Unit         // Unit under the mouse cursor (set externally)
+
+	// Rank chevrons are hidden when zoomed out past RankMaxZoom
+	Zoom        float32 // Camera zoom level (set externally)
+	RankMaxZoom float32
+
+	// Visibility hides enemy units and their effects where the player
+	// can't see (nil = show all)
+	Visibility graphics.Visibility
+
+	// Spotted reports whether the player has made out an enemy unit in
+	// sight, concealed ones only up close (nil = all in sight)
+	Spotted func(*Unit) bool
+
+	Graphics graphics.Settings
+}
+
+// motorcycleMaxLean is the furthest a motorcycle leans into a turn, radians
+const motorcycleMaxLean = 0.3
+
+// defaultShadowRadius is the shadow footprint of units without a hitbox size
+const defaultShadowRadius = 0.5
 
 // NewRenderer creates a new unit renderer
 func NewRenderer() *Renderer {
-	return &Renderer{}
+	return &Renderer{
+		ShowOrderLines: true,
+		Zoom:           1.0,
+		RankMaxZoom:    1.5,
+		Graphics:       graphics.DefaultSettings(),
+	}
 }
 
 // Draw renders all units from a manager
@@ -20,17 +60,105 @@ func (r *Renderer) Draw(m *Manager) {
 	for _, u := range m.GetUnits() {
 		r.DrawUnit(u)
 	}
+
+	if r.ShowSelectedPaths {
+		r.drawSelectedPaths(m)
+	}
+	if r.ShowOrderLines {
+		r.drawOrderLines(m)
+	}
+}
+
+// orderLineHeight lifts order lines clear of the terrain
+const orderLineHeight = 0.3
+
+// orderLineColor returns the color order lines of a kind are drawn in
+func orderLineColor(order Order) rl.Color {
+	switch order {
+	case OrderAttackHQ, OrderAttackNearest:
+		return rl.Red
+	case OrderCaptureOutpost:
+		return rl.Gold
+	case OrderDefendPosition:
+		return rl.SkyBlue
+	case OrderPatrolArea:
+		return rl.Lime
+	case OrderGuard:
+		return rl.Violet
+	case OrderGarrison:
+		return rl.Orange
+	default:
+		return rl.White
+	}
+}
+
+// drawOrderLines draws an arrow from each selected unit to what its order
+// is aimed at, colored by order
+func (r *Renderer) drawOrderLines(m *Manager) {
+	for _, u := range m.GetSelected() {
+		if u.IsCarried() {
+			continue
+		}
+		order, end, ok := u.OrderLine()
+		if !ok {
+			continue
+		}
+
+		start := rl.Vector3{X: u.Position.X, Y: orderLineHeight, Z: u.Position.Z}
+		end.Y = orderLineHeight
+		color := orderLineColor(order)
+		rl.DrawLine3D(start, end, color)
+
+		// Arrowhead, two short barbs swept back from the end
+		dir := rl.Vector3Subtract(end, start)
+		length := rl.Vector3Length(dir)
+		if length < 0.5 {
+			continue
+		}
+		dir = rl.Vector3Scale(dir, 0.4/length)
+		side := rl.Vector3{X: -dir.Z * 0.6, Z: dir.X * 0.6}
+		back := rl.Vector3Subtract(end, dir)
+		rl.DrawLine3D(end, rl.Vector3Add(back, side), color)
+		rl.DrawLine3D(end, rl.Vector3Subtract(back, side), color)
+	}
+}
+
+// drawSelectedPaths draws where each selected unit is headed
+func (r *Renderer) drawSelectedPaths(m *Manager) {
+	for _, u := range m.GetSelected() {
+		// Idle units have nothing to show
+		if !u.HasActivePath() {
+			continue
+		}
+		r.DrawDebugPath(u)
+	}
 }
 
 // DrawUnit renders a single unit
 func (r *Renderer) DrawUnit(u *Unit) {
+	if !r.IsSeen(u) {
+		return
+	}
 	if u.IsDead() {
 		r.drawDeadUnit(u)
 		return
 	}
 
-	// Get colors based on team
+	if r.ShowsShadow(u) {
+		r.drawShadow(u)
+	}
+
+	// Get colors based on team, veterans get brighter trim
 	mainColor, trimColor := r.getTeamColors(u.Team)
+	trimColor = rankTrim(trimColor, u.Rank())
+
+	// Emerging units rise out of the ground
+	if u.IsSpawning() {
+		r.drawSpawnPad(u)
+		rl.PushMatrix()
+		rl.Translatef(0, -spawnSinkDepth*(1-u.SpawnProgress()), 0)
+		defer rl.PopMatrix()
+	}
 
 	// Draw based on unit type
 	switch u.Config.Type {
@@ -48,20 +176,90 @@ func (r *Renderer) DrawUnit(u *Unit) {
 		r.drawSupply(u, mainColor, trimColor)
 	}
 
+	// Draw selection ring
+	if u.Selected {
+		r.drawSelectionRing(u)
+	}
+
 	// Draw health bar
-	r.drawHealthBar(u)
+	if r.ShowsHealthBar(u) {
+		r.drawHealthBar(u)
+	}
 
 	// Draw attack effect if attacking
-	if u.State == StateAttacking && u.Target != nil {
+	if r.ShowsAttackEffect(u) {
 		r.drawAttackEffect(u)
 	}
 }
 
+// IsSeen returns true if the player can see a unit: it's theirs, or it's
+// somewhere they can see and, if alive, spotted
+func (r *Renderer) IsSeen(u *Unit) bool {
+	if u.Team == TeamPlayer {
+		return true
+	}
+	if !graphics.IsVisible(r.Visibility, u.Position) {
+		return false
+	}
+	return u.IsDead() || r.Spotted == nil || r.Spotted(u)
+}
+
+// ShowsAttackEffect returns true if a unit's attack line should be drawn:
+// it's attacking and the player can see it
+func (r *Renderer) ShowsAttackEffect(u *Unit) bool {
+	if u.State != StateAttacking || u.Target == nil {
+		return false
+	}
+	return r.IsSeen(u)
+}
+
+// ShowsShadow returns true if a unit casts a shadow: shadows are on, the
+// unit is alive and above ground, and the player can see it
+func (r *Renderer) ShowsShadow(u *Unit) bool {
+	if !r.Graphics.Shadows || u.IsDead() || u.IsSpawning() {
+		return false
+	}
+	return r.IsSeen(u)
+}
+
+// drawShadow casts a unit's shadow sized by its footprint, carried units
+// casting smaller ones from the air
+func (r *Renderer) drawShadow(u *Unit) {
+	radius := u.Config.HitboxRadius
+	if radius <= 0 {
+		radius = defaultShadowRadius
+	}
+	graphics.DrawShadow(graphics.ShadowFor(u.Position, radius))
+}
+
+// spawnSinkDepth is how far below ground a unit starts emerging from
+const spawnSinkDepth = 0.6
+
+// drawSpawnPad draws a shrinking ring under a unit that is still emerging
+func (r *Renderer) drawSpawnPad(u *Unit) {
+	radius := u.Config.HitboxRadius
+	if radius <= 0 {
+		radius = 0.5
+	}
+	radius *= 2 - u.SpawnProgress()
+	alpha := uint8(255 * (1 - u.SpawnProgress()))
+	rl.DrawCircle3D(rl.NewVector3(u.Position.X, 0.02, u.Position.Z), radius, rl.NewVector3(1, 0, 0), 90, rl.NewColor(255, 255, 255, alpha))
+}
+
+// rankTrim brightens a trim color toward gold with each rank
+func rankTrim(trim rl.Color, rank Rank) rl.Color {
+	return graphics.LerpColor(trim, rl.Gold, 0.2*float32(rank.Chevrons()))
+}
+
 func (r *Renderer) getTeamColors(team Team) (main, trim rl.Color) {
-	if team == TeamPlayer {
+	switch team {
+	case TeamPlayer:
 		return rl.Blue, rl.DarkBlue
+	case TeamNeutral:
+		return rl.LightGray, rl.DarkGray
+	default:
+		return rl.Red, rl.Maroon
 	}
-	return rl.Red, rl.Maroon
 }
 
 func (r *Renderer) drawInfantry(u *Unit, main, trim rl.Color) {
@@ -114,10 +312,13 @@ func (r *Renderer) drawTank(u *Unit, main, trim rl.Color) {
 func (r *Renderer) drawMotorcycle(u *Unit, main, trim rl.Color) {
 	pos := u.Position
 	rot := u.Rotation * 180.0 / math.Pi
+	lean := graphics.Degrees(graphics.BankAngle(u.TurnRate, u.Speed(), motorcycleMaxLean))
 
+	// Lean into turns from the wheels up
 	rl.PushMatrix()
 	rl.Translatef(pos.X, pos.Y, pos.Z)
 	rl.Rotatef(rot, 0, 1, 0)
+	rl.Rotatef(lean, 0, 0, 1)
 
 	// Frame
 	rl.DrawCube(rl.NewVector3(0, 0.15, 0), 0.15, 0.1, 0.5, main)
@@ -211,6 +412,23 @@ func (r *Renderer) drawDeadUnit(u *Unit) {
 	// Smoke effect would go here
 }
 
+func (r *Renderer) drawSelectionRing(u *Unit) {
+	center := rl.Vector3{X: u.Position.X, Y: u.Position.Y + 0.05, Z: u.Position.Z}
+	radius := u.Config.HitboxRadius + 0.15
+	rl.DrawCircle3D(center, radius, rl.Vector3{X: 1, Y: 0, Z: 0}, 90, rl.Green)
+}
+
+// ShowsHealthBar returns true if a unit's health bar should be drawn
+func (r *Renderer) ShowsHealthBar(u *Unit) bool {
+	if u.IsDead() || u.IsSpawning() || !r.IsSeen(u) {
+		return false
+	}
+	if r.HealthBars == HealthBarsAlways {
+		return true
+	}
+	return u.Health < u.MaxHealth || u.Selected || u == r.Hovered
+}
+
 func (r *Renderer) drawHealthBar(u *Unit) {
 	// Position health bar above unit
 	pos := u.Position
@@ -266,6 +484,40 @@ func (r *Renderer) drawAttackEffect(u *Unit) {
 	rl.DrawLine3D(startPos, endPos, color)
 }
 
+// ShowsRank returns true if a unit's rank chevrons should be drawn: it has
+// earned a rank and the camera is close enough to read them
+func (r *Renderer) ShowsRank(u *Unit) bool {
+	if u.IsDead() || u.IsSpawning() || u.IsCarried() || !r.IsSeen(u) {
+		return false
+	}
+	return u.Rank().Chevrons() > 0 && r.Zoom <= r.RankMaxZoom
+}
+
+// DrawRanks draws chevrons over veteran units. Call it in 2D mode; the
+// chevrons are projected from world space so they stay upright and
+// readable whichever way the camera faces.
+func (r *Renderer) DrawRanks(m *Manager, camera rl.Camera3D) {
+	// Chevrons shrink as the camera pulls back
+	size := 6 / r.Zoom
+	for _, u := range m.GetUnits() {
+		if !r.ShowsRank(u) {
+			continue
+		}
+
+		pos := u.Position
+		pos.Y += 1.0 // Above the health bar
+		screen := rl.GetWorldToScreen(pos, camera)
+		for i := 0; i < u.Rank().Chevrons(); i++ {
+			y := screen.Y - float32(i)*size*0.9
+			left := rl.Vector2{X: screen.X - size, Y: y + size/2}
+			tip := rl.Vector2{X: screen.X, Y: y - size/2}
+			right := rl.Vector2{X: screen.X + size, Y: y + size/2}
+			rl.DrawLineEx(left, tip, 2, rl.Gold)
+			rl.DrawLineEx(tip, right, 2, rl.Gold)
+		}
+	}
+}
+
 // DrawUI renders unit-related UI elements
 func (r *Renderer) DrawUI(m *Manager, screenWidth, screenHeight int) {
 	// Unit count display
@@ -274,6 +526,62 @@ func (r *Renderer) DrawUI(m *Manager, screenWidth, screenHeight int) {
 
 	unitText := fmt.Sprintf("Units - Player: %d | Enemy: %d", playerCount, enemyCount)
 	rl.DrawText(unitText, int32(screenWidth-200), 40, 15, rl.White)
+
+	r.drawArmyPanel(m.Summary(TeamPlayer), int32(screenWidth-200), 62)
+}
+
+// drawArmyPanel renders the player's army summary: overall health, what the
+// army is doing, and a count per unit type
+func (r *Renderer) drawArmyPanel(s ArmySummary, x, y int32) {
+	const (
+		width      = 190
+		lineHeight = 16
+		fontSize   = 12
+	)
+
+	types := make([]UnitType, 0, len(s.ByType))
+	for t := TypeInfantry; t <= TypeSupply; t++ {
+		if s.ByType[t] > 0 {
+			types = append(types, t)
+		}
+	}
+
+	height := int32(3+len(types))*lineHeight + 8
+	if s.Total == 0 {
+		height = lineHeight + 8
+	}
+	rl.DrawRectangle(x-5, y-4, width, height, rl.Color{R: 0, G: 0, B: 0, A: 150})
+
+	if s.Total == 0 {
+		rl.DrawText("Army: no units", x, y, fontSize, rl.LightGray)
+		return
+	}
+
+	rl.DrawText(fmt.Sprintf("Army: %d  Selected: %d", s.Total, s.Selected), x, y, fontSize, rl.White)
+	y += lineHeight
+
+	// Health bar, colored like the unit health bars
+	frac := s.AverageHealth()
+	var barColor rl.Color
+	if frac > 0.6 {
+		barColor = rl.Green
+	} else if frac > 0.3 {
+		barColor = rl.Yellow
+	} else {
+		barColor = rl.Red
+	}
+	rl.DrawRectangle(x, y+2, 100, 8, rl.DarkGray)
+	rl.DrawRectangle(x, y+2, int32(100*frac), 8, barColor)
+	rl.DrawText(fmt.Sprintf("%.0f%%", frac*100), x+106, y, fontSize, rl.White)
+	y += lineHeight
+
+	rl.DrawText(fmt.Sprintf("Fighting: %d  Idle: %d", s.InCombat, s.Idle), x, y, fontSize, rl.LightGray)
+	y += lineHeight
+
+	for _, t := range types {
+		rl.DrawText(fmt.Sprintf("%s: %d", t, s.ByType[t]), x, y, fontSize, rl.LightGray)
+		y += lineHeight
+	}
 }
 
 // DrawDebugPath draws a unit's current path (for debugging)
@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DefaultUnitRadius is how much room each unit keeps to itself by default
+const DefaultUnitRadius = 0.4
+
+// separationStrength is the share of an overlap resolved each frame, so
+// crowds spread out over a few frames instead of jumping apart
+const separationStrength = 0.5
+
+// separate pushes apart units that overlap, boids style: any two closer
+// than twice UnitRadius are each moved half the remaining overlap away from
+// the other. Neighbours come from the spatial grid, so this is O(n) for
+// units spread over the map and O(n²) only when everything piles onto one
+// spot. Dead and carried units are left alone, and no unit is pushed onto
+// ground it can't stand on. Returns true if any unit moved.
+func (m *Manager) separate() bool {
+	if m.UnitRadius <= 0 {
+		return false
+	}
+	spacing := 2 * m.UnitRadius
+
+	moved := false
+	var nearby []*Unit
+	for _, u := range m.units {
+		if u.IsDead() || u.IsCarried() {
+			continue
+		}
+		nearby = m.grid.QueryRadius(u.Position, spacing, nearby[:0])
+		for _, other := range nearby {
+			// Each pair once, from the lower ID
+			if other.ID <= u.ID || other.IsDead() || other.IsCarried() {
+				continue
+			}
+			if m.pushApart(u, other, spacing) {
+				moved = true
+			}
+		}
+	}
+	return moved
+}
+
+// pushApart moves two units away from each other if they're closer than
+// spacing, returning true if they were. Units on exactly the same spot
+// split along the X axis, lower ID to the left, so the result doesn't
+// depend on chance.
+func (m *Manager) pushApart(a, b *Unit, spacing float32) bool {
+	dx := b.Position.X - a.Position.X
+	dz := b.Position.Z - a.Position.Z
+	dist := float32(math.Sqrt(float64(dx*dx + dz*dz)))
+	if dist >= spacing {
+		return false
+	}
+	if dist == 0 {
+		dx, dz, dist = 1, 0, 1
+	}
+
+	push := (spacing - dist) * separationStrength / 2
+	nx, nz := dx/dist*push, dz/dist*push
+	m.nudge(a, -nx, -nz)
+	m.nudge(b, nx, nz)
+	return true
+}
+
+// nudge moves a unit by an offset unless that would put it on ground it
+// can't stand on
+func (m *Manager) nudge(u *Unit, dx, dz float32) {
+	x, z := u.Position.X+dx, u.Position.Z+dz
+	if m.Pathfinder != nil {
+		gx, gy := m.Pathfinder.WorldToGrid(rl.Vector2{X: x, Y: z})
+		if m.Pathfinder.isImpassable(gx, gy, u.Config.CanTraverseWater) {
+			return
+		}
+	}
+	u.Position.X = x
+	u.Position.Z = z
+}
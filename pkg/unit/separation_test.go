@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestStackedUnitsSeparate(t *testing.T) {
+	m := NewManager(10)
+	a := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+	b := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+	for _, u := range []*Unit{a, b} {
+		u.SpawnTimer = 0
+		u.State = StateIdle
+	}
+
+	for i := 0; i < 15; i++ {
+		m.Update(1.0 / 60)
+	}
+
+	// Each frame resolves half the overlap, so allow what's left of it
+	if dist := a.DistanceTo(b); dist < 2*m.UnitRadius-0.01 {
+		t.Errorf("stacked units only %.3f apart, want %.2f", dist, 2*m.UnitRadius)
+	}
+}
+
+func TestSeparationSkipsCarriedUnits(t *testing.T) {
+	m := NewManager(10)
+	a := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+	b := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+	a.SpawnTimer = 0
+	a.State = StateIdle
+	b.SpawnTimer = 0
+	b.State = StateBeingCarried
+	m.grid.Rebuild(m.units)
+
+	if m.separate() {
+		t.Error("separation pushed a unit against one being carried")
+	}
+	if a.Position != b.Position {
+		t.Error("carried unit was pushed apart from the one beneath it")
+	}
+}
+
+func TestZeroUnitRadiusDisablesSeparation(t *testing.T) {
+	m := NewManager(10)
+	m.UnitRadius = 0
+	a := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+	b := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+	m.grid.Rebuild(m.units)
+
+	if m.separate() || a.Position != b.Position {
+		t.Error("units separated with no unit radius")
+	}
+}
@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ShotHeight is how far above its position a unit fires from, and above
+// the target's position it aims
+const ShotHeight = 0.3
+
+// Shot is a projectile a unit fired, for the combat system to put in flight.
+// The unit package doesn't own projectiles, it only says what was fired.
+type Shot struct {
+	From      rl.Vector3
+	Velocity  rl.Vector3
+	Damage    float32
+	Range     float32 // How far it flies before coming down
+	Type      UnitType
+	Team      Team
+	ShooterID uint32
+}
+
+// FiresProjectiles returns true if the unit's attacks are projectiles in
+// flight rather than instant hits
+func (u *Unit) FiresProjectiles() bool {
+	return u.Config.ProjectileSpeed > 0
+}
+
+// FireAt fires a projectile at a point, starting the attack cooldown, and
+// returns the shot for the caller to put in flight
+func (u *Unit) FireAt(point rl.Vector3) Shot {
+	from := rl.Vector3{X: u.Position.X, Y: u.Position.Y + ShotHeight, Z: u.Position.Z}
+	to := rl.Vector3{X: point.X, Y: point.Y + ShotHeight, Z: point.Z}
+
+	dir := rl.Vector3Subtract(to, from)
+	if length := rl.Vector3Length(dir); length > 0 {
+		dir = rl.Vector3Scale(dir, 1/length)
+	} else {
+		// Point blank, fire the way the unit faces
+		dir = rl.Vector3{X: float32(math.Sin(float64(u.Rotation))), Z: float32(math.Cos(float64(u.Rotation)))}
+	}
+
+	shot := Shot{
+		From:      from,
+		Velocity:  rl.Vector3Scale(dir, u.Config.ProjectileSpeed),
+		Damage:    u.Config.AttackDamage,
+		Range:     u.Config.AttackRange * shotRangeSlack,
+		Type:      u.Config.Type,
+		Team:      u.Team,
+		ShooterID: u.ID,
+	}
+
+	u.State = StateAttacking
+	u.AttackCooldown = 1.0 / u.Config.AttackRate
+	u.RevealTimer = FireRevealTime
+	return shot
+}
+
+// shotRangeSlack lets a shot fly a little past the unit's attack range, so
+// a target at the edge of range moving away can still be hit
+const shotRangeSlack = 1.25
+
+// collectShots moves the shots units fired this frame into the manager
+func (m *Manager) collectShots() {
+	for _, u := range m.units {
+		m.shots = append(m.shots, u.shots...)
+		u.shots = u.shots[:0]
+	}
+}
+
+// Shots returns the projectiles units fired during the last Update
+func (m *Manager) Shots() []Shot {
+	return m.shots
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestAttackFiresShotOnCooldown(t *testing.T) {
+	m := NewManager(10)
+	enemy := m.Spawn(TypeInfantry, TeamEnemy, rl.NewVector3(0, 0, 5))
+	tank := m.Spawn(TypeTank, TeamPlayer, rl.NewVector3(0, 0, 0))
+	for _, u := range []*Unit{enemy, tank} {
+		u.SpawnTimer = 0
+		u.State = StateIdle
+	}
+	tank.Target = enemy
+
+	m.updateCombat(0.01)
+	m.collectShots()
+	shots := m.Shots()
+	if len(shots) != 1 {
+		t.Fatalf("tank in range fired %d shots, want 1", len(shots))
+	}
+	if enemy.Health != enemy.MaxHealth {
+		t.Error("projectile attack hit instantly")
+	}
+	if shot := shots[0]; shot.ShooterID != tank.ID || shot.Team != TeamPlayer || shot.Velocity.Z <= 0 {
+		t.Errorf("shot %+v not fired by the tank toward its target", shot)
+	}
+	if tank.AttackCooldown <= 0 {
+		t.Error("firing didn't start the attack cooldown")
+	}
+
+	// Nothing more until the cooldown runs out
+	m.shots = m.shots[:0]
+	m.updateCombat(0.01)
+	m.collectShots()
+	if n := len(m.Shots()); n != 0 {
+		t.Errorf("tank fired %d shots while cooling down", n)
+	}
+}
+
+func TestInstantAttackWithoutProjectileSpeed(t *testing.T) {
+	m := NewManager(10)
+	enemy := m.Spawn(TypeInfantry, TeamEnemy, rl.NewVector3(0, 0, 1))
+	tank := m.Spawn(TypeTank, TeamPlayer, rl.NewVector3(0, 0, 0))
+	for _, u := range []*Unit{enemy, tank} {
+		u.SpawnTimer = 0
+		u.State = StateIdle
+	}
+	tank.Config.ProjectileSpeed = 0
+	tank.Target = enemy
+
+	m.updateCombat(0.01)
+	m.collectShots()
+	if n := len(m.Shots()); n != 0 {
+		t.Errorf("unit without a projectile speed fired %d shots", n)
+	}
+	if enemy.Health == enemy.MaxHealth {
+		t.Error("instant attack didn't hurt its target")
+	}
+}
@@ -0,0 +1,194 @@
+package unit
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DefaultGridCellSize is the width of a spatial grid cell in world units,
+// about the reach of a typical neighbour query
+const DefaultGridCellSize = 4.0
+
+// gridCell is a spatial grid cell coordinate
+type gridCell struct{ x, z int }
+
+// SpatialGrid is a uniform spatial hash of units on the ground plane,
+// covering just the cells between the outermost units. It holds positions
+// as of the last Rebuild or Insert: queries check each candidate's current
+// position, but a unit that has moved far since then can be missed until
+// the grid is rebuilt.
+type SpatialGrid struct {
+	cellSize float32
+
+	origin        gridCell // Cell coordinate of cells[0]
+	width, height int
+	cells         [][]*Unit // Row-major, each in insertion order
+	count         int
+}
+
+// NewSpatialGrid creates an empty grid with cells of the given size
+func NewSpatialGrid(cellSize float32) *SpatialGrid {
+	return &SpatialGrid{cellSize: cellSize}
+}
+
+// cellOf returns the cell a position falls in
+func (g *SpatialGrid) cellOf(pos rl.Vector3) gridCell {
+	return gridCell{
+		x: int(math.Floor(float64(pos.X / g.cellSize))),
+		z: int(math.Floor(float64(pos.Z / g.cellSize))),
+	}
+}
+
+// contains returns true if a cell is inside the grid's bounds
+func (g *SpatialGrid) contains(c gridCell) bool {
+	return c.x >= g.origin.x && c.x < g.origin.x+g.width && c.z >= g.origin.z && c.z < g.origin.z+g.height
+}
+
+// Insert adds a unit to the cell it's in now, growing the grid if the unit
+// is outside it
+func (g *SpatialGrid) Insert(u *Unit) {
+	cell := g.cellOf(u.Position)
+	if g.count == 0 && !g.contains(cell) {
+		g.resize(cell, cell)
+	} else if !g.contains(cell) {
+		g.grow(cell)
+	}
+	i := (cell.z-g.origin.z)*g.width + (cell.x - g.origin.x)
+	g.cells[i] = append(g.cells[i], u)
+	g.count++
+}
+
+// Remove takes a unit out of the grid, looking in the cell it's in now
+// first and then everywhere, as it may have moved since it was inserted.
+// Returns false if the unit isn't in the grid.
+func (g *SpatialGrid) Remove(u *Unit) bool {
+	if cell := g.cellOf(u.Position); g.contains(cell) {
+		if g.removeAt((cell.z-g.origin.z)*g.width+(cell.x-g.origin.x), u) {
+			return true
+		}
+	}
+	for i := range g.cells {
+		if g.removeAt(i, u) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeAt takes a unit out of one cell, keeping the others in order
+func (g *SpatialGrid) removeAt(i int, u *Unit) bool {
+	j := slices.Index(g.cells[i], u)
+	if j < 0 {
+		return false
+	}
+	g.cells[i] = slices.Delete(g.cells[i], j, j+1)
+	g.count--
+	return true
+}
+
+// Rebuild refills the grid from units, in their order, sized to fit them.
+// Cell storage is kept for reuse.
+func (g *SpatialGrid) Rebuild(units []*Unit) {
+	g.Clear()
+	if len(units) == 0 {
+		return
+	}
+
+	lo := g.cellOf(units[0].Position)
+	hi := lo
+	for _, u := range units[1:] {
+		c := g.cellOf(u.Position)
+		lo.x, lo.z = min(lo.x, c.x), min(lo.z, c.z)
+		hi.x, hi.z = max(hi.x, c.x), max(hi.z, c.z)
+	}
+	g.resize(lo, hi)
+
+	for _, u := range units {
+		g.Insert(u)
+	}
+}
+
+// Clear empties the grid
+func (g *SpatialGrid) Clear() {
+	for i := range g.cells {
+		g.cells[i] = g.cells[i][:0]
+	}
+	g.count = 0
+}
+
+// resize sets the grid's bounds to the cells from lo to hi, dropping
+// everything in it
+func (g *SpatialGrid) resize(lo, hi gridCell) {
+	g.origin = lo
+	g.width = hi.x - lo.x + 1
+	g.height = hi.z - lo.z + 1
+
+	n := g.width * g.height
+	if n > cap(g.cells) {
+		g.cells = append(g.cells[:cap(g.cells)], make([][]*Unit, n-cap(g.cells))...)
+	}
+	g.cells = g.cells[:n]
+	g.Clear()
+}
+
+// grow widens the grid to take in a cell, keeping the units it holds
+func (g *SpatialGrid) grow(cell gridCell) {
+	held := make([]*Unit, 0, g.count)
+	for _, units := range g.cells {
+		held = append(held, units...)
+	}
+
+	lo := gridCell{min(g.origin.x, cell.x), min(g.origin.z, cell.z)}
+	hi := gridCell{max(g.origin.x+g.width-1, cell.x), max(g.origin.z+g.height-1, cell.z)}
+	g.resize(lo, hi)
+	for _, u := range held {
+		g.Insert(u)
+	}
+}
+
+// QueryRadius appends to result every unit within radius of a point on the
+// ground plane, dead or alive. Units come cell by cell, each cell in the
+// order its units were inserted, so the same grid and query always give
+// the same order, though not sorted by ID.
+func (g *SpatialGrid) QueryRadius(center rl.Vector3, radius float32, result []*Unit) []*Unit {
+	if g.count == 0 {
+		return result
+	}
+	lo := g.cellOf(rl.Vector3{X: center.X - radius, Z: center.Z - radius})
+	hi := g.cellOf(rl.Vector3{X: center.X + radius, Z: center.Z + radius})
+	lo.x, lo.z = max(lo.x, g.origin.x), max(lo.z, g.origin.z)
+	hi.x, hi.z = min(hi.x, g.origin.x+g.width-1), min(hi.z, g.origin.z+g.height-1)
+
+	r2 := radius * radius
+	for z := lo.z; z <= hi.z; z++ {
+		row := (z - g.origin.z) * g.width
+		for x := lo.x; x <= hi.x; x++ {
+			for _, u := range g.cells[row+x-g.origin.x] {
+				dx := u.Position.X - center.X
+				dz := u.Position.Z - center.Z
+				if dx*dx+dz*dz <= r2 {
+					result = append(result, u)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// QueryRadius returns every unit within radius of a point, dead or alive,
+// in ascending ID order. Backed by the spatial grid, which is refreshed
+// after units move each Update.
+func (m *Manager) QueryRadius(center rl.Vector3, radius float32) []*Unit {
+	result := m.grid.QueryRadius(center, radius, make([]*Unit, 0))
+	slices.SortFunc(result, func(a, b *Unit) int { return cmp.Compare(a.ID, b.ID) })
+	return result
+}
+
+// closer reports whether a candidate at dist beats the best so far, ties
+// going to the lower ID so the pick doesn't depend on query order
+func closer(u *Unit, dist float32, best *Unit, bestDist float32) bool {
+	return dist < bestDist || (dist == bestDist && best != nil && u.ID < best.ID)
+}
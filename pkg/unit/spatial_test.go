@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// scatterUnits spawns n units at random spots on a size by size field
+func scatterUnits(n int, size float32, seed int64) *Manager {
+	rng := rand.New(rand.NewSource(seed))
+	m := NewManager(n)
+	for i := 0; i < n; i++ {
+		pos := rl.NewVector3(rng.Float32()*size, 0, rng.Float32()*size)
+		m.Spawn(TypeInfantry, Team(i%2), pos)
+	}
+	return m
+}
+
+// bruteForceRadius is the linear scan the grid replaced
+func bruteForceRadius(units []*Unit, center rl.Vector3, radius float32) []*Unit {
+	var result []*Unit
+	for _, u := range units {
+		dx, dz := u.Position.X-center.X, u.Position.Z-center.Z
+		if dx*dx+dz*dz <= radius*radius {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+func sameUnits(a, b []*Unit) bool {
+	ids := func(units []*Unit) []uint32 {
+		out := make([]uint32, len(units))
+		for i, u := range units {
+			out[i] = u.ID
+		}
+		slices.Sort(out)
+		return out
+	}
+	return slices.Equal(ids(a), ids(b))
+}
+
+func TestQueryRadiusMatchesBruteForce(t *testing.T) {
+	m := scatterUnits(300, 60, 1)
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		center := rl.NewVector3(rng.Float32()*70-5, 0, rng.Float32()*70-5)
+		radius := rng.Float32() * 12
+		got := m.QueryRadius(center, radius)
+		want := bruteForceRadius(m.GetUnits(), center, radius)
+		if !sameUnits(got, want) {
+			t.Fatalf("query at %v radius %v: grid found %d units, brute force %d", center, radius, len(got), len(want))
+		}
+	}
+}
+
+func TestDetachedUnitLeavesGrid(t *testing.T) {
+	m := NewManager(10)
+	u := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(5, 0, 5))
+
+	// Moved since it was inserted, it must still come out
+	u.Position = rl.NewVector3(9, 0, 9)
+	if m.Detach(u.ID) == nil {
+		t.Fatal("detach failed")
+	}
+	if found := m.QueryRadius(rl.NewVector3(5, 0, 5), 10); len(found) != 0 {
+		t.Fatalf("detached unit still found by %d queries", len(found))
+	}
+
+	if !m.Attach(u, rl.NewVector3(20, 0, 20)) {
+		t.Fatal("attach failed")
+	}
+	if found := m.QueryRadius(rl.NewVector3(20, 0, 20), 1); len(found) != 1 || found[0] != u {
+		t.Fatal("attached unit isn't found where it was put")
+	}
+}
+
+func BenchmarkRadiusQuery(b *testing.B) {
+	for _, n := range []int{100, 500} {
+		m := scatterUnits(n, 80, 1)
+		units := m.GetUnits()
+
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, u := range units {
+					bruteForceRadius(units, u.Position, DefaultGridCellSize)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("grid/%d", n), func(b *testing.B) {
+			var result []*Unit
+			for i := 0; i < b.N; i++ {
+				for _, u := range units {
+					result = m.grid.QueryRadius(u.Position, DefaultGridCellSize, result[:0])
+				}
+			}
+		})
+	}
+}
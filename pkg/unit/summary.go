@@ -0,0 +1,59 @@
+package unit
+
+// ArmySummary is one team's units in aggregate, for the HUD
+type ArmySummary struct {
+	Total    int
+	ByType   map[UnitType]int
+	Selected int
+	InCombat int // Attacking or holding a target
+	Idle     int
+
+	Health    float32 // Sum over all units
+	MaxHealth float32
+}
+
+// AverageHealth returns the army's health as a fraction of its maximum,
+// 0 with no units
+func (s ArmySummary) AverageHealth() float32 {
+	if s.MaxHealth <= 0 {
+		return 0
+	}
+	return s.Health / s.MaxHealth
+}
+
+// Summarize aggregates a team's living units. Carried units still count,
+// spawning ones don't until they're out.
+func Summarize(units []*Unit, team Team) ArmySummary {
+	s := ArmySummary{ByType: make(map[UnitType]int)}
+	for _, u := range units {
+		if u.Team != team || u.IsDead() || u.IsSpawning() {
+			continue
+		}
+
+		s.Total++
+		s.ByType[u.Config.Type]++
+		s.Health += u.Health
+		s.MaxHealth += u.MaxHealth
+		if u.Selected {
+			s.Selected++
+		}
+
+		switch {
+		case u.State == StateAttacking || u.Target != nil:
+			s.InCombat++
+		case u.State == StateIdle:
+			s.Idle++
+		}
+	}
+	return s
+}
+
+// Summary returns a team's army summary, computed at most once per Update
+func (m *Manager) Summary(team Team) ArmySummary {
+	if s, ok := m.summaries[team]; ok {
+		return s
+	}
+	s := Summarize(m.units, team)
+	m.summaries[team] = s
+	return s
+}
@@ -10,14 +10,18 @@ func GetConfig(t UnitType) Config {
 			TurnSpeed:       4.0,
 			CanTraverseWater: false,
 			AttackRange:     3.0,
+			EngageDistance:  2.4,
 			AttackDamage:    5.0,
 			AttackRate:      1.5,
+			ProjectileSpeed: 15.0,
 			CanAttackAir:    false,
 			CanAttackGround: true,
 			MaxHealth:       30.0,
 			Armor:           0.0,
+			HitboxRadius:    0.25,
 			CanCapture:      true,
 			Cost:            100,
+			Weight:          1.0,
 		}
 
 	case TypeTank:
@@ -27,14 +31,21 @@ func GetConfig(t UnitType) Config {
 			TurnSpeed:       2.0,
 			CanTraverseWater: false,
 			AttackRange:     6.0,
+			EngageDistance:  4.5,
+			StandoffDistance: 2.0,
 			AttackDamage:    20.0,
 			AttackRate:      0.8,
+			ProjectileSpeed: 18.0,
 			CanAttackAir:    false,
 			CanAttackGround: true,
+			DeathBlastRadius: 2.5,
+			DeathBlastDamage: 30.0,
 			MaxHealth:       100.0,
 			Armor:           0.3,
+			HitboxRadius:    0.7,
 			CanCapture:      false,
 			Cost:            400,
+			Weight:          6.0,
 		}
 
 	case TypeMotorcycle:
@@ -44,14 +55,19 @@ func GetConfig(t UnitType) Config {
 			TurnSpeed:       5.0,
 			CanTraverseWater: false,
 			AttackRange:     4.0,
+			EngageDistance:  3.2,
+			StandoffDistance: 2.5,
 			AttackDamage:    8.0,
 			AttackRate:      2.0,
+			ProjectileSpeed: 20.0,
 			CanAttackAir:    false,
 			CanAttackGround: true,
 			MaxHealth:       40.0,
 			Armor:           0.0,
+			HitboxRadius:    0.35,
 			CanCapture:      false,
 			Cost:            200,
+			Weight:          2.0,
 		}
 
 	case TypeSAM:
@@ -61,14 +77,23 @@ func GetConfig(t UnitType) Config {
 			TurnSpeed:       3.0,
 			CanTraverseWater: false,
 			AttackRange:     8.0,
+			EngageDistance:  6.5,
+			StandoffDistance: 3.0,
 			AttackDamage:    25.0,
 			AttackRate:      1.0,
+			ProjectileSpeed: 25.0,
 			CanAttackAir:    true,
 			CanAttackGround: false,
+			InterceptRadius: 2.0,
+			InterceptRate:   0.5,
+			DeathBlastRadius: 2.0,
+			DeathBlastDamage: 20.0,
 			MaxHealth:       50.0,
 			Armor:           0.1,
+			HitboxRadius:    0.6,
 			CanCapture:      false,
 			Cost:            350,
+			Weight:          4.0,
 		}
 
 	case TypeBoat:
@@ -78,14 +103,21 @@ func GetConfig(t UnitType) Config {
 			TurnSpeed:       2.5,
 			CanTraverseWater: true,
 			AttackRange:     5.0,
+			EngageDistance:  4.0,
+			StandoffDistance: 2.0,
 			AttackDamage:    15.0,
 			AttackRate:      1.2,
+			ProjectileSpeed: 18.0,
 			CanAttackAir:    false,
 			CanAttackGround: true,
+			DeathBlastRadius: 2.5,
+			DeathBlastDamage: 25.0,
 			MaxHealth:       60.0,
 			Armor:           0.2,
+			HitboxRadius:    0.6,
 			CanCapture:      false,
 			Cost:            300,
+			Weight:          5.0,
 		}
 
 	case TypeSupply:
@@ -97,12 +129,17 @@ func GetConfig(t UnitType) Config {
 			AttackRange:     0.0,
 			AttackDamage:    0.0,
 			AttackRate:      0.0,
+			ProjectileSpeed: 0.0,
 			CanAttackAir:    false,
 			CanAttackGround: false,
+			SupplyRadius:    3.0,
+			SupplyHealRate:  5.0,
 			MaxHealth:       80.0,
 			Armor:           0.1,
+			HitboxRadius:    0.55,
 			CanCapture:      false,
 			Cost:            250,
+			Weight:          3.0,
 		}
 
 	default:
@@ -111,6 +148,59 @@ func GetConfig(t UnitType) Config {
 	}
 }
 
+// damageMultipliers scales each attacker type's damage against each
+// defender type: infantry shred infantry but barely scratch tanks, tanks
+// crack armor, motorcycles run down soft targets, boats duel boats. Pairs
+// not listed deal normal damage.
+var damageMultipliers = map[UnitType]map[UnitType]float32{
+	TypeInfantry: {
+		TypeInfantry: 1.5,
+		TypeTank:     0.4,
+		TypeBoat:     0.6,
+		TypeSupply:   1.25,
+	},
+	TypeTank: {
+		TypeInfantry:   0.75,
+		TypeTank:       1.5,
+		TypeMotorcycle: 1.25,
+		TypeSAM:        1.5,
+	},
+	TypeMotorcycle: {
+		TypeInfantry: 1.25,
+		TypeTank:     0.5,
+		TypeSAM:      1.5,
+		TypeSupply:   1.5,
+	},
+	TypeBoat: {
+		TypeInfantry: 0.75,
+		TypeBoat:     1.5,
+	},
+}
+
+// airDamageMultipliers scales each attacker type's damage against the mech
+// in jet mode. Types not listed deal normal damage.
+var airDamageMultipliers = map[UnitType]float32{
+	TypeSAM: 2.0,
+}
+
+// DamageMultiplier returns how much of its damage an attacker type deals to
+// a defender type, 1 for an even matchup
+func DamageMultiplier(attacker, defender UnitType) float32 {
+	if mult, ok := damageMultipliers[attacker][defender]; ok {
+		return mult
+	}
+	return 1.0
+}
+
+// AirDamageMultiplier returns how much of its damage an attacker type deals
+// to the mech in jet mode
+func AirDamageMultiplier(attacker UnitType) float32 {
+	if mult, ok := airDamageMultipliers[attacker]; ok {
+		return mult
+	}
+	return 1.0
+}
+
 // TypeName returns the display name for a unit type
 func TypeName(t UnitType) string {
 	return t.String()
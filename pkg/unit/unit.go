@@ -2,8 +2,11 @@ package unit
 
 import (
 	"math"
+	"math/rand"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/graphics"
 )
 
 // Team represents which side a unit belongs to
@@ -12,8 +15,23 @@ type Team int
 const (
 	TeamPlayer Team = iota
 	TeamEnemy
+	TeamNeutral // Map garrisons, hostile to both players
 )
 
+// String returns the display name for a team
+func (t Team) String() string {
+	switch t {
+	case TeamPlayer:
+		return "Player"
+	case TeamEnemy:
+		return "Enemy"
+	case TeamNeutral:
+		return "Neutral"
+	default:
+		return "Unknown"
+	}
+}
+
 // UnitType identifies the kind of unit
 type UnitType int
 
@@ -36,6 +54,7 @@ const (
 	StateDead
 	StateCapturing    // Infantry only
 	StateBeingCarried // Being transported by mech
+	StateSpawning     // Emerging from a base, not yet active
 )
 
 // Order represents the unit's assigned behavior
@@ -48,6 +67,8 @@ const (
 	OrderCaptureOutpost // Capture nearest outpost
 	OrderDefendPosition // Hold current position
 	OrderPatrolArea     // Patrol around drop point
+	OrderGuard          // Escort a friendly unit or the mech
+	OrderGarrison       // Enter a friendly base and join its garrison
 )
 
 // OrderNames returns human-readable order names
@@ -59,6 +80,8 @@ func OrderNames() []string {
 		"Capture Outpost",
 		"Defend Position",
 		"Patrol Area",
+		"Guard",
+		"Garrison",
 	}
 }
 
@@ -75,16 +98,41 @@ type Config struct {
 	AttackRange   float32
 	AttackDamage  float32
 	AttackRate    float32 // attacks per second
+	ProjectileSpeed float32 // 0 = instant hit
 	CanAttackAir  bool
 	CanAttackGround bool
 
+	// Spacing kept from a target: hold and fire at EngageDistance
+	// (0 = AttackRange), back away inside StandoffDistance (0 = close fully)
+	EngageDistance   float32
+	StandoffDistance float32
+
+	// Blast when the unit is destroyed, full damage at the center falling
+	// off to none at the radius (0 = just a wreck)
+	DeathBlastRadius float32
+	DeathBlastDamage float32
+
+	// Field repair, healing friendly units nearby while stopped
+	SupplyRadius   float32 // 0 = can't heal
+	SupplyHealRate float32 // Hit points per second given to each unit in reach
+
+	// Point defense, shooting down enemy projectiles
+	InterceptRadius float32 // 0 = can't intercept
+	InterceptRate   float32 // Interceptions per second
+
 	// Health
 	MaxHealth float32
 	Armor     float32 // damage reduction 0-1
 
+	// Collision
+	HitboxRadius float32 // 0 falls back to the combat default
+
 	// Special
 	CanCapture bool // Infantry only
 	Cost       int  // Resource cost to spawn
+
+	// Transport
+	Weight float32 // Load on a mech carrying the unit
 }
 
 // Unit represents a deployable combat unit
@@ -97,6 +145,7 @@ type Unit struct {
 	Position rl.Vector3
 	Velocity rl.Vector3
 	Rotation float32 // Y-axis rotation in radians
+	TurnRate float32 // Smoothed turning speed in radians per second, for leaning
 
 	// State
 	State State
@@ -106,22 +155,109 @@ type Unit struct {
 	Health    float32
 	MaxHealth float32
 
+	// Seconds left emerging from the base (StateSpawning)
+	SpawnTimer float32
+
 	// Combat
 	AttackCooldown float32
-	Target         *Unit // Current attack target
+	Target         *Unit      // Current attack target
+	AimPoint       rl.Vector3 // Where shots at the target are aimed (led for moving targets)
+	Kills          int        // Enemy units destroyed, earns Rank
+
+	// Seconds until point defense can fire again
+	InterceptCooldown float32
+
+	// Seconds left that firing gives the unit's position away through cover
+	RevealTimer float32
+
+	// Healing built up by a supply unit, handed out in whole hit points
+	supplyCharge float32
+
+	// Shots fired since the manager last collected them
+	shots []Shot
 
 	// AI
 	Objective    rl.Vector3   // Where the unit is trying to go
 	HasObjective bool
+	ForceMove    bool         // Ignore enemies until the objective is reached
 	Path         []rl.Vector2 // Pathfinding result (X, Z)
 	PathIndex    int
 
 	// Order-specific data
-	OrderTarget  rl.Vector3 // Target position for orders
-	PatrolCenter rl.Vector3 // Center of patrol area
-	PatrolRadius float32
+	OrderTarget    rl.Vector3 // Target position for orders
+	PatrolCenter   rl.Vector3 // Center of patrol area
+	PatrolRadius   float32
+	GuardFacing    float32 // Rotation held by idle defenders
+	HasGuardFacing bool
+	GuardTargetID  uint32     // Ward escorted under OrderGuard (MechID for the mech)
+	GuardOffset    rl.Vector3 // Where the guard keeps station relative to its ward
+	wardPosition   rl.Vector3 // Ward's position, refreshed by the manager
+
+	// Set by the manager's combat pass each frame: hold still to fire, or
+	// back away from a target inside the standoff distance
+	holdPosition bool
+	backingOff   bool
+
+	// Order set aside while the unit fights, resumed once the fight is over
+	interrupted       bool
+	interruptedOrder  Order
+	interruptedTarget rl.Vector3
+	engagedFrom       rl.Vector3 // Where the unit broke off to fight
+	reengageTimer     float32    // Seconds after giving up a chase before it can break off again
+
+	// Player selection
+	Selected bool
+
+	// Shared alliance table, set by the manager on spawn
+	alliances *Alliances
+
+	// Simulation randomness shared through the manager (nil = unseeded)
+	rng *rand.Rand
+
+	// Damage taken since the last ConsumeDamage, for combat feedback
+	damageTaken float32
+
+	// Stuck detection
+	posHistory  [stuckHistorySize]rl.Vector3 // Recent positions, sampled periodically
+	historyLen  int
+	sampleTimer float32
+	StuckNudges int  // Times this unit has been nudged loose
+	NeedsRepath bool // Set when stuck, the manager recomputes the path
 }
 
+// Stuck detection tuning
+const (
+	stuckSampleInterval = 0.25 // Seconds between position samples
+	stuckHistorySize    = 6    // Samples in the window (~1.5 seconds)
+	stuckMinProgress    = 0.3  // World units a moving unit must cover over the window
+	stuckNudgeDistance  = 0.6  // How far a stuck unit is pushed sideways
+)
+
+// SpawnDuration is how long a freshly produced unit takes to emerge
+const SpawnDuration = 0.8
+
+// FireRevealTime is how long a unit that fires stays spotted through cover
+const FireRevealTime = 2.0
+
+// MechID is the guard target ID that refers to the player's mech.
+// Unit IDs start at 1 so it never collides with a unit.
+const MechID uint32 = 0
+
+// Guard tuning
+const (
+	guardFollowDistance = 2.5 // How far from its ward a guard keeps station
+	guardStationSlack   = 0.5 // Distance from station a guard tolerates before moving
+	guardThreatRadius   = 8.0 // Enemies this close to the ward are engaged
+)
+
+// orderLeash is how far a unit chases an enemy from where it broke off its
+// order before giving up and going back to it
+const orderLeash = 10.0
+
+// reengageDelay is how long a unit that gave up a chase keeps to its order
+// before it can break off to fight again, so it can't chase on in short hops
+const reengageDelay = 4.0
+
 // New creates a new unit of the specified type
 func New(id uint32, unitType UnitType, team Team, pos rl.Vector3) *Unit {
 	cfg := GetConfig(unitType)
@@ -144,14 +280,38 @@ func (u *Unit) Update(dt float32) {
 		return
 	}
 
+	// Hold in place until fully emerged
+	if u.State == StateSpawning {
+		u.SpawnTimer -= dt
+		if u.SpawnTimer <= 0 {
+			u.SpawnTimer = 0
+			u.State = StateIdle
+		}
+		return
+	}
+
 	// Update attack cooldown
 	if u.AttackCooldown > 0 {
 		u.AttackCooldown -= dt
 	}
+	if u.InterceptCooldown > 0 {
+		u.InterceptCooldown -= dt
+	}
+	if u.RevealTimer > 0 {
+		u.RevealTimer -= dt
+	}
+	if u.reengageTimer > 0 {
+		u.reengageTimer -= dt
+	}
 
 	// Execute order-based behavior if we have an order
+	prevRotation := u.Rotation
 	if u.Order != OrderNone {
 		u.executeOrder(dt)
+	} else if u.backingOff && u.Target != nil {
+		u.backAwayFrom(u.Target.Position, dt)
+	} else if u.holdPosition {
+		u.Velocity = rl.Vector3{}
 	} else if u.HasObjective && len(u.Path) > 0 && u.PathIndex < len(u.Path) {
 		// Movement along path
 		u.updateMovement(dt)
@@ -161,7 +321,7 @@ func (u *Unit) Update(dt float32) {
 	}
 
 	// Update state based on movement
-	speed := float32(math.Sqrt(float64(u.Velocity.X*u.Velocity.X + u.Velocity.Z*u.Velocity.Z)))
+	speed := u.Speed()
 	if u.State != StateAttacking && u.State != StateCapturing {
 		if speed > 0.1 {
 			u.State = StateMoving
@@ -169,6 +329,69 @@ func (u *Unit) Update(dt float32) {
 			u.State = StateIdle
 		}
 	}
+
+	u.TurnRate = graphics.TrackTurnRate(u.TurnRate, normalizeAngle(u.Rotation-prevRotation), dt)
+	u.updateStuck(dt, speed)
+}
+
+// updateStuck watches for units that keep trying to move without getting
+// anywhere and nudges them loose
+func (u *Unit) updateStuck(dt, speed float32) {
+	// Only units actively trying to move can be stuck, idle and
+	// defending units stand still on purpose
+	if u.State != StateMoving || speed <= 0.1 {
+		u.resetStuck()
+		return
+	}
+
+	u.sampleTimer -= dt
+	if u.sampleTimer > 0 {
+		return
+	}
+	u.sampleTimer = stuckSampleInterval
+
+	// Shift the window and record the newest position
+	if u.historyLen < stuckHistorySize {
+		u.historyLen++
+	} else {
+		copy(u.posHistory[:], u.posHistory[1:])
+	}
+	u.posHistory[u.historyLen-1] = u.Position
+
+	if u.historyLen < stuckHistorySize {
+		return // Window not full yet
+	}
+	if u.DistanceToPoint(u.posHistory[0]) >= stuckMinProgress {
+		return
+	}
+
+	u.nudge(speed)
+}
+
+// nudge pushes a stuck unit sideways, alternating sides, and asks for a new path
+func (u *Unit) nudge(speed float32) {
+	side := float32(1)
+	if u.StuckNudges%2 == 1 {
+		side = -1
+	}
+	// Perpendicular to the direction the unit is trying to go
+	u.Position.X += -u.Velocity.Z / speed * stuckNudgeDistance * side
+	u.Position.Z += u.Velocity.X / speed * stuckNudgeDistance * side
+
+	u.StuckNudges++
+	u.NeedsRepath = u.HasObjective
+	u.resetStuck()
+}
+
+// resetStuck clears the position history
+func (u *Unit) resetStuck() {
+	u.historyLen = 0
+	u.sampleTimer = 0
+}
+
+// IsStuck returns true if the unit was nudged and is waiting for a new path
+func (u *Unit) IsStuck() bool {
+	return u.NeedsRepath
 }
 
 func (u *Unit) executeOrder(dt float32) {
@@ -188,6 +411,18 @@ func (u *Unit) executeOrder(dt float32) {
 
 	case OrderPatrolArea:
 		u.executePatrolOrder(dt)
+
+	case OrderGuard:
+		u.executeGuardOrder(dt)
+
+	case OrderGarrison:
+		// Walk right up to the base, the base system takes the unit in
+		// once it's close
+		if u.HasActivePath() {
+			u.updateMovement(dt)
+		} else {
+			u.moveToward(u.OrderTarget, dt)
+		}
 	}
 }
 
@@ -196,17 +431,17 @@ func (u *Unit) executeAttackOrder(dt float32) {
 	if u.moveTowardOrder(u.OrderTarget, dt) {
 		// Reached target, attack if we have a target
 		if u.Target != nil && u.AttackCooldown <= 0 {
-			u.State = StateAttacking
-			u.Target.TakeDamage(u.Config.AttackDamage)
-			u.AttackCooldown = 1.0 / u.Config.AttackRate
+			u.attack(u.Target)
 		}
 	}
 }
 
 func (u *Unit) executeCaptureOrder(dt float32) {
-	// Move toward capture target
-	u.moveTowardOrder(u.OrderTarget, dt)
-	// Capture logic handled by base system checking infantry in range
+	// Move toward capture target, then hold it. Capture logic handled by
+	// base system checking infantry in range
+	if u.moveTowardOrder(u.OrderTarget, dt) {
+		u.State = StateCapturing
+	}
 }
 
 func (u *Unit) executeDefendOrder(dt float32) {
@@ -217,7 +452,11 @@ func (u *Unit) executeDefendOrder(dt float32) {
 	} else {
 		u.Velocity = rl.Vector3{}
 		u.State = StateIdle
-		// Attack logic handled externally
+		// Attack logic handled externally, turning to engage; once there's
+		// nothing to shoot at, swing back to the guard facing
+		if u.HasGuardFacing && (u.Target == nil || !u.IsInRange(u.Target)) {
+			u.Rotation = lerpAngle(u.Rotation, u.GuardFacing, u.Config.TurnSpeed*dt)
+		}
 	}
 }
 
@@ -232,8 +471,8 @@ func (u *Unit) executePatrolOrder(dt float32) {
 		// Wander within patrol area
 		if u.State == StateIdle {
 			// Pick a new random point in patrol area
-			angle := float32(math.Pi * 2.0 * float64(rl.GetRandomValue(0, 100)) / 100.0)
-			radius := float32(rl.GetRandomValue(0, int32(u.PatrolRadius*100))) / 100.0
+			angle := float32(math.Pi * 2.0 * u.randFloat())
+			radius := u.PatrolRadius * float32(u.randFloat())
 			u.OrderTarget = rl.Vector3{
 				X: u.PatrolCenter.X + radius*float32(math.Cos(float64(angle))),
 				Y: 0,
@@ -244,6 +483,26 @@ func (u *Unit) executePatrolOrder(dt float32) {
 	}
 }
 
+func (u *Unit) executeGuardOrder(dt float32) {
+	// Intercept whatever threatens the ward, attack handled externally
+	if u.Target != nil && u.Target.IsTargetable() {
+		if !u.IsInRange(u.Target) {
+			u.moveToward(u.Target.Position, dt)
+		} else {
+			u.Velocity = rl.Vector3{}
+		}
+		return
+	}
+
+	// No threat, keep station beside the ward
+	if u.DistanceToPoint(u.OrderTarget) > guardStationSlack {
+		u.moveToward(u.OrderTarget, dt)
+	} else {
+		u.Velocity = rl.Vector3{}
+		u.State = StateIdle
+	}
+}
+
 // moveTowardOrder moves the unit toward a target position for order execution
 // Returns true if within attack range
 func (u *Unit) moveTowardOrder(target rl.Vector3, dt float32) bool {
@@ -251,7 +510,7 @@ func (u *Unit) moveTowardOrder(target rl.Vector3, dt float32) bool {
 	dz := target.Z - u.Position.Z
 	dist := float32(math.Sqrt(float64(dx*dx + dz*dz)))
 
-	if dist < u.Config.AttackRange {
+	if dist < u.EngageDistance() {
 		u.Velocity = rl.Vector3{}
 		u.State = StateIdle
 		return true
@@ -338,6 +597,28 @@ func (u *Unit) moveToward(target rl.Vector3, dt float32) {
 	u.Position.Z += u.Velocity.Z * dt
 }
 
+// backOffSpeed is the fraction of full speed a unit reverses at
+const backOffSpeed = 0.6
+
+// backAwayFrom reverses straight away from a position without turning, so
+// the unit keeps its guns on what it's backing away from
+func (u *Unit) backAwayFrom(pos rl.Vector3, dt float32) {
+	dx := u.Position.X - pos.X
+	dz := u.Position.Z - pos.Z
+	dist := float32(math.Sqrt(float64(dx*dx + dz*dz)))
+	if dist < 0.01 {
+		// Right on top of it, back out the way we're facing
+		dx = -float32(math.Sin(float64(u.Rotation)))
+		dz = -float32(math.Cos(float64(u.Rotation)))
+		dist = 1
+	}
+
+	speed := u.Config.Speed * backOffSpeed
+	u.Velocity = rl.Vector3{X: dx / dist * speed, Z: dz / dist * speed}
+	u.Position.X += u.Velocity.X * dt
+	u.Position.Z += u.Velocity.Z * dt
+}
+
 // SetObjective sets a destination for the unit
 func (u *Unit) SetObjective(pos rl.Vector3) {
 	u.Objective = pos
@@ -360,20 +641,58 @@ func (u *Unit) SetPath(path []rl.Vector2) {
 	u.PathIndex = 0
 }
 
-// TakeDamage applies damage to the unit
+// attack fires on a target. Units with projectiles fire a shot at where the
+// target will be, the combat system resolves the hit. Anything else hits
+// instantly, crediting a kill if the hit destroys the target.
+func (u *Unit) attack(target *Unit) {
+	if u.FiresProjectiles() {
+		u.shots = append(u.shots, u.FireAt(ComputeLeadPoint(u.Position, target.Position, target.Velocity, u.Config.ProjectileSpeed)))
+		return
+	}
+
+	u.State = StateAttacking
+	wasAlive := !target.IsDead()
+	target.TakeDamage(u.Config.AttackDamage * DamageMultiplier(u.Config.Type, target.Config.Type))
+	if wasAlive && target.IsDead() {
+		u.Kills++
+	}
+	u.AttackCooldown = 1.0 / u.Config.AttackRate
+	u.RevealTimer = FireRevealTime
+}
+
+// TakeDamage applies damage to the unit, reduced by armor and rounded to
+// whole hit points
 func (u *Unit) TakeDamage(amount float32) {
 	// Apply armor reduction
-	actualDamage := amount * (1.0 - u.Config.Armor)
+	actualDamage := HitPoints(amount * (1.0 - u.Config.Armor))
 	u.Health -= actualDamage
+	u.damageTaken += actualDamage
 	if u.Health <= 0 {
 		u.Health = 0
 		u.State = StateDead
 	}
 }
 
-// Heal restores health to the unit
+// ConsumeDamage returns the damage taken since the last call and resets it
+func (u *Unit) ConsumeDamage() float32 {
+	d := u.damageTaken
+	u.damageTaken = 0
+	return d
+}
+
+// ScaleHealth multiplies max health by a handicap factor.
+// Current health is scaled with it so the unit stays at the same percentage.
+func (u *Unit) ScaleHealth(mult float32) {
+	if mult <= 0 {
+		return
+	}
+	u.MaxHealth = HitPoints(u.MaxHealth * mult)
+	u.Health = HitPoints(u.Health * mult)
+}
+
+// Heal restores health to the unit in whole hit points
 func (u *Unit) Heal(amount float32) {
-	u.Health += amount
+	u.Health += HitPoints(amount)
 	if u.Health > u.MaxHealth {
 		u.Health = u.MaxHealth
 	}
@@ -391,6 +710,11 @@ func (u *Unit) DistanceTo(other *Unit) float32 {
 	return float32(math.Sqrt(float64(dx*dx + dz*dz)))
 }
 
+// angleTo returns the Y rotation that faces a point
+func (u *Unit) angleTo(pos rl.Vector3) float32 {
+	return float32(math.Atan2(float64(pos.X-u.Position.X), float64(pos.Z-u.Position.Z)))
+}
+
 // DistanceToPoint returns the distance to a point
 func (u *Unit) DistanceToPoint(pos rl.Vector3) float32 {
 	dx := pos.X - u.Position.X
@@ -398,23 +722,129 @@ func (u *Unit) DistanceToPoint(pos rl.Vector3) float32 {
 	return float32(math.Sqrt(float64(dx*dx + dz*dz)))
 }
 
+// minSightRange lets units with a short or no attack range still see
+// around themselves
+const minSightRange = 5.0
+
+// SightRange returns how far the unit sees: twice its attack range, the
+// distance it picks targets from, but never less than minSightRange
+func (u *Unit) SightRange() float32 {
+	if r := u.Config.AttackRange * 2; r > minSightRange {
+		return r
+	}
+	return minSightRange
+}
+
+// BeginSpawn starts the unit's emergence, during which it can't act or be
+// targeted
+func (u *Unit) BeginSpawn(duration float32) {
+	if duration <= 0 {
+		return
+	}
+	u.State = StateSpawning
+	u.SpawnTimer = duration
+}
+
+// IsSpawning returns true while the unit is still emerging
+func (u *Unit) IsSpawning() bool {
+	return u.State == StateSpawning
+}
+
+// SpawnProgress returns how far the unit has emerged, 0 to 1
+func (u *Unit) SpawnProgress() float32 {
+	if !u.IsSpawning() {
+		return 1
+	}
+	return 1 - u.SpawnTimer/SpawnDuration
+}
+
+// IsTargetable returns true if the unit can be shot at
+func (u *Unit) IsTargetable() bool {
+	return !u.IsDead() && !u.IsSpawning()
+}
+
 // CanAttack returns true if this unit can attack the target
 func (u *Unit) CanAttack(target *Unit) bool {
-	if target == nil || target.IsDead() {
+	if target == nil || !target.IsTargetable() {
 		return false
 	}
-	if u.Team == target.Team {
+	if u.IsAlliedWith(target.Team) {
 		return false
 	}
 	// For now, all units are ground units
 	return u.Config.CanAttackGround
 }
 
+// CanIntercept returns true if the unit's point defense is ready to shoot
+// down a projectile
+func (u *Unit) CanIntercept() bool {
+	return u.Config.InterceptRadius > 0 && u.InterceptCooldown <= 0 && u.IsTargetable()
+}
+
+// Intercept spends the unit's point defense on a projectile
+func (u *Unit) Intercept() {
+	if u.Config.InterceptRate > 0 {
+		u.InterceptCooldown = 1.0 / u.Config.InterceptRate
+	}
+}
+
+// IsAlliedWith returns true if the unit is on the same side as a team
+func (u *Unit) IsAlliedWith(team Team) bool {
+	return u.alliances.AreAllied(u.Team, team)
+}
+
 // IsInRange returns true if the target is within attack range
 func (u *Unit) IsInRange(target *Unit) bool {
 	return u.DistanceTo(target) <= u.Config.AttackRange
 }
 
+// SetTeam moves the unit to another side. It drops its order, any fight or
+// escort, and its selection, since those were the old side's, and the
+// target too if that's now a friend. Use Manager.SetTeam to also make
+// other units stop shooting at it.
+func (u *Unit) SetTeam(team Team) {
+	if team == u.Team {
+		return
+	}
+
+	u.Team = team
+	u.Order = OrderNone
+	u.interrupted = false
+	u.GuardTargetID = 0
+	u.HasGuardFacing = false
+	u.ForceMove = false
+	u.Selected = false
+	u.ClearObjective()
+	if u.Target != nil && !u.CanAttack(u.Target) {
+		u.Target = nil
+	}
+}
+
+// IsRevealed returns true if the unit fired recently enough that cover
+// doesn't hide it
+func (u *Unit) IsRevealed() bool {
+	return u.RevealTimer > 0
+}
+
+// EngageDistance returns how close the unit closes on a target before
+// holding to fire
+func (u *Unit) EngageDistance() float32 {
+	if u.Config.EngageDistance > 0 {
+		return u.Config.EngageDistance
+	}
+	return u.Config.AttackRange
+}
+
+// Speed returns how fast the unit is moving across the ground
+func (u *Unit) Speed() float32 {
+	return float32(math.Sqrt(float64(u.Velocity.X*u.Velocity.X + u.Velocity.Z*u.Velocity.Z)))
+}
+
+// HasActivePath returns true if the unit is still following a path
+func (u *Unit) HasActivePath() bool {
+	return u.HasObjective && len(u.Path) > 0 && u.PathIndex < len(u.Path)
+}
+
 // GetForward returns the forward direction vector
 func (u *Unit) GetForward() rl.Vector3 {
 	return rl.Vector3{
@@ -426,10 +856,17 @@ func (u *Unit) GetForward() rl.Vector3 {
 
 // Transport methods
 
+// CanBePickedUp returns true if a transport on a team may pick the unit up.
+// Attacking or capturing units are eligible, carrying pauses them.
+func (u *Unit) CanBePickedUp(team Team) bool {
+	return u.Team == team && !u.IsDead() && !u.IsCarried() && !u.IsSpawning()
+}
+
 // PickUp marks the unit as being carried
 func (u *Unit) PickUp() {
 	u.State = StateBeingCarried
 	u.Velocity = rl.Vector3{}
+	u.Target = nil
 	u.ClearObjective()
 }
 
@@ -447,10 +884,15 @@ func (u *Unit) IsCarried() bool {
 
 // SetOrder sets the unit's order with a target position
 func (u *Unit) SetOrder(order Order, target rl.Vector3) {
+	u.interrupted = false
+	if u.State == StateCapturing {
+		u.State = StateIdle
+	}
 	u.Order = order
 	u.OrderTarget = target
 	u.HasObjective = true
 	u.Objective = target
+	u.HasGuardFacing = false
 
 	if order == OrderPatrolArea {
 		u.PatrolCenter = target
@@ -458,6 +900,162 @@ func (u *Unit) SetOrder(order Order, target rl.Vector3) {
 	}
 }
 
+// interruptsForCombat returns true if the unit's order is set aside to fight
+// enemies it meets. Defenders, patrols, and guards fight as part of their
+// orders.
+func (u *Unit) interruptsForCombat() bool {
+	switch u.Order {
+	case OrderAttackHQ, OrderAttackNearest, OrderCaptureOutpost:
+		return true
+	default:
+		return false
+	}
+}
+
+// interruptOrder sets the unit's order aside so it can fight its target
+func (u *Unit) interruptOrder() {
+	if u.interrupted || u.reengageTimer > 0 || !u.interruptsForCombat() {
+		return
+	}
+	u.interrupted = true
+	u.interruptedOrder = u.Order
+	u.interruptedTarget = u.OrderTarget
+	u.engagedFrom = u.Position
+	u.Order = OrderNone
+	u.ClearObjective()
+}
+
+// resumeOrder goes back to the order set aside for a fight
+func (u *Unit) resumeOrder() {
+	if !u.interrupted {
+		return
+	}
+	u.SetOrder(u.interruptedOrder, u.interruptedTarget)
+}
+
+// pastLeash returns true if the unit has chased a fight too far from its
+// interrupted order
+func (u *Unit) pastLeash() bool {
+	return u.interrupted && u.DistanceToPoint(u.engagedFrom) > orderLeash
+}
+
+// giveUpChase drops the fight and goes back to the interrupted order,
+// holding to it for reengageDelay
+func (u *Unit) giveUpChase() {
+	u.Target = nil
+	u.resumeOrder()
+	u.reengageTimer = reengageDelay
+}
+
+// OrderLine returns the order the unit is carrying out and the point it's
+// working toward: the enemy it's shooting for attack orders and fights
+// that interrupted an order, the ward for guards, the patrol center for
+// patrols, and the order target otherwise. False for units with no order.
+func (u *Unit) OrderLine() (Order, rl.Vector3, bool) {
+	order := u.Order
+	if u.interrupted {
+		order = u.interruptedOrder
+	}
+	if order == OrderNone {
+		return OrderNone, rl.Vector3{}, false
+	}
+
+	if u.Target != nil && !u.Target.IsDead() && (u.interrupted || order == OrderAttackHQ || order == OrderAttackNearest) {
+		return order, u.Target.Position, true
+	}
+
+	switch order {
+	case OrderGuard:
+		return order, u.wardPosition, true
+	case OrderPatrolArea:
+		return order, u.PatrolCenter, true
+	default:
+		return order, u.OrderTarget, true
+	}
+}
+
+// InterruptedOrder returns the order the unit will resume after its
+// current fight, false if it isn't fighting in place of an order
+func (u *Unit) InterruptedOrder() (Order, bool) {
+	return u.interruptedOrder, u.interrupted
+}
+
+// Guard orders the unit to escort a ward at its position, keeping station
+// on the side of the ward the unit is on now
+func (u *Unit) Guard(wardID uint32, wardPos rl.Vector3) {
+	dx := u.Position.X - wardPos.X
+	dz := u.Position.Z - wardPos.Z
+	dist := float32(math.Sqrt(float64(dx*dx + dz*dz)))
+	offset := rl.Vector3{Z: -guardFollowDistance} // Trail behind by default
+	if dist > 0.1 {
+		offset = rl.Vector3{X: dx / dist * guardFollowDistance, Z: dz / dist * guardFollowDistance}
+	}
+
+	u.SetOrder(OrderGuard, rl.Vector3{X: wardPos.X + offset.X, Z: wardPos.Z + offset.Z})
+	u.GuardTargetID = wardID
+	u.GuardOffset = offset
+	u.wardPosition = wardPos
+}
+
+// followWard moves the guard's station along with its ward
+func (u *Unit) followWard(wardPos rl.Vector3) {
+	u.wardPosition = wardPos
+	u.OrderTarget = rl.Vector3{X: wardPos.X + u.GuardOffset.X, Z: wardPos.Z + u.GuardOffset.Z}
+}
+
+// SetGuardFacing sets the rotation a defending unit holds while idle
+func (u *Unit) SetGuardFacing(rotation float32) {
+	u.GuardFacing = rotation
+	u.HasGuardFacing = true
+}
+
+// GuardToward makes a defending unit face a position while idle
+func (u *Unit) GuardToward(pos rl.Vector3) {
+	u.SetGuardFacing(u.angleTo(pos))
+}
+
+// CanFollowOrder returns true if the unit's type can carry out an order
+func (u *Unit) CanFollowOrder(order Order) bool {
+	switch order {
+	case OrderAttackHQ, OrderAttackNearest:
+		return u.Config.CanAttackGround || u.Config.CanAttackAir
+	case OrderCaptureOutpost:
+		return u.Config.CanCapture
+	case OrderDefendPosition, OrderPatrolArea:
+		return true
+	case OrderGuard:
+		return u.Config.CanAttackGround || u.Config.CanAttackAir
+	case OrderGarrison:
+		return u.Config.CanCapture
+	default:
+		return false
+	}
+}
+
+// ValidOrders returns the orders at least one of the units can follow,
+// in order-enum order
+func ValidOrders(units []*Unit) []Order {
+	valid := make([]Order, 0, OrderGarrison)
+	for order := OrderAttackHQ; order <= OrderGarrison; order++ {
+		for _, u := range units {
+			if u.CanFollowOrder(order) {
+				valid = append(valid, order)
+				break
+			}
+		}
+	}
+	return valid
+}
+
+// OrderName returns the display name for an order
+func OrderName(order Order) string {
+	names := OrderNames()
+	if int(order) < len(names) {
+		return names[order]
+	}
+	return "Unknown"
+}
+
 // GetOrderName returns the display name for the unit's current order
 func (u *Unit) GetOrderName() string {
 	names := OrderNames()
@@ -467,8 +1065,66 @@ func (u *Unit) GetOrderName() string {
 	return "Unknown"
 }
 
+// ComputeLeadPoint returns where to aim so a projectile fired from shooterPos
+// meets a target moving at a constant velocity. Falls back to the target's
+// current position for instant-hit weapons or when the target is too fast
+// to intercept.
+func ComputeLeadPoint(shooterPos, targetPos, targetVel rl.Vector3, projSpeed float32) rl.Vector3 {
+	if projSpeed <= 0 {
+		return targetPos
+	}
+
+	// Solve |d + v*t| = s*t for the earliest positive t
+	dx := targetPos.X - shooterPos.X
+	dy := targetPos.Y - shooterPos.Y
+	dz := targetPos.Z - shooterPos.Z
+
+	a := targetVel.X*targetVel.X + targetVel.Y*targetVel.Y + targetVel.Z*targetVel.Z - projSpeed*projSpeed
+	b := 2 * (dx*targetVel.X + dy*targetVel.Y + dz*targetVel.Z)
+	c := dx*dx + dy*dy + dz*dz
+
+	var t float32
+	if float32(math.Abs(float64(a))) < 1e-6 {
+		// Target as fast as the projectile, equation is linear
+		if b >= 0 {
+			return targetPos
+		}
+		t = -c / b
+	} else {
+		disc := b*b - 4*a*c
+		if disc < 0 {
+			return targetPos // Can't catch it
+		}
+		sqrtDisc := float32(math.Sqrt(float64(disc)))
+		t1 := (-b - sqrtDisc) / (2 * a)
+		t2 := (-b + sqrtDisc) / (2 * a)
+		t = t1
+		if t <= 0 || (t2 > 0 && t2 < t) {
+			t = t2
+		}
+	}
+	if t <= 0 {
+		return targetPos
+	}
+
+	return rl.Vector3{
+		X: targetPos.X + targetVel.X*t,
+		Y: targetPos.Y + targetVel.Y*t,
+		Z: targetPos.Z + targetVel.Z*t,
+	}
+}
+
 // Helper functions
 
+// randFloat returns a random number in [0, 1) from the manager's seeded
+// source, falling back to the global source for units outside a manager
+func (u *Unit) randFloat() float64 {
+	if u.rng == nil {
+		return rand.Float64()
+	}
+	return u.rng.Float64()
+}
+
 func lerpAngle(a, b, t float32) float32 {
 	a = normalizeAngle(a)
 	b = normalizeAngle(b)
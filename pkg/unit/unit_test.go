@@ -0,0 +1,151 @@
+package unit
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestLeashedUnitKeepsToOrder(t *testing.T) {
+	m := NewManager(10)
+	u := m.Spawn(TypeTank, TeamPlayer, rl.NewVector3(0, 0, 0))
+	u.SpawnTimer = 0
+	u.State = StateIdle
+	u.SetOrder(OrderAttackHQ, rl.NewVector3(0, 0, 100))
+
+	// Break off to fight, then chase past the leash
+	u.interruptOrder()
+	if !u.interrupted {
+		t.Fatal("attack order wasn't set aside for a fight")
+	}
+	u.Position = rl.NewVector3(orderLeash+1, 0, 0)
+	m.updateAI(0.1)
+	if u.interrupted || u.Order != OrderAttackHQ {
+		t.Fatal("unit past its leash didn't go back to its order")
+	}
+
+	// An enemy close by right away doesn't start a fresh chase
+	u.interruptOrder()
+	if u.interrupted {
+		t.Fatal("unit broke off again straight after giving up a chase")
+	}
+
+	for i := 0; i < int(reengageDelay/0.1)+1; i++ {
+		u.Update(0.1)
+	}
+	u.interruptOrder()
+	if !u.interrupted {
+		t.Error("unit can't break off to fight once the delay is over")
+	}
+}
+
+func TestSetTeamClearsFriendlyTargets(t *testing.T) {
+	m := NewManager(10)
+	enemy := m.Spawn(TypeInfantry, TeamEnemy, rl.NewVector3(0, 0, 0))
+	player := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(2, 0, 0))
+	other := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(4, 0, 0))
+	for _, u := range m.GetUnits() {
+		u.SpawnTimer, u.State = 0, StateIdle
+	}
+	enemy.Target = player
+	player.Target = enemy
+	other.Target = enemy
+
+	// The player's unit defects, it and the enemy are now friends
+	m.SetTeam(player, TeamEnemy)
+	if enemy.Target != nil {
+		t.Error("enemy still targets a unit that joined its side")
+	}
+	if player.Target != nil {
+		t.Error("defector still targets its new side")
+	}
+	if other.Target != enemy {
+		t.Error("unit whose target is still an enemy lost it")
+	}
+}
+
+func TestHealthHandicapScalesSpawns(t *testing.T) {
+	m := NewManager(10)
+	m.SetHealthMultiplier(TeamEnemy, 1.5)
+
+	for _, ut := range []UnitType{TypeInfantry, TypeTank} {
+		u := m.Spawn(ut, TeamEnemy, rl.NewVector3(0, 0, 0))
+		if want := GetConfig(ut).MaxHealth * 1.5; u.MaxHealth != want || u.Health != u.MaxHealth {
+			t.Errorf("%v spawned at %v/%v, want full at %v", ut, u.Health, u.MaxHealth, want)
+		}
+	}
+	if u := m.Spawn(TypeInfantry, TeamPlayer, rl.NewVector3(0, 0, 0)); u.MaxHealth != GetConfig(TypeInfantry).MaxHealth {
+		t.Errorf("unhandicapped team's infantry has %v max health", u.MaxHealth)
+	}
+}
+
+func TestOrderHaltsAtEngageDistance(t *testing.T) {
+	u := New(1, TypeTank, TeamPlayer, rl.NewVector3(0, 0, 0))
+	u.SpawnTimer = 0
+	u.State = StateIdle
+	target := rl.NewVector3(0, 0, 20)
+	u.SetOrder(OrderAttackHQ, target)
+
+	const dt = 0.05
+	for i := 0; i < 400; i++ {
+		u.Update(dt)
+	}
+
+	dist := u.DistanceToPoint(target)
+	if dist > u.EngageDistance() || dist < u.EngageDistance()-u.Config.Speed*dt {
+		t.Errorf("tank stopped %.2f from its target, want just inside %.2f", dist, u.EngageDistance())
+	}
+	if u.Speed() > 0.1 {
+		t.Error("tank still moving once at the engagement distance")
+	}
+}
+
+func TestStandoffBacksAwayFromCloseTarget(t *testing.T) {
+	m := NewManager(10)
+	enemy := m.Spawn(TypeInfantry, TeamEnemy, rl.NewVector3(0, 0, 1))
+	tank := m.Spawn(TypeTank, TeamPlayer, rl.NewVector3(0, 0, 0))
+	for _, u := range []*Unit{enemy, tank} {
+		u.SpawnTimer = 0
+		u.State = StateIdle
+	}
+	enemy.MaxHealth = 1e6
+	enemy.Health = 1e6
+	tank.Target = enemy
+
+	const dt = 0.05
+	for i := 0; i < 200; i++ {
+		m.updateCombat(dt)
+		tank.Update(dt)
+	}
+
+	dist := tank.DistanceTo(enemy)
+	if dist < tank.Config.StandoffDistance {
+		t.Fatalf("tank sat %.2f from its target, inside its %.2f standoff", dist, tank.Config.StandoffDistance)
+	}
+	if dist > tank.EngageDistance() {
+		t.Errorf("tank backed off to %.2f, past its %.2f engagement distance", dist, tank.EngageDistance())
+	}
+}
+
+func TestZeroStandoffHoldsClose(t *testing.T) {
+	m := NewManager(10)
+	enemy := m.Spawn(TypeInfantry, TeamEnemy, rl.NewVector3(0, 0, 1))
+	tank := m.Spawn(TypeTank, TeamPlayer, rl.NewVector3(0, 0, 0))
+	for _, u := range []*Unit{enemy, tank} {
+		u.SpawnTimer = 0
+		u.State = StateIdle
+	}
+	enemy.MaxHealth = 1e6
+	enemy.Health = 1e6
+	tank.Config.StandoffDistance = 0
+	tank.Target = enemy
+
+	for i := 0; i < 40; i++ {
+		m.updateCombat(0.05)
+		tank.Update(0.05)
+	}
+
+	if dist := tank.DistanceTo(enemy); dist > 1.01 {
+		t.Errorf("unit with no standoff backed away to %.2f", dist)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+)
+
+// GameState controls which part of the game loop is active
+type GameState int
+
+const (
+	StateMainMenu GameState = iota // Title screen, waiting to start
+	StatePlaying                   // Simulation running
+	StatePaused                    // Simulation frozen, resumable
+	StateGameOver                  // An HQ fell, simulation frozen
+)
+
+// String returns the display name for a game state
+func (s GameState) String() string {
+	switch s {
+	case StateMainMenu:
+		return "Main Menu"
+	case StatePlaying:
+		return "Playing"
+	case StatePaused:
+		return "Paused"
+	case StateGameOver:
+		return "Game Over"
+	default:
+		return "Unknown"
+	}
+}
+
+// restart throws away the current match and starts a fresh one
+func (g *Game) restart() {
+	g.Reset()
+	g.state = StatePlaying
+}
+
+// drawStateOverlay draws the screen for non-playing states
+func (g *Game) drawStateOverlay() {
+	switch g.state {
+	case StateMainMenu:
+		drawDimmer()
+		drawCenteredText(gameTitle, screenHeight/2-60, 60, rl.Gold)
+		drawCenteredText("Press ENTER to start", screenHeight/2+20, 25, rl.White)
+		drawCenteredText("F2: Graphics quality ("+g.graphicsQuality.String()+")", screenHeight/2+60, 18, rl.LightGray)
+		drawCenteredText("F3: Movement ("+g.mechInput.MoveMode.String()+" relative)", screenHeight/2+85, 18, rl.LightGray)
+		drawCenteredText("F4: Minimap ("+g.minimapModeText()+")", screenHeight/2+110, 18, rl.LightGray)
+		drawCenteredText("F5: Damage numbers ("+onOff(g.combatSystem.Config.ShowDamageText)+")", screenHeight/2+135, 18, rl.LightGray)
+		drawCenteredText("F6: Day/night cycle ("+onOff(g.dayCycle.Enabled)+")", screenHeight/2+160, 18, rl.LightGray)
+		drawCenteredText("F7: Frame timings ("+onOff(g.profiler.Enabled)+")", screenHeight/2+185, 18, rl.LightGray)
+		drawCenteredText(g.recordText(), screenHeight/2+225, 18, rl.Gold)
+
+	case StatePaused:
+		drawDimmer()
+		drawCenteredText("PAUSED", screenHeight/2-40, 50, rl.White)
+		drawCenteredText("Press P to resume", screenHeight/2+20, 20, rl.LightGray)
+		drawCenteredText("F2: Graphics quality ("+g.graphicsQuality.String()+")", screenHeight/2+50, 18, rl.LightGray)
+		drawCenteredText("F3: Movement ("+g.mechInput.MoveMode.String()+" relative)", screenHeight/2+75, 18, rl.LightGray)
+		drawCenteredText("F4: Minimap ("+g.minimapModeText()+")", screenHeight/2+100, 18, rl.LightGray)
+		drawCenteredText("F5: Damage numbers ("+onOff(g.combatSystem.Config.ShowDamageText)+")", screenHeight/2+125, 18, rl.LightGray)
+		drawCenteredText("F6: Day/night cycle ("+onOff(g.dayCycle.Enabled)+")", screenHeight/2+150, 18, rl.LightGray)
+		drawCenteredText("F7: Frame timings ("+onOff(g.profiler.Enabled)+")", screenHeight/2+175, 18, rl.LightGray)
+
+	case StateGameOver:
+		drawDimmer()
+		winText := "PLAYER 1 WINS!"
+		if g.loser == base.OwnerPlayer1 {
+			winText = "PLAYER 2 WINS!"
+		}
+		drawCenteredText(winText, screenHeight/2-40, 40, rl.Gold)
+		drawCenteredText("Press ENTER to play again", screenHeight/2+20, 20, rl.White)
+
+		// This match, then the running record
+		p1 := g.stats.Player1
+		matchText := fmt.Sprintf("%s | Built %d | Lost %d | Captured %d",
+			formatDuration(g.stats.Duration), p1.UnitsBuilt, p1.UnitsLost, p1.BasesCaptured)
+		drawCenteredText(matchText, screenHeight/2+60, 18, rl.LightGray)
+		drawCenteredText(g.recordText(), screenHeight/2+85, 18, rl.Gold)
+	}
+}
+
+// onOff describes a toggle setting
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// minimapModeText describes how the minimap is oriented
+func (g *Game) minimapModeText() string {
+	if g.minimap.RotateWithCamera {
+		return "rotating"
+	}
+	return "north-up"
+}
+
+// recordText describes Player 1's saved win/loss record
+func (g *Game) recordText() string {
+	s := g.statsSummary
+	return fmt.Sprintf("Record: %d W / %d L over %d matches", s.Wins, s.Losses, s.Matches)
+}
+
+// formatDuration formats seconds as m:ss
+func formatDuration(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+// drawDimmer darkens the frame behind an overlay
+func drawDimmer() {
+	rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 150})
+}
+
+// drawCenteredText draws text horizontally centered on the screen
+func drawCenteredText(text string, y int32, fontSize int32, color rl.Color) {
+	textWidth := rl.MeasureText(text, fontSize)
+	rl.DrawText(text, screenWidth/2-textWidth/2, y, fontSize, color)
+}
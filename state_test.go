@@ -0,0 +1,91 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"github.com/chazu/herzog-drei/pkg/base"
+)
+
+// newTestGame starts a match that saves its results under the test's
+// temporary directory
+func newTestGame(t *testing.T) *Game {
+	t.Helper()
+	g := NewGame()
+	g.statsPath = filepath.Join(t.TempDir(), "stats.json")
+	return g
+}
+
+func TestHQFallEndsMatch(t *testing.T) {
+	g := newTestGame(t)
+	g.state = StatePlaying
+
+	g.checkGameOver()
+	if g.state != StatePlaying {
+		t.Fatalf("match in state %v with both HQs standing", g.state)
+	}
+
+	hq := g.baseManager.GetHQ(base.OwnerPlayer2)
+	hq.TakeDamage(hq.MaxHealth * 10)
+	g.checkGameOver()
+	if g.state != StateGameOver || g.loser != base.OwnerPlayer2 {
+		t.Fatalf("after player 2's HQ fell: state %v, loser %v", g.state, g.loser)
+	}
+	if g.statsSummary.Matches != 1 || g.statsSummary.Wins != 1 {
+		t.Errorf("record is %+v, want one win", g.statsSummary)
+	}
+
+	// Nothing moves, fires, or earns while the game is over
+	positions := map[uint32]rl.Vector3{}
+	for _, u := range g.unitManager.GetUnits() {
+		positions[u.ID] = u.Position
+	}
+	credits := g.baseManager.GetCredits(base.OwnerPlayer1)
+	mechPos := g.playerMech.Position
+	for i := 0; i < 60; i++ {
+		g.Update()
+	}
+	if g.state != StateGameOver {
+		t.Fatalf("game left game over for %v without a restart", g.state)
+	}
+	for _, u := range g.unitManager.GetUnits() {
+		if pos, ok := positions[u.ID]; !ok || pos != u.Position {
+			t.Errorf("unit %d moved from %v to %v after game over", u.ID, pos, u.Position)
+		}
+	}
+	if got := g.baseManager.GetCredits(base.OwnerPlayer1); got != credits {
+		t.Errorf("credits went from %v to %v after game over", credits, got)
+	}
+	if g.playerMech.Position != mechPos {
+		t.Errorf("mech moved from %v to %v after game over", mechPos, g.playerMech.Position)
+	}
+}
+
+func TestRestartPlaysFreshMatch(t *testing.T) {
+	g := newTestGame(t)
+	g.state = StatePlaying
+	g.baseManager.GetHQ(base.OwnerPlayer1).TakeDamage(1e6)
+	g.baseManager.GetPlayer(base.OwnerPlayer2).Credits = 0
+	g.checkGameOver()
+	if g.state != StateGameOver {
+		t.Fatalf("state %v after player 1's HQ fell", g.state)
+	}
+
+	g.restart()
+	if g.state != StatePlaying || g.loser != base.OwnerNeutral {
+		t.Fatalf("after restart: state %v, loser %v", g.state, g.loser)
+	}
+	hq := g.baseManager.GetHQ(base.OwnerPlayer1)
+	if hq == nil || hq.Health != hq.MaxHealth {
+		t.Fatal("player 1's HQ isn't back at full health")
+	}
+	if got, want := g.baseManager.GetCredits(base.OwnerPlayer2), g.matchConfig.Player2.Credits; float64(got) != want {
+		t.Errorf("player 2 restarted with %v credits, want %v", got, want)
+	}
+	g.checkGameOver()
+	if g.state != StatePlaying {
+		t.Errorf("fresh match in state %v", g.state)
+	}
+}